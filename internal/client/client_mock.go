@@ -21,24 +21,17 @@ import (
 	"testing"
 
 	camelkapis "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
-	camelkv1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1"
 	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
-	"k8s.io/client-go/rest"
 	"knative.dev/client/pkg/util/mock"
 )
 
 // MockKameletClient is a combine of test object and recorder
 type MockKameletClient struct {
-	t        *testing.T
-	recorder *KameletRecorder
-}
-
-func (c *MockKameletClient) RESTClient() rest.Interface {
-	panic("should not be called")
+	t             *testing.T
+	recorder      *KameletRecorder
+	bindingClient *MockKameletBindingClient
 }
 
 // NewMockKameletClient returns a new mock instance which you need to record for
@@ -48,48 +41,21 @@ func NewMockKameletClient(t *testing.T, ns ...string) *MockKameletClient {
 		namespace = ns[0]
 	}
 	return &MockKameletClient{
-		t:        t,
-		recorder: &KameletRecorder{mock.NewRecorder(t, namespace)},
+		t:             t,
+		recorder:      &KameletRecorder{mock.NewRecorder(t, namespace)},
+		bindingClient: NewMockKameletBindingClient(t, namespace),
 	}
 }
 
-// Ensure that the interface is implemented
-var _ camelkv1alpha1.CamelV1alpha1Interface = &MockKameletClient{}
-var _ camelkv1alpha1.KameletInterface = &MockKameletClient{}
-
 // KameletRecorder is recorder for eventing objects
 type KameletRecorder struct {
 	r *mock.Recorder
 }
 
-func (c *MockKameletClient) CamelV1() camelkv1.CamelV1Interface {
-	panic("implement me")
-}
-
-func (c *MockKameletClient) CamelV1alpha1() *camelkv1alpha1.CamelV1alpha1Interface {
-	var i camelkv1alpha1.CamelV1alpha1Interface = c
-	return &i
-}
-
-func (c *MockKameletClient) GetScheme() *runtime.Scheme {
-	panic("implement me")
-}
-
-func (c *MockKameletClient) GetConfig() *rest.Config {
-	panic("implement me")
-}
-
-func (c *MockKameletClient) GetCurrentNamespace(kubeConfig string) (string, error) {
-	panic("implement me")
-}
-
-func (c *MockKameletClient) Kamelets(namespace string) camelkv1alpha1.KameletInterface {
-	var i camelkv1alpha1.KameletInterface = c
-	return i
-}
-
+// KameletBindings returns the mock of the Kamelet bindings, which shares the namespace of the Kamelet mock. The mock
+// thereby serves as client of the Kamelet binding API as well.
 func (c *MockKameletClient) KameletBindings(namespace string) camelkv1alpha1.KameletBindingInterface {
-	panic("implement me")
+	return c.bindingClient
 }
 
 // Recorder returns the recorder for registering API calls
@@ -97,34 +63,48 @@ func (c *MockKameletClient) Recorder() *KameletRecorder {
 	return c.recorder
 }
 
+// BindingRecorder returns the recorder for registering KameletBinding API calls
+func (c *MockKameletClient) BindingRecorder() *KameletBindingRecorder {
+	return c.bindingClient.Recorder()
+}
+
 // List records a call for ListKamelets with the expected result and error (nil if none)
 func (sr *KameletRecorder) List(kameletList *camelkapis.KameletList, err error) {
-	sr.r.Add("List", nil, []interface{}{kameletList, err})
+	sr.ListWithOptions(mock.Any(), kameletList, err)
+}
+
+// ListWithOptions records a call for ListKamelets with the expected list options, result and error (nil if none)
+func (sr *KameletRecorder) ListWithOptions(options interface{}, kameletList *camelkapis.KameletList, err error) {
+	sr.r.Add("List", []interface{}{options}, []interface{}{kameletList, err})
 }
 
 // List performs a previously recorded action
-func (c *MockKameletClient) List(ctx context.Context, opts v1.ListOptions) (*camelkapis.KameletList, error) {
-	call := c.recorder.r.VerifyCall("List")
+func (c *MockKameletClient) List(ctx context.Context, namespace string, opts v1.ListOptions) (*camelkapis.KameletList, error) {
+	call := c.recorder.r.VerifyCall("List", opts)
 	return call.Result[0].(*camelkapis.KameletList), mock.ErrorOrNil(call.Result[1])
 }
 
-func (c *MockKameletClient) Create(ctx context.Context, kamelet *camelkapis.Kamelet, opts v1.CreateOptions) (*camelkapis.Kamelet, error) {
-	panic("implement me")
+// Create records a call for CreateKamelet with the expected error (nil if none)
+func (sr *KameletRecorder) Create(kamelet interface{}, err error) {
+	sr.CreateWithOptions(kamelet, mock.Any(), err)
 }
 
-func (c *MockKameletClient) Update(ctx context.Context, kamelet *camelkapis.Kamelet, opts v1.UpdateOptions) (*camelkapis.Kamelet, error) {
-	panic("implement me")
+// CreateWithOptions records a call for CreateKamelet with the expected create options and error (nil if none)
+func (sr *KameletRecorder) CreateWithOptions(kamelet interface{}, options interface{}, err error) {
+	sr.r.Add("Create", []interface{}{kamelet, options}, []interface{}{err})
 }
 
-func (c *MockKameletClient) UpdateStatus(ctx context.Context, kamelet *camelkapis.Kamelet, opts v1.UpdateOptions) (*camelkapis.Kamelet, error) {
-	panic("implement me")
+// Create performs a previously recorded action
+func (c *MockKameletClient) Create(ctx context.Context, kamelet *camelkapis.Kamelet, opts v1.CreateOptions) (*camelkapis.Kamelet, error) {
+	call := c.recorder.r.VerifyCall("Create", kamelet, opts)
+	return kamelet.DeepCopy(), mock.ErrorOrNil(call.Result[0])
 }
 
-func (c *MockKameletClient) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+func (c *MockKameletClient) Update(ctx context.Context, kamelet *camelkapis.Kamelet, opts v1.UpdateOptions) (*camelkapis.Kamelet, error) {
 	panic("implement me")
 }
 
-func (c *MockKameletClient) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+func (c *MockKameletClient) Delete(ctx context.Context, namespace string, name string, opts v1.DeleteOptions) error {
 	panic("implement me")
 }
 
@@ -134,17 +114,23 @@ func (sr *KameletRecorder) Get(kamelet *camelkapis.Kamelet, err error) {
 }
 
 // Get performs a previously recorded action
-func (c *MockKameletClient) Get(ctx context.Context, name string, opts v1.GetOptions) (*camelkapis.Kamelet, error) {
+func (c *MockKameletClient) Get(ctx context.Context, namespace string, name string, opts v1.GetOptions) (*camelkapis.Kamelet, error) {
 	call := c.recorder.r.VerifyCall("Get")
 	return call.Result[0].(*camelkapis.Kamelet), mock.ErrorOrNil(call.Result[1])
 }
 
-func (c *MockKameletClient) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
-	panic("implement me")
+// Watch records a call for WatchKamelet with the expected watcher and error (nil if none)
+func (sr *KameletRecorder) Watch(watcher watch.Interface, err error) {
+	sr.r.Add("Watch", nil, []interface{}{watcher, err})
 }
 
-func (c *MockKameletClient) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *camelkapis.Kamelet, err error) {
-	panic("implement me")
+// Watch performs a previously recorded action
+func (c *MockKameletClient) Watch(ctx context.Context, namespace string, opts v1.ListOptions) (watch.Interface, error) {
+	call := c.recorder.r.VerifyCall("Watch")
+	if call.Result[0] == nil {
+		return nil, mock.ErrorOrNil(call.Result[1])
+	}
+	return call.Result[0].(watch.Interface), mock.ErrorOrNil(call.Result[1])
 }
 
 // Validate validates whether every recorded action has been called
@@ -0,0 +1,187 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	camelkapis "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"knative.dev/client/pkg/util/mock"
+)
+
+// GeneratedNameSuffix is appended to the generate name of created objects that have no name set
+const GeneratedNameSuffix = "x7k2p"
+
+// MockKameletBindingClient is a combine of test object and recorder
+type MockKameletBindingClient struct {
+	t        *testing.T
+	recorder *KameletBindingRecorder
+}
+
+// NewMockKameletBindingClient returns a new mock instance which you need to record for
+func NewMockKameletBindingClient(t *testing.T, ns ...string) *MockKameletBindingClient {
+	namespace := "default"
+	if len(ns) > 0 {
+		namespace = ns[0]
+	}
+	return &MockKameletBindingClient{
+		t:        t,
+		recorder: &KameletBindingRecorder{mock.NewRecorder(t, namespace)},
+	}
+}
+
+// Ensure that the interface is implemented
+var _ camelkv1alpha1.KameletBindingInterface = &MockKameletBindingClient{}
+
+// KameletBindingRecorder is recorder for KameletBinding objects
+type KameletBindingRecorder struct {
+	r *mock.Recorder
+}
+
+// Recorder returns the recorder for registering API calls
+func (c *MockKameletBindingClient) Recorder() *KameletBindingRecorder {
+	return c.recorder
+}
+
+// Create records a call for CreateKameletBinding with the expected error (nil if none)
+func (sr *KameletBindingRecorder) Create(binding interface{}, err error) {
+	sr.CreateWithOptions(binding, mock.Any(), err)
+}
+
+// CreateWithOptions records a call for CreateKameletBinding with the expected create options and error (nil if none)
+func (sr *KameletBindingRecorder) CreateWithOptions(binding interface{}, options interface{}, err error) {
+	sr.r.Add("Create", []interface{}{binding, options}, []interface{}{err})
+}
+
+// Create performs a previously recorded action
+func (c *MockKameletBindingClient) Create(ctx context.Context, binding *camelkapis.KameletBinding, opts v1.CreateOptions) (*camelkapis.KameletBinding, error) {
+	call := c.recorder.r.VerifyCall("Create", binding, opts)
+	created := binding.DeepCopy()
+	// simulate server side name generation
+	if created.Name == "" && created.GenerateName != "" {
+		created.Name = created.GenerateName + GeneratedNameSuffix
+	}
+	return created, mock.ErrorOrNil(call.Result[0])
+}
+
+// Update records a call for UpdateKameletBinding with the expected error (nil if none)
+func (sr *KameletBindingRecorder) Update(binding interface{}, err error) {
+	sr.UpdateWithOptions(binding, mock.Any(), err)
+}
+
+// UpdateWithOptions records a call for UpdateKameletBinding with the expected update options and error (nil if none)
+func (sr *KameletBindingRecorder) UpdateWithOptions(binding interface{}, options interface{}, err error) {
+	sr.r.Add("Update", []interface{}{binding, options}, []interface{}{err})
+}
+
+// Update performs a previously recorded action
+func (c *MockKameletBindingClient) Update(ctx context.Context, binding *camelkapis.KameletBinding, opts v1.UpdateOptions) (*camelkapis.KameletBinding, error) {
+	call := c.recorder.r.VerifyCall("Update", binding, opts)
+	return binding.DeepCopy(), mock.ErrorOrNil(call.Result[0])
+}
+
+func (c *MockKameletBindingClient) UpdateStatus(ctx context.Context, binding *camelkapis.KameletBinding, opts v1.UpdateOptions) (*camelkapis.KameletBinding, error) {
+	panic("implement me")
+}
+
+// Delete records a call for DeleteKameletBinding with the expected error (nil if none)
+func (sr *KameletBindingRecorder) Delete(name interface{}, err error) {
+	sr.DeleteWithOptions(name, mock.Any(), err)
+}
+
+// DeleteWithOptions records a call for DeleteKameletBinding with the expected delete options and error (nil if none)
+func (sr *KameletBindingRecorder) DeleteWithOptions(name interface{}, options interface{}, err error) {
+	sr.r.Add("Delete", []interface{}{name, options}, []interface{}{err})
+}
+
+// Delete performs a previously recorded action
+func (c *MockKameletBindingClient) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	call := c.recorder.r.VerifyCall("Delete", name, opts)
+	return mock.ErrorOrNil(call.Result[0])
+}
+
+func (c *MockKameletBindingClient) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	panic("implement me")
+}
+
+// Get records a call for GetKameletBinding with the expected result and error (nil if none)
+func (sr *KameletBindingRecorder) Get(name interface{}, binding *camelkapis.KameletBinding, err error) {
+	sr.r.Add("Get", []interface{}{name}, []interface{}{binding, err})
+}
+
+// Get performs a previously recorded action
+func (c *MockKameletBindingClient) Get(ctx context.Context, name string, opts v1.GetOptions) (*camelkapis.KameletBinding, error) {
+	call := c.recorder.r.VerifyCall("Get", name)
+	return call.Result[0].(*camelkapis.KameletBinding), mock.ErrorOrNil(call.Result[1])
+}
+
+// List records a call for ListKameletBindings with the expected result and error (nil if none)
+func (sr *KameletBindingRecorder) List(bindingList *camelkapis.KameletBindingList, err error) {
+	sr.ListWithOptions(mock.Any(), bindingList, err)
+}
+
+// ListWithOptions records a call for ListKameletBindings with the expected list options, result and error (nil if none)
+func (sr *KameletBindingRecorder) ListWithOptions(options interface{}, bindingList *camelkapis.KameletBindingList, err error) {
+	sr.r.Add("List", []interface{}{options}, []interface{}{bindingList, err})
+}
+
+// List performs a previously recorded action
+func (c *MockKameletBindingClient) List(ctx context.Context, opts v1.ListOptions) (*camelkapis.KameletBindingList, error) {
+	call := c.recorder.r.VerifyCall("List", opts)
+	return call.Result[0].(*camelkapis.KameletBindingList), mock.ErrorOrNil(call.Result[1])
+}
+
+// Watch records a call for WatchKameletBinding with the watcher to return and the expected error (nil if none)
+func (sr *KameletBindingRecorder) Watch(watcher watch.Interface, err error) {
+	sr.r.Add("Watch", nil, []interface{}{watcher, err})
+}
+
+// Watch performs a previously recorded action
+func (c *MockKameletBindingClient) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	call := c.recorder.r.VerifyCall("Watch")
+	if call.Result[0] == nil {
+		return nil, mock.ErrorOrNil(call.Result[1])
+	}
+	return call.Result[0].(watch.Interface), mock.ErrorOrNil(call.Result[1])
+}
+
+// Patch records a call for PatchKameletBinding with the expected patch data given as string, the result and error (nil if none)
+func (sr *KameletBindingRecorder) Patch(name interface{}, patchType interface{}, data interface{}, binding *camelkapis.KameletBinding, err error) {
+	sr.PatchWithOptions(name, patchType, data, mock.Any(), binding, err)
+}
+
+// PatchWithOptions records a call for PatchKameletBinding with the expected patch data and options, the result and error (nil if none)
+func (sr *KameletBindingRecorder) PatchWithOptions(name interface{}, patchType interface{}, data interface{}, options interface{},
+	binding *camelkapis.KameletBinding, err error) {
+	sr.r.Add("Patch", []interface{}{name, patchType, data, options}, []interface{}{binding, err})
+}
+
+// Patch performs a previously recorded action
+func (c *MockKameletBindingClient) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *camelkapis.KameletBinding, err error) {
+	call := c.recorder.r.VerifyCall("Patch", name, pt, string(data), opts)
+	return call.Result[0].(*camelkapis.KameletBinding), mock.ErrorOrNil(call.Result[1])
+}
+
+// Validate validates whether every recorded action has been called
+func (sr *KameletBindingRecorder) Validate() {
+	sr.r.CheckThatAllRecordedMethodsHaveBeenCalled()
+}
@@ -0,0 +1,173 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package testing provides an in-memory fake of the Kamelet client the commands depend on.
+package testing
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	camelkapis "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"github.com/apache/camel-k/pkg/client/camel/clientset/versioned/scheme"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var (
+	kameletsResource = camelkapis.SchemeGroupVersion.WithResource("kamelets")
+	kameletsKind     = camelkapis.SchemeGroupVersion.WithKind("Kamelet")
+)
+
+// FakeKameletClient is a Kamelet client keeping the Kamelets in memory, so that tests can check the objects a command
+// leaves behind instead of recording every request it sends
+type FakeKameletClient struct {
+	tracker k8stesting.ObjectTracker
+}
+
+// NewFakeKameletClient returns a new fake holding given Kamelets
+func NewFakeKameletClient(t *testing.T, objects ...runtime.Object) *FakeKameletClient {
+	tracker := k8stesting.NewObjectTracker(scheme.Scheme, scheme.Codecs.UniversalDecoder())
+	for _, obj := range objects {
+		if err := tracker.Add(obj); err != nil {
+			t.Fatalf("unable to add object to the fake Kamelet client: %v", err)
+		}
+	}
+	return &FakeKameletClient{tracker: tracker}
+}
+
+// Get returns the Kamelet with given name
+func (c *FakeKameletClient) Get(ctx context.Context, namespace string, name string, opts v1.GetOptions) (*camelkapis.Kamelet, error) {
+	obj, err := c.tracker.Get(kameletsResource, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*camelkapis.Kamelet), nil
+}
+
+// List returns the Kamelets matching the label and field selectors of given options ordered by namespace and name,
+// in all namespaces if namespace is empty
+func (c *FakeKameletClient) List(ctx context.Context, namespace string, opts v1.ListOptions) (*camelkapis.KameletList, error) {
+	filter, err := newListFilter(opts)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := c.tracker.List(kameletsResource, kameletsKind, namespace)
+	if err != nil {
+		return nil, err
+	}
+	list := obj.(*camelkapis.KameletList)
+	items := list.Items[:0]
+	for _, kamelet := range list.Items {
+		if filter.matches(&kamelet) {
+			items = append(items, kamelet)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return lessObject(&items[i], &items[j]) })
+	list.Items = items
+	return list, nil
+}
+
+// Create stores given Kamelet in the namespace set on the Kamelet
+func (c *FakeKameletClient) Create(ctx context.Context, kamelet *camelkapis.Kamelet, opts v1.CreateOptions) (*camelkapis.Kamelet, error) {
+	if err := c.tracker.Create(kameletsResource, kamelet, kamelet.Namespace); err != nil {
+		return nil, err
+	}
+	return c.Get(ctx, kamelet.Namespace, kamelet.Name, v1.GetOptions{})
+}
+
+// Update replaces the stored Kamelet with given one
+func (c *FakeKameletClient) Update(ctx context.Context, kamelet *camelkapis.Kamelet, opts v1.UpdateOptions) (*camelkapis.Kamelet, error) {
+	if err := c.tracker.Update(kameletsResource, kamelet, kamelet.Namespace); err != nil {
+		return nil, err
+	}
+	return c.Get(ctx, kamelet.Namespace, kamelet.Name, v1.GetOptions{})
+}
+
+// Delete removes the Kamelet with given name
+func (c *FakeKameletClient) Delete(ctx context.Context, namespace string, name string, opts v1.DeleteOptions) error {
+	return c.tracker.Delete(kameletsResource, namespace, name)
+}
+
+// Watch returns a watch of the changes to the Kamelets matching the label and field selectors of given options
+func (c *FakeKameletClient) Watch(ctx context.Context, namespace string, opts v1.ListOptions) (watch.Interface, error) {
+	return watchObjects(c.tracker, kameletsResource, namespace, opts)
+}
+
+// listFilter matches objects with the label and field selectors of list options
+type listFilter struct {
+	labels labels.Selector
+	fields fields.Selector
+}
+
+// newListFilter parses the selectors of given options, only the metadata.name and metadata.namespace field labels
+// are supported like for every custom resource
+func newListFilter(opts v1.ListOptions) (*listFilter, error) {
+	labelSelector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	fieldSelector, err := fields.ParseSelector(opts.FieldSelector)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	for _, requirement := range fieldSelector.Requirements() {
+		if requirement.Field != "metadata.name" && requirement.Field != "metadata.namespace" {
+			return nil, apierrors.NewBadRequest(fmt.Sprintf("field label not supported: %s", requirement.Field))
+		}
+	}
+	return &listFilter{labels: labelSelector, fields: fieldSelector}, nil
+}
+
+func (f *listFilter) matches(obj v1.Object) bool {
+	return f.labels.Matches(labels.Set(obj.GetLabels())) &&
+		f.fields.Matches(fields.Set{"metadata.name": obj.GetName(), "metadata.namespace": obj.GetNamespace()})
+}
+
+// lessObject orders listed objects by namespace and name, so that lists do not depend on the order the objects
+// are tracked in
+func lessObject(a v1.Object, b v1.Object) bool {
+	if a.GetNamespace() != b.GetNamespace() {
+		return a.GetNamespace() < b.GetNamespace()
+	}
+	return a.GetName() < b.GetName()
+}
+
+// watchObjects watches the objects of given resource tracked in namespace, dropping the events of objects which do
+// not match the selectors of given options
+func watchObjects(tracker k8stesting.ObjectTracker, gvr schema.GroupVersionResource, namespace string,
+	opts v1.ListOptions) (watch.Interface, error) {
+	filter, err := newListFilter(opts)
+	if err != nil {
+		return nil, err
+	}
+	watcher, err := tracker.Watch(gvr, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return watch.Filter(watcher, func(event watch.Event) (watch.Event, bool) {
+		obj, ok := event.Object.(v1.Object)
+		return event, ok && filter.matches(obj)
+	}), nil
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package testing
+
+import (
+	"context"
+	"testing"
+
+	camelkapis "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestFakeKameletClient(t *testing.T) {
+	ctx := context.TODO()
+	c := NewFakeKameletClient(t, newKamelet("default", "k1", "a"), newKamelet("default", "k2", "b"), newKamelet("other", "k3", "a"))
+
+	watcher, err := c.Watch(ctx, "default", v1.ListOptions{FieldSelector: "metadata.name=k4"})
+	assert.NilError(t, err)
+	defer watcher.Stop()
+
+	_, err = c.Create(ctx, newKamelet("default", "k1", "a"), v1.CreateOptions{})
+	assert.Assert(t, apierrors.IsAlreadyExists(err), err)
+	created, err := c.Create(ctx, newKamelet("default", "k4", "b"), v1.CreateOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, created.Namespace, "default")
+
+	// only the events of the watched Kamelet are received
+	event := <-watcher.ResultChan()
+	assert.Equal(t, event.Type, watch.Added)
+	assert.Equal(t, event.Object.(*camelkapis.Kamelet).Name, "k4")
+
+	list, err := c.List(ctx, "default", v1.ListOptions{LabelSelector: "team=b"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, kameletNames(list), []string{"k2", "k4"})
+	list, err = c.List(ctx, "", v1.ListOptions{LabelSelector: "team=a", FieldSelector: "metadata.namespace=other"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, kameletNames(list), []string{"k3"})
+	_, err = c.List(ctx, "default", v1.ListOptions{FieldSelector: "status.phase=Ready"})
+	assert.Assert(t, apierrors.IsBadRequest(err), err)
+
+	assert.NilError(t, c.Delete(ctx, "default", "k4", v1.DeleteOptions{}))
+	event = <-watcher.ResultChan()
+	assert.Equal(t, event.Type, watch.Deleted)
+	_, err = c.Get(ctx, "default", "k4", v1.GetOptions{})
+	assert.Assert(t, apierrors.IsNotFound(err), err)
+}
+
+func newKamelet(namespace string, name string, team string) *camelkapis.Kamelet {
+	return &camelkapis.Kamelet{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels:    map[string]string{"team": team},
+		},
+	}
+}
+
+func kameletNames(list *camelkapis.KameletList) []string {
+	names := []string{}
+	for _, kamelet := range list.Items {
+		names = append(names, kamelet.Name)
+	}
+	return names
+}
@@ -0,0 +1,903 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	camelkapisv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	knerrors "knative.dev/client/pkg/errors"
+	"knative.dev/client/pkg/kn/commands"
+	knflags "knative.dev/client/pkg/kn/flags"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+// bindingNameHashLength is the number of hex digits of the hash suffixing truncated binding names
+const bindingNameHashLength = 8
+
+var bindExample = `
+  # Bind Kamelet source to Knative service
+  kn-source-kamelet bind timer-source --sink ksvc:my-service -p message=Hello
+
+  # Bind Kamelet source to Knative channel using a custom binding name
+  kn-source-kamelet bind timer-source --sink channel:my-channel --name timer-binding
+
+  # Bind Kamelet source to Knative service reading properties from a YAML file
+  kn-source-kamelet bind timer-source --sink ksvc:my-service --properties-file timer.yaml
+
+  # Bind Kamelet source to Knative broker reading the secretKey property from key aws-secret of secret aws
+  kn-source-kamelet bind aws-s3-source --sink broker:default -p accessKey=AKIA --secret secretKey=aws/aws-secret
+
+  # Bind Kamelet source to Knative broker reading the secretKey property from key aws-secret of secret aws
+  kn-source-kamelet bind aws-s3-source --sink broker:default --property-from-secret secretKey=aws:aws-secret
+
+  # Bind Kamelet source to Knative broker reading the accessKey property from environment variable AWS_ACCESS_KEY
+  kn-source-kamelet bind aws-s3-source --sink broker:default --property-from-env accessKey=AWS_ACCESS_KEY
+
+  # Bind Kamelet source to Knative broker overriding the type of the produced CloudEvents
+  kn-source-kamelet bind timer-source --sink broker:default --ce-override type=dev.example.timer
+
+  # Bind Kamelet source to Knative broker and create a Trigger delivering only the timer events to a Knative service
+  kn-source-kamelet bind timer-source --sink broker:default --trigger-filter type=dev.example.timer --trigger-subscriber ksvc:my-service
+
+  # Bind Kamelet sources to Knative broker printing the source, name and status of each binding as JSON
+  kn-source-kamelet bind timer-source aws-s3-source --sink broker:default -o json-summary
+
+  # Bind Kamelet sources to Knative broker naming the bindings team-a-timer-source and team-a-aws-s3-source
+  kn-source-kamelet bind timer-source aws-s3-source --sink broker:default --name-prefix team-a
+
+  # Bind Kamelet source to Knative broker labeling and annotating the Kamelet binding
+  kn-source-kamelet bind timer-source --sink broker:default --label team=payments --annotation owner=jane@example.com
+
+  # Bind Kamelet source to Knative broker running the integration under given service account
+  kn-source-kamelet bind timer-source --sink broker:default --service-account timer-runner
+
+  # Bind Kamelet source to Knative broker configuring the traits of the integration
+  kn-source-kamelet bind timer-source --sink broker:default --trait container.limit-memory=256Mi --trait mount.configs=configmap:a --trait mount.configs=configmap:b
+
+  # Bind Kamelet source to Knative broker rejecting property values violating the constraints of the Kamelet schema
+  kn-source-kamelet bind timer-source --sink broker:default -p period=1000 --strict
+
+  # Bind Kamelet source to Knative broker asking for the values of required properties
+  kn-source-kamelet bind timer-source --sink broker:default --interactive
+
+  # Bind several Kamelet sources to the same Knative broker, one binding is created per Kamelet
+  kn-source-kamelet bind timer-source other-source --sink broker:default
+
+  # Bind Kamelet source to Knative broker without waiting for the binding to become ready
+  kn-source-kamelet bind timer-source --sink broker:default --no-wait
+
+  # Bind Kamelet source to Knative broker and stream the logs of its integration once the binding is ready
+  kn-source-kamelet bind timer-source --sink broker:default --log
+
+  # Bind Kamelet source to Knative broker replacing the binding created by a previous run
+  kn-source-kamelet bind timer-source --sink broker:default --name timer-binding --replace
+
+  # Bind Kamelet source to Knative broker creating a KameletBinding even if the cluster supports pipes
+  kn-source-kamelet bind timer-source --sink broker:default --api kameletbinding
+
+  # Bind Kamelet source to Knative broker and print just the name of the created binding
+  kn-source-kamelet bind timer-source --sink broker:default -o name
+
+  # Print the Kamelet binding that would be created as YAML without creating it
+  kn-source-kamelet bind timer-source --sink broker:default -p message=Hello --dry-run=client
+
+  # Generate the Kamelet binding manifest as JSON, e.g. for committing it to a GitOps repository
+  kn-source-kamelet bind timer-source --sink broker:default -p message=Hello -o json
+
+  # Write the Kamelet binding manifest that would be created to given file
+  kn-source-kamelet bind timer-source --sink broker:default -p message=Hello --dry-run=client --output-file bindings/timer.yaml`
+
+// cloudEventOverridePrefix is the endpoint property prefix for CloudEvent attribute overrides of the Camel Knative component
+const cloudEventOverridePrefix = "ce.override.ce-"
+
+// cloudEventAttributeName matches valid CloudEvent attribute names
+var cloudEventAttributeName = regexp.MustCompile("^[a-z0-9]+$")
+
+// bindOptions holds the flag values of the bind command
+type bindOptions struct {
+	name           string
+	namePrefix     string
+	sink           string
+	properties     []string
+	propertiesFile string
+	ceOverrides    []string
+	output         string
+	interactive    bool
+	wait           bool
+	timeout        time.Duration
+	waitWindow     time.Duration
+	dryRun         string
+	api            string
+	replace        bool
+	force          bool
+	labels         []string
+	annotations    []string
+	strict         bool
+	secrets        []string
+	secretProps    []string
+	envProps       []string
+	triggerFilters []string
+	triggerSubscr  string
+	serviceAccount string
+	traits         []string
+	fieldManager   string
+	log            bool
+	outputFile     string
+	rediscover     bool
+	// secretProperties holds the names of the properties read from a secret with --secret or
+	// --property-from-secret, their placeholders are resolved when the binding runs
+	secretProperties map[string]bool
+}
+
+// NewBindCommand implements 'kn-source-kamelet bind' command
+func NewBindCommand(p *KameletPluginParams) *cobra.Command {
+	options := &bindOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "bind NAME...",
+		Short:   "Bind Kamelet source to a Knative broker, channel or service",
+		Example: bindExample,
+		// several Kamelets can be bound at once
+		ValidArgsFunction: completeKameletNames(p, kameletTypeSource, -1),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if len(args) == 0 {
+				return errors.New("'kn-source-kamelet bind' requires at least one Kamelet name given as argument")
+			}
+			kameletNames := args
+
+			if options.sink == "" {
+				return errors.New("'kn-source-kamelet bind' requires the sink to be specified with --sink")
+			}
+			if options.name != "" && options.namePrefix != "" {
+				return errors.New("--name and --name-prefix can not be used together")
+			}
+			if err := validateWaitWindow(options.waitWindow, options.timeout); err != nil {
+				return err
+			}
+
+			switch options.output {
+			case "", "name", "yaml", "json", jsonSummaryFormat:
+			default:
+				return fmt.Errorf("invalid output format '%s', must be one of: name, yaml, json, %s", options.output, jsonSummaryFormat)
+			}
+			printObjects := options.output == "yaml" || options.output == "json"
+
+			if err := validateDryRun(options.dryRun); err != nil {
+				return err
+			}
+			if err := validateFieldManager(options.fieldManager); err != nil {
+				return err
+			}
+			if options.dryRun == dryRunClient && options.output == "name" {
+				return errors.New("--dry-run=client can not be combined with --output name")
+			}
+			if options.dryRun == dryRunClient && options.output == jsonSummaryFormat {
+				return fmt.Errorf("--dry-run=client can not be combined with --output %s as nothing is created", jsonSummaryFormat)
+			}
+			if options.outputFile != "" && !printObjects && options.output != jsonSummaryFormat && options.dryRun != dryRunClient {
+				return fmt.Errorf("--output-file requires --output yaml, json or %s, or --dry-run=client", jsonSummaryFormat)
+			}
+			if _, err := parseLabels(options.labels); err != nil {
+				return err
+			}
+			if _, err := parseAnnotations(options.annotations); err != nil {
+				return err
+			}
+			if err := validateServiceAccount(options.serviceAccount); err != nil {
+				return err
+			}
+			if _, err := parseTraits(options.traits); err != nil {
+				return err
+			}
+			secrets, err := parseSecretReferences(options.secrets)
+			if err != nil {
+				return err
+			}
+			secretProperties, err := parsePropertiesFromSecrets(options.secretProps)
+			if err != nil {
+				return err
+			}
+			for property, reference := range secretProperties {
+				if _, ok := secrets[property]; ok {
+					return fmt.Errorf("property '%s' is given with both --secret and --property-from-secret", property)
+				}
+				secrets[property] = reference
+			}
+
+			envProperties, err := parsePropertiesFromEnv(options.envProps)
+			if err != nil {
+				return err
+			}
+
+			triggerFilters, err := parseTriggerFilters(options.triggerFilters)
+			if err != nil {
+				return err
+			}
+			withTrigger := false
+			if len(options.triggerFilters) > 0 || options.triggerSubscr != "" {
+				sink, err := parseSink(options.sink, "")
+				if err != nil {
+					return err
+				}
+				if isBrokerSink(sink) {
+					if options.triggerSubscr == "" {
+						return errors.New("--trigger-filter requires the subscriber of the Trigger given with --trigger-subscriber")
+					}
+					withTrigger = true
+				} else {
+					fmt.Fprintln(cmd.ErrOrStderr(), "Warning: --trigger-filter and --trigger-subscriber are ignored as the sink is not a broker.")
+				}
+			}
+
+			if err := knflags.ReconcileBoolFlags(cmd.Flags()); err != nil {
+				return err
+			}
+			if options.log {
+				switch {
+				case len(kameletNames) > 1:
+					return errors.New("--log can only be used when binding a single Kamelet")
+				case !options.wait:
+					return errors.New("--log streams the logs once the binding is ready, it can not be combined with --no-wait")
+				case options.dryRun != dryRunNone:
+					return errors.New("--log can not be combined with --dry-run as no integration is started")
+				case options.output != "":
+					return errors.New("--log can not be combined with --output as the logs are printed to stdout")
+				}
+			}
+
+			if options.interactive && !isTerminalInput(cmd.InOrStdin()) {
+				return errors.New("--interactive requires a terminal attached to stdin")
+			}
+			if options.interactive && options.propertiesFile == "-" {
+				return errors.New("--interactive can not be combined with reading the properties file from stdin")
+			}
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := p.NewKameletClient()
+			if err != nil {
+				return err
+			}
+
+			kamelets := make([]*v1alpha1.Kamelet, 0, len(kameletNames))
+			for _, kameletName := range kameletNames {
+				kamelet, err := p.getKamelet(client, namespace, kameletName)
+				if err != nil {
+					return knerrors.GetError(err)
+				}
+
+				if err := verifyKameletType(kamelet, kameletTypeSource); err != nil {
+					return err
+				}
+				kamelets = append(kamelets, kamelet)
+			}
+
+			properties, err := collectProperties(cmd.InOrStdin(), options.propertiesFile, options.properties)
+			if err != nil {
+				return err
+			}
+			// environment values take precedence over the properties file like inline properties
+			inlineProperties, _ := parseProperties(options.properties)
+			for property, value := range envProperties {
+				if _, ok := inlineProperties[property]; ok {
+					return fmt.Errorf("property '%s' is given with both --property and --property-from-env", property)
+				}
+				properties[property] = value
+			}
+			options.secretProperties = make(map[string]bool, len(secrets))
+			for property, reference := range secrets {
+				if _, ok := properties[property]; ok {
+					return fmt.Errorf("property '%s' is given as plain value and read from a secret, use only one of them", property)
+				}
+				properties[property] = reference
+				options.secretProperties[property] = true
+			}
+
+			// all bindings are built and validated before the first one gets created
+			bindings := make([]*v1alpha1.KameletBinding, 0, len(kamelets))
+			for _, kamelet := range kamelets {
+				sourceProperties := make(map[string]string, len(properties))
+				for key, value := range properties {
+					sourceProperties[key] = value
+				}
+				if options.interactive {
+					if len(kamelets) > 1 {
+						fmt.Fprintf(cmd.ErrOrStderr(), "Properties of Kamelet %s:\n", kamelet.Name)
+					}
+					if err := promptRequiredProperties(cmd.InOrStdin(), cmd.ErrOrStderr(), kamelet, sourceProperties); err != nil {
+						return err
+					}
+				}
+
+				sourceOptions := *options
+				// the names are validated before any binding gets created
+				if len(kamelets) > 1 && options.name != "" {
+					if sourceOptions.name, err = prefixedBindingName("--name", options.name, kamelet.Name); err != nil {
+						return err
+					}
+				}
+				if options.namePrefix != "" {
+					if sourceOptions.name, err = prefixedBindingName("--name-prefix", options.namePrefix, kamelet.Name); err != nil {
+						return err
+					}
+				}
+				binding, err := createKameletBinding(namespace, kamelet, sourceProperties, &sourceOptions)
+				if err != nil {
+					return err
+				}
+				bindings = append(bindings, binding)
+			}
+
+			api, err := p.resolveBindingAPI(options.api, options.rediscover)
+			if err != nil {
+				return err
+			}
+			bindingClient := p.newBindingClient(api)
+
+			out, finish, err := openOutput(cmd.OutOrStdout(), options.outputFile)
+			if err != nil {
+				return err
+			}
+			defer func() { err = finish(err) }()
+
+			// the manifests are printed instead of creating the bindings, unless the server should validate them
+			if options.dryRun == dryRunClient || (printObjects && options.dryRun == dryRunNone) {
+				return writeBindingObjects(out, outputFormatOrYAML(options.output), bindingClient, bindings)
+			}
+
+			var triggerSubscriber *duckv1.Destination
+			var existingTrigger *eventingv1.Trigger
+			if withTrigger {
+				triggerSubscriber, err = parseTriggerSubscriber(options.triggerSubscr, namespace)
+				if err != nil {
+					return err
+				}
+				if options.dryRun == dryRunNone {
+					if existingTrigger, err = lookupBindingTrigger(p, bindings); err != nil {
+						return err
+					}
+				}
+			}
+
+			bindings, replaced, err := createKameletBindings(p, bindingClient, bindings, options)
+			if err != nil {
+				return err
+			}
+			// no Trigger is created on a server side dry run as the eventing client has no dry run support
+			var trigger *eventingv1.Trigger
+			if withTrigger && options.dryRun == dryRunNone {
+				trigger, err = createBindingTrigger(p, bindingClient, bindings, replaced, existingTrigger, triggerSubscriber,
+					triggerFilters)
+				if err != nil {
+					return err
+				}
+			}
+			if printObjects {
+				return writeBindingObjects(out, options.output, bindingClient, bindings)
+			}
+
+			// status messages go to stderr when only the name or the summary is printed
+			statusOut := cmd.OutOrStdout()
+			if options.output == "name" || options.output == jsonSummaryFormat {
+				statusOut = cmd.ErrOrStderr()
+			}
+			statusOut = p.messageWriter(statusOut)
+			for _, binding := range bindings {
+				action := "created"
+				if replaced[binding.Name] {
+					action = "replaced"
+				}
+				fmt.Fprintf(statusOut, "%s '%s' %s in namespace '%s'%s.\n", bindingClient.kind(), binding.Name, action, namespace,
+					dryRunSuffix(options.dryRun))
+			}
+			if trigger != nil {
+				action := "created"
+				if existingTrigger != nil {
+					action = "updated"
+				}
+				fmt.Fprintf(statusOut, "Trigger '%s' %s in namespace '%s'.\n", trigger.Name, action, trigger.Namespace)
+			}
+
+			// all bindings are waited for, so that the summary shows the status of each of them
+			results := newBindingResults(bindings, replaced, options.dryRun)
+			var failed []string
+			var failure error
+			// objects validated by a server side dry run never become ready
+			if options.wait && options.dryRun == dryRunNone {
+				for i, binding := range bindings {
+					if err := waitForKameletBinding(p, bindingClient, binding, statusOut, options.timeout, options.waitWindow); err != nil {
+						results[i].Status = err.Error()
+						failed = append(failed, binding.Name)
+						if failure == nil {
+							failure = err
+						}
+						continue
+					}
+					results[i].Status = bindingStatusReady
+				}
+			}
+			if options.output == jsonSummaryFormat {
+				if err := writeBindingSummaryJSON(out, results); err != nil {
+					return err
+				}
+			} else if len(bindings) > 1 {
+				if err := writeBindingSummary(statusOut, results); err != nil {
+					return err
+				}
+			}
+			if len(bindings) > 1 && len(failed) > 0 {
+				return fmt.Errorf("%d of %d %ss did not become ready (%s): %w", len(failed), len(bindings),
+					bindingClient.kind(), strings.Join(failed, ", "), failure)
+			}
+			if failure != nil {
+				return failure
+			}
+
+			if options.output == "name" {
+				for _, binding := range bindings {
+					fmt.Fprintln(cmd.OutOrStdout(), resourceName(bindingClient, binding.Name))
+				}
+			}
+			if options.log {
+				return streamIntegrationLogs(p, bindingClient.kind(), bindings[0], cmd.OutOrStdout(), statusOut)
+			}
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	commands.AddNamespaceFlags(flags, false)
+	flags.StringVar(&options.name, "name", "", "Name of the Kamelet binding. Generated from the Kamelet name when not set. "+
+		"When binding several Kamelets the Kamelet name is appended to the given name, shortened like with --name-prefix.")
+	flags.StringVar(&options.namePrefix, "name-prefix", "", "Prefix of the Kamelet binding name, the binding is named "+
+		"<prefix>-<kamelet> so that binding again yields the same name. Names longer than 63 characters are truncated "+
+		"and suffixed with a hash of the full name.")
+	flags.StringVarP(&options.sink, "sink", "s", "", sinkUsage)
+	flags.StringArrayVarP(&options.properties, "property", "p", nil, "Kamelet property given as key=value pair. Can be given multiple times.")
+	flags.StringVar(&options.propertiesFile, "properties-file", "", "YAML or JSON file holding Kamelet properties as top level keys. "+
+		"Use '-' to read from stdin. Properties given with --property take precedence.")
+	flags.StringArrayVar(&options.secrets, "secret", nil, "Kamelet property read from a Kubernetes secret given as "+
+		"property=secret[/key] pair, the key defaults to the property name. Can be given multiple times.")
+	flags.StringArrayVar(&options.secretProps, "property-from-secret", nil, "Kamelet property read from given key of a "+
+		"Kubernetes secret given as property=secret:key. Can be given multiple times.")
+	flags.StringArrayVar(&options.envProps, "property-from-env", nil, "Kamelet property read from the environment "+
+		"variable given as property=ENV_VAR, which keeps the value out of the process arguments. Can be given multiple times.")
+	flags.StringArrayVar(&options.ceOverrides, "ce-override", nil, "CloudEvent attribute override given as key=value pair, "+
+		"e.g. '--ce-override type=dev.example.timer'. Can be given multiple times.")
+	knflags.AddBothBoolFlagsUnhidden(flags, &options.wait, "wait", "", true, "Wait until the Kamelet binding is ready.")
+	flags.DurationVar(&options.timeout, "timeout", 60*time.Second, "Maximum time to wait for the Kamelet binding to become ready.")
+	flags.DurationVar(&options.waitWindow, "wait-window", 0, "Time the Kamelet binding must stay ready before the wait "+
+		"succeeds, e.g. 10s, so that a Ready condition flapping during startup is not taken for success. The window "+
+		"restarts whenever the binding is seen not ready. By default the wait succeeds as soon as the binding is ready.")
+	flags.BoolVarP(&options.interactive, "interactive", "i", false, "Prompt for the values of required properties not given "+
+		"with --property or --properties-file. Requires a terminal attached to stdin.")
+	flags.StringVarP(&options.output, "output", "o", "", "Output format. One of: name|yaml|json|json-summary. "+
+		"When set to 'name' only the resource name of the created binding is printed and status messages go to stderr. "+
+		"With 'json-summary' the source, name and status of each binding are printed as JSON once the bindings have "+
+		"been created and waited for, status messages go to stderr. "+
+		"With 'yaml' or 'json' the binding is printed instead of created, combined with --dry-run=server the binding "+
+		"validated by the API server is printed.")
+	flags.BoolVarP(&options.log, "log", "L", false, "Stream the logs of the integration pod to stdout once the binding "+
+		"is ready, until interrupted with Ctrl-C. Requires permission to list pods and to get pods/log in the namespace.")
+	addOutputFileFlag(flags, &options.outputFile)
+	addDryRunFlag(flags, &options.dryRun)
+	addFieldManagerFlag(flags, &options.fieldManager)
+	addBindingAPIFlag(flags, &options.api)
+	flags.BoolVar(&options.rediscover, "refresh-discovery", false, "Discover the APIs served by the cluster again "+
+		"instead of using the cached discovery, e.g. after Camel K has been upgraded.")
+	flags.StringArrayVar(&options.labels, "label", nil, "Label of the Kamelet binding given as key=value pair. "+
+		"Can be given multiple times.")
+	flags.StringArrayVar(&options.annotations, "annotation", nil, "Annotation of the Kamelet binding given as key=value pair. "+
+		"Can be given multiple times.")
+	flags.BoolVar(&options.replace, "replace", false, "Replace the Kamelet binding if a binding with the given name "+
+		"already exists, so that binding again is idempotent.")
+	flags.BoolVar(&options.force, "force", false, "Allow --replace to replace a Kamelet binding not created by this plugin.")
+	flags.StringArrayVar(&options.triggerFilters, "trigger-filter", nil, "CloudEvent attribute filter given as "+
+		"attribute=value pair of a Trigger created along with the binding, e.g. '--trigger-filter type=dev.example.timer'. "+
+		"Can be given multiple times. Ignored unless the sink is a broker.")
+	flags.StringVar(&options.triggerSubscr, "trigger-subscriber", "", "Subscriber of a Trigger created along with the "+
+		"binding, given like the sink. The Trigger delivers the events of the broker sink matching all given "+
+		"--trigger-filter attributes, it is not created on a dry run. An existing Trigger of the same name is updated if it "+
+		"has been created by this plugin. Ignored unless the sink is a broker.")
+	flags.StringVar(&options.serviceAccount, "service-account", "", "Service account the integration of the Kamelet "+
+		"binding runs with. Uses the default service account of the namespace when not set.")
+	flags.StringArrayVar(&options.traits, "trait", nil, "Trait configuration of the integration of the Kamelet binding "+
+		"given as trait.property=value pair, e.g. '--trait container.limit-memory=256Mi'. Can be given multiple times, "+
+		"a property given several times is configured with the list of its values.")
+	flags.BoolVar(&options.strict, "strict", false, "Validate the properties strictly against the Kamelet schema, "+
+		"enforcing the enum, minimum, maximum, length and pattern constraints of the properties in addition to their types.")
+	return cmd
+}
+
+// outputFormatOrYAML returns given output format if it is an object format, yaml otherwise
+func outputFormatOrYAML(output string) string {
+	if output == "json" {
+		return output
+	}
+	return "yaml"
+}
+
+// createKameletBinding builds the Kamelet binding object using given Kamelet as source
+func createKameletBinding(namespace string, kamelet *v1alpha1.Kamelet, propertyValues map[string]string, options *bindOptions) (*v1alpha1.KameletBinding, error) {
+	properties, err := validateProperties(kamelet, propertyValues, options.secretProperties, options.strict)
+	if err != nil {
+		return nil, err
+	}
+
+	sink, err := parseSink(options.sink, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	labels, err := parseLabels(options.labels)
+	if err != nil {
+		return nil, err
+	}
+	annotations, err := parseAnnotations(options.annotations)
+	if err != nil {
+		return nil, err
+	}
+	traits, err := parseTraits(options.traits)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := parseCloudEventOverrides(options.ceOverrides)
+	if err != nil {
+		return nil, err
+	}
+	if len(overrides) > 0 {
+		sink.Properties, err = asEndpointProperties(overrides)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	source := v1alpha1.Endpoint{
+		Ref: &corev1.ObjectReference{
+			Kind:       v1alpha1.KameletKind,
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Name:       kamelet.Name,
+			Namespace:  namespace,
+		},
+	}
+
+	if len(properties) > 0 {
+		source.Properties, err = asEndpointProperties(properties)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	binding := v1alpha1.NewKameletBinding(namespace, options.name)
+	binding.Labels = mergeMetadata(map[string]string{managedByLabel: managedByValue}, labels)
+	if len(annotations) > 0 {
+		binding.Annotations = annotations
+	}
+	if options.name == "" {
+		binding.GenerateName = kamelet.Name + "-"
+	}
+	binding.Spec.Source = source
+	binding.Spec.Sink = sink
+	if options.serviceAccount != "" || len(traits) > 0 {
+		binding.Spec.Integration = &camelkapisv1.IntegrationSpec{ServiceAccountName: options.serviceAccount}
+		if len(traits) > 0 {
+			binding.Spec.Integration.Traits = traits
+		}
+	}
+
+	return &binding, nil
+}
+
+// prefixedBindingName returns the binding name for given prefix and Kamelet, which must be a valid DNS-1123 label.
+// Names exceeding the maximum label length are truncated keeping a hash of the full name, so that they stay unique
+// and deterministic. The flag the prefix has been given with is named in the error.
+func prefixedBindingName(flag string, prefix string, kameletName string) (string, error) {
+	name := prefix + "-" + kameletName
+	if len(name) > validation.DNS1123LabelMaxLength {
+		hash := sha256.Sum256([]byte(name))
+		suffix := hex.EncodeToString(hash[:])[:bindingNameHashLength]
+		name = strings.TrimRight(name[:validation.DNS1123LabelMaxLength-len(suffix)-1], "-.") + "-" + suffix
+	}
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		return "", fmt.Errorf("invalid binding name '%s' for %s '%s': %s", name, flag, prefix, strings.Join(errs, "; "))
+	}
+	return name, nil
+}
+
+// validateServiceAccount checks that given service account name is a valid DNS-1123 subdomain, if set
+func validateServiceAccount(serviceAccount string) error {
+	if serviceAccount == "" {
+		return nil
+	}
+	if errs := validation.IsDNS1123Subdomain(serviceAccount); len(errs) > 0 {
+		return fmt.Errorf("invalid service account '%s': %s", serviceAccount, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// serviceAccountName returns the service account given Kamelet binding runs with, empty if not set
+func serviceAccountName(binding *v1alpha1.KameletBinding) string {
+	if binding.Spec.Integration == nil {
+		return ""
+	}
+	return binding.Spec.Integration.ServiceAccountName
+}
+
+// verifyServiceAccountKept fails if the API server dropped the service account of given binding when creating it,
+// as API versions not knowing the field prune it silently
+func verifyServiceAccountKept(client bindingClient, binding *v1alpha1.KameletBinding, result *v1alpha1.KameletBinding) error {
+	if serviceAccountName(binding) == "" || serviceAccountName(binding) == serviceAccountName(result) {
+		return nil
+	}
+	return fmt.Errorf("the %s API of the cluster does not support the service account of the integration, "+
+		"'%s' has been dropped from %s '%s', bind without --service-account or use another --api",
+		client.kind(), serviceAccountName(binding), client.kind(), result.Name)
+}
+
+// createKameletBindings creates given Kamelet bindings one after the other, existing bindings are replaced if
+// requested. The names of the replaced bindings are returned as well. When a creation fails, the bindings created
+// before are deleted again so that no partial set of bindings is left behind, replaced bindings are kept. Nothing
+// is rolled back on a server side dry run as nothing has been persisted.
+func createKameletBindings(p *KameletPluginParams, client bindingClient, bindings []*v1alpha1.KameletBinding,
+	options *bindOptions) ([]*v1alpha1.KameletBinding, map[string]bool, error) {
+	created := make([]*v1alpha1.KameletBinding, 0, len(bindings))
+	replaced := map[string]bool{}
+	for _, binding := range bindings {
+		result, err := client.create(p.Context, binding, v1.CreateOptions{DryRun: dryRunOptions(options.dryRun),
+			FieldManager: options.fieldManager})
+		if apierrors.IsAlreadyExists(err) && binding.Name != "" {
+			if options.replace {
+				result, err = replaceKameletBinding(p, client, binding, options)
+				if err == nil {
+					replaced[binding.Name] = true
+				}
+			} else {
+				err = fmt.Errorf("%s '%s' already exists in namespace '%s', use --replace to replace it or "+
+					"'kn-source-kamelet update' to change it", client.kind(), binding.Name, binding.Namespace)
+			}
+		}
+		if err == nil {
+			created = append(created, result)
+			if err = verifyServiceAccountKept(client, binding, result); err == nil {
+				continue
+			}
+		}
+		err = knerrors.GetError(err)
+		if len(created) == 0 || options.dryRun == dryRunServer {
+			return nil, nil, err
+		}
+
+		return nil, nil, fmt.Errorf("%v; rolled back already created bindings: %s", err,
+			rollbackKameletBindings(p, client, created, replaced))
+	}
+	return created, replaced, nil
+}
+
+// rollbackKameletBindings deletes given created bindings again and returns the summary of the rollback, replaced
+// bindings are kept
+func rollbackKameletBindings(p *KameletPluginParams, client bindingClient, created []*v1alpha1.KameletBinding,
+	replaced map[string]bool) string {
+	rollback := make([]string, 0, len(created))
+	for _, binding := range created {
+		if replaced[binding.Name] {
+			rollback = append(rollback, fmt.Sprintf("kept replaced %s '%s'", client.kind(), binding.Name))
+			continue
+		}
+		if deleteErr := client.delete(p.Context, binding.Namespace, binding.Name, v1.DeleteOptions{}); deleteErr != nil {
+			rollback = append(rollback, fmt.Sprintf("failed to delete %s '%s': %v", client.kind(), binding.Name, knerrors.GetError(deleteErr)))
+			continue
+		}
+		rollback = append(rollback, fmt.Sprintf("deleted %s '%s'", client.kind(), binding.Name))
+	}
+	return strings.Join(rollback, ", ")
+}
+
+// replaceKameletBinding replaces the existing binding having the name of given binding in place. Bindings not
+// created by this plugin are only replaced when forced.
+func replaceKameletBinding(p *KameletPluginParams, client bindingClient, binding *v1alpha1.KameletBinding,
+	options *bindOptions) (*v1alpha1.KameletBinding, error) {
+	existing, err := client.get(p.Context, binding.Namespace, binding.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Labels[managedByLabel] != managedByValue && !options.force {
+		return nil, fmt.Errorf("%s '%s' in namespace '%s' has not been created by %s, use --force to replace it anyway",
+			client.kind(), binding.Name, binding.Namespace, managedByValue)
+	}
+
+	replacement := binding.DeepCopy()
+	replacement.ResourceVersion = existing.ResourceVersion
+	return client.update(p.Context, replacement, v1.UpdateOptions{DryRun: dryRunOptions(options.dryRun),
+		FieldManager: options.fieldManager})
+}
+
+// waitForKameletBinding watches given Kamelet binding or pipe and prints its progress until it becomes ready and
+// stayed ready for given window. An error is returned when the binding fails or does not become ready within given
+// timeout.
+func waitForKameletBinding(p *KameletPluginParams, client bindingClient, binding *v1alpha1.KameletBinding,
+	out io.Writer, timeout time.Duration, window time.Duration) error {
+	kind := client.kind()
+	readyAtStart := isKameletBindingReady(binding)
+	if readyAtStart && window <= 0 {
+		fmt.Fprintf(out, "%s '%s' is ready.\n", kind, binding.Name)
+		return nil
+	}
+
+	watcher, err := client.watch(p.Context, binding.Namespace, v1.ListOptions{
+		FieldSelector:   fields.OneTermEqualSelector("metadata.name", binding.Name).String(),
+		ResourceVersion: binding.ResourceVersion,
+	})
+	if err != nil {
+		return knerrors.GetError(err)
+	}
+
+	// the progress line is updated in place on a terminal, otherwise a new line is printed from time to time
+	redraw := isTerminal(out)
+	interval := bindingProgressLogInterval
+	if redraw {
+		interval = bindingProgressRedrawInterval
+	}
+	start := time.Now()
+	progress := ""
+	printed := false
+	writeProgress := func(elapsed time.Duration) {
+		if redraw && printed {
+			clearLines(out, 1)
+		}
+		printed = true
+		fmt.Fprintln(out, bindingProgressLine(kind, binding.Name, progress, elapsed, timeout))
+	}
+	err = waitUntilReadyWithProgress(p.Context, watcher, kind, binding.Name, timeout, interval, kameletBindingReadiness, func(obj runtime.Object) error {
+		binding, ok := obj.(*v1alpha1.KameletBinding)
+		if !ok {
+			return fmt.Errorf("unexpected object type %T", obj)
+		}
+		reason := nonReadyBindingConditionReason(binding.Status.Conditions)
+		if binding.Status.Phase == v1alpha1.KameletBindingPhaseError {
+			return fmt.Errorf("%s '%s' failed: %s", kind, binding.Name, reason)
+		}
+		if window > 0 && isKameletBindingReady(binding) {
+			reason = fmt.Sprintf("ready, checking it stays ready for %s", window)
+		}
+		if reason == "" || reason == progress {
+			return nil
+		}
+		progress = reason
+		writeProgress(time.Since(start))
+		return nil
+	}, writeProgress, readinessWindow{duration: window, readyAtStart: readyAtStart})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%s '%s' is ready.\n", kind, binding.Name)
+	return nil
+}
+
+// bindingProgressRedrawInterval is the interval the progress line refreshes at while waiting on a terminal
+var bindingProgressRedrawInterval = time.Second
+
+// bindingProgressLogInterval is the interval a progress line is printed at while waiting without a terminal
+var bindingProgressLogInterval = 10 * time.Second
+
+// bindingProgressLine returns the progress message of a binding not ready yet, showing the elapsed time relative to
+// the timeout and the reason the binding is not ready if known
+func bindingProgressLine(kind string, name string, reason string, elapsed time.Duration, timeout time.Duration) string {
+	progress := fmt.Sprintf("(%s/%s)", elapsed.Truncate(time.Second), timeout)
+	if reason == "" {
+		return fmt.Sprintf("Waiting for %s '%s' to become ready... %s", kind, name, progress)
+	}
+	return fmt.Sprintf("Waiting for %s '%s' to become ready: %s %s", kind, name, reason, progress)
+}
+
+// isKameletBindingReady returns true if the ready condition of given Kamelet binding is true
+func isKameletBindingReady(binding *v1alpha1.KameletBinding) bool {
+	for _, condition := range binding.Status.Conditions {
+		if condition.Type == v1alpha1.KameletBindingConditionReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// kameletBindingReadiness is the readiness function used when watching Kamelet bindings
+func kameletBindingReadiness(obj runtime.Object) (bool, string) {
+	binding, ok := obj.(*v1alpha1.KameletBinding)
+	if !ok {
+		return false, ""
+	}
+	return isKameletBindingReady(binding), nonReadyBindingConditionReason(binding.Status.Conditions)
+}
+
+// nonReadyBindingConditionReason returns the reason and message of the ready condition if it is not true,
+// empty if the binding is ready or has no ready condition yet
+func nonReadyBindingConditionReason(conditions []v1alpha1.KameletBindingCondition) string {
+	for _, condition := range conditions {
+		if condition.Type == v1alpha1.KameletBindingConditionReady {
+			if condition.Status == corev1.ConditionTrue {
+				return ""
+			}
+			if condition.Message != "" {
+				return fmt.Sprintf("%s : %s", condition.Reason, condition.Message)
+			}
+			return condition.Reason
+		}
+	}
+	return ""
+}
+
+// parseProperties converts given key=value pairs into a property map
+func parseProperties(properties []string) (map[string]string, error) {
+	propertyMap := make(map[string]string, len(properties))
+	for _, property := range properties {
+		parts := strings.SplitN(property, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid property '%s', expected format key=value", property)
+		}
+		propertyMap[parts[0]] = parts[1]
+	}
+	return propertyMap, nil
+}
+
+// parseCloudEventOverrides converts given key=value pairs into the sink endpoint properties
+// that make the Camel Knative component override the respective CloudEvent attributes
+func parseCloudEventOverrides(overrides []string) (map[string]interface{}, error) {
+	properties := make(map[string]interface{}, len(overrides))
+	for _, override := range overrides {
+		parts := strings.SplitN(override, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid CloudEvent override '%s', expected format key=value", override)
+		}
+		if !cloudEventAttributeName.MatchString(parts[0]) {
+			return nil, fmt.Errorf("invalid CloudEvent attribute name '%s', must consist of lowercase letters and digits only", parts[0])
+		}
+		properties[cloudEventOverridePrefix+parts[0]] = parts[1]
+	}
+	return properties, nil
+}
+
+// asEndpointProperties converts given property map into the raw JSON representation of endpoint properties
+func asEndpointProperties(properties map[string]interface{}) (*v1alpha1.EndpointProperties, error) {
+	data, err := json.Marshal(properties)
+	if err != nil {
+		return nil, err
+	}
+	return &v1alpha1.EndpointProperties{
+		RawMessage: camelkapisv1.RawMessage(data),
+	}, nil
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	hprinters "knative.dev/client/pkg/printers"
+)
+
+// jsonSummaryFormat is the bind output format printing the result of each binding as JSON
+const jsonSummaryFormat = "json-summary"
+
+// bindingStatusReady is the status of bindings that became ready while waiting
+const bindingStatusReady = "Ready"
+
+// bindingResult is the outcome of binding a single Kamelet source, a row of the bind summary
+type bindingResult struct {
+	Source  string `json:"source"`
+	Binding string `json:"binding"`
+	Status  string `json:"status"`
+}
+
+// newBindingResults returns the results of given created bindings, their status tells whether they have been
+// created or replaced until they are waited for
+func newBindingResults(bindings []*v1alpha1.KameletBinding, replaced map[string]bool, dryRun string) []bindingResult {
+	results := make([]bindingResult, 0, len(bindings))
+	for _, binding := range bindings {
+		status := "Created"
+		if replaced[binding.Name] {
+			status = "Replaced"
+		}
+		source := ""
+		if binding.Spec.Source.Ref != nil {
+			source = binding.Spec.Source.Ref.Name
+		}
+		results = append(results, bindingResult{Source: source, Binding: binding.Name, Status: status + dryRunSuffix(dryRun)})
+	}
+	return results
+}
+
+// writeBindingSummary prints given results as table aligned like the one of list-types
+func writeBindingSummary(out io.Writer, results []bindingResult) error {
+	w := hprinters.NewTabWriter(out)
+	fmt.Fprintln(w, "SOURCE\tBINDING NAME\tSTATUS")
+	for _, result := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", result.Source, result.Binding, result.Status)
+	}
+	return w.Flush()
+}
+
+// writeBindingSummaryJSON prints given results as JSON array
+func writeBindingSummaryJSON(out io.Writer, results []bindingResult) error {
+	data, err := json.MarshalIndent(results, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(out, string(data))
+	return err
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	camelkapis "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"knative.dev/client/pkg/util"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestWriteBindingSummary(t *testing.T) {
+	out := &bytes.Buffer{}
+	assert.NilError(t, writeBindingSummary(out, []bindingResult{
+		{Source: "timer-source", Binding: "timer-source-binding", Status: "Ready"},
+		{Source: "aws-s3-source", Binding: "s3", Status: "Created"},
+	}))
+	assert.Equal(t, out.String(), "SOURCE          BINDING NAME           STATUS\n"+
+		"timer-source    timer-source-binding   Ready\n"+
+		"aws-s3-source   s3                     Created\n")
+}
+
+func TestBindMultipleSourcesSummary(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+	recorder.Get(createKamelet("k2"), nil)
+
+	uri := "https://event.receiver.uri"
+	expected := map[string]*camelkapis.KameletBinding{}
+	for _, kameletName := range []string{"k1", "k2"} {
+		expected[kameletName] = createKameletBindingFor(kameletName, "fan-in-"+kameletName)
+		expected[kameletName].Spec.Sink = camelkapis.Endpoint{URI: &uri}
+		bindingRecorder.Create(expected[kameletName], nil)
+	}
+
+	ready := expected["k1"].DeepCopy()
+	ready.Status.Phase = camelkapis.KameletBindingPhaseReady
+	ready.Status.Conditions = []camelkapis.KameletBindingCondition{
+		{Type: camelkapis.KameletBindingConditionReady, Status: corev1.ConditionTrue},
+	}
+	readyWatcher := watch.NewFakeWithChanSize(1, false)
+	readyWatcher.Modify(ready)
+	bindingRecorder.Watch(readyWatcher, nil)
+
+	failed := expected["k2"].DeepCopy()
+	failed.Status.Phase = camelkapis.KameletBindingPhaseError
+	failed.Status.Conditions = []camelkapis.KameletBindingCondition{
+		{Type: camelkapis.KameletBindingConditionReady, Status: corev1.ConditionFalse, Reason: "Error", Message: "sink not found"},
+	}
+	failedWatcher := watch.NewFakeWithChanSize(1, false)
+	failedWatcher.Modify(failed)
+	bindingRecorder.Watch(failedWatcher, nil)
+
+	// the failed binding does not stop waiting for the others and shows its error in the summary
+	output, err := runBindCmd(mockClient, "k1", "k2", "--name", "fan-in", "--sink", uri)
+	assert.Error(t, err, "1 of 2 KameletBindings did not become ready (fan-in-k2): "+
+		"KameletBinding 'fan-in-k2' failed: Error : sink not found")
+	lines := strings.Split(output, "\n")
+	header := indexOfLine(lines, "SOURCE")
+	assert.Assert(t, header >= 0, output)
+	assert.Assert(t, util.ContainsAll(lines[header], "SOURCE", "BINDING NAME", "STATUS"))
+	assert.Assert(t, util.ContainsAll(lines[header+1], "k1", "fan-in-k1", "Ready"))
+	assert.Assert(t, util.ContainsAll(lines[header+2], "k2", "fan-in-k2", "KameletBinding 'fan-in-k2' failed: Error : sink not found"))
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindJSONSummary(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+	recorder.Get(createKamelet("k2"), nil)
+
+	uri := "https://event.receiver.uri"
+	for _, kameletName := range []string{"k1", "k2"} {
+		expected := createKameletBindingFor(kameletName, "team-a-"+kameletName)
+		expected.Spec.Sink = camelkapis.Endpoint{URI: &uri}
+		bindingRecorder.Create(expected, nil)
+	}
+
+	output, err := runBindCmd(mockClient, "k1", "k2", "--name-prefix", "team-a", "--sink", uri, "--no-wait",
+		"-o", "json-summary")
+	assert.NilError(t, err)
+	var results []bindingResult
+	assert.NilError(t, json.Unmarshal([]byte(output), &results))
+	assert.DeepEqual(t, results, []bindingResult{
+		{Source: "k1", Binding: "team-a-k1", Status: "Created"},
+		{Source: "k2", Binding: "team-a-k2", Status: "Created"},
+	})
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", uri, "--dry-run=client", "-o", "json-summary")
+	assert.Error(t, err, "--dry-run=client can not be combined with --output json-summary as nothing is created")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
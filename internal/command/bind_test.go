@@ -0,0 +1,1714 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	camelkapisv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	camelkapis "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"github.com/apache/camel-k/pkg/client/camel/clientset/versioned/scheme"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	clienteventingv1 "knative.dev/client/pkg/eventing/v1"
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/util"
+	"knative.dev/client/pkg/util/mock"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestBindSetup(t *testing.T) {
+	p := KameletPluginParams{
+		Context: context.TODO(),
+	}
+
+	bindCmd := NewBindCommand(&p)
+	assert.Equal(t, bindCmd.Use, "bind NAME...")
+	assert.Equal(t, bindCmd.Short, "Bind Kamelet source to a Knative broker, channel or service")
+	assert.Assert(t, bindCmd.RunE != nil)
+	// --refresh-cache rebuilds the Kamelet cache of list-types, bind only refreshes the API discovery
+	assert.Assert(t, bindCmd.Flags().Lookup("refresh-discovery") != nil)
+	assert.Assert(t, bindCmd.Flags().Lookup("refresh-cache") == nil)
+}
+
+func TestBindErrorCaseMissingArgument(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+
+	_, err := runBindCmd(mockClient, "--sink", "ksvc:my-service")
+	assert.Error(t, err, "'kn-source-kamelet bind' requires at least one Kamelet name given as argument")
+	mockClient.Recorder().Validate()
+}
+
+func TestBindErrorCaseMissingSink(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+
+	_, err := runBindCmd(mockClient, "k1")
+	assert.Error(t, err, "'kn-source-kamelet bind' requires the sink to be specified with --sink")
+	mockClient.Recorder().Validate()
+}
+
+func TestBindErrorCaseNotFound(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKamelet("k1"), errors.New("not found"))
+
+	_, err := runBindCmd(mockClient, "k1", "--sink", "ksvc:my-service")
+	assert.Error(t, err, "not found")
+	recorder.Validate()
+}
+
+func TestBindErrorCaseNoEventSource(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	kamelet.Labels[kameletTypeLabel] = kameletTypeSink
+	recorder.Get(kamelet, nil)
+
+	_, err := runBindCmd(mockClient, "k1", "--sink", "ksvc:my-service")
+	assert.Error(t, err, "Kamelet k1 is a sink, not a source; use --type sink")
+	recorder.Validate()
+}
+
+func TestBindErrorCaseInvalidProperty(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+
+	_, err := runBindCmd(mockClient, "k1", "--sink", "ksvc:my-service", "-p", "message")
+	assert.Error(t, err, "invalid property 'message', expected format key=value")
+	recorder.Validate()
+}
+
+func TestBindErrorCaseUnknownProperty(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "message", "string", "The message to send", false)
+	addKameletProperty(kamelet, "period", "integer", "Delay between messages", false)
+	recorder.Get(kamelet, nil)
+	recorder.Get(createKamelet("k2"), nil)
+
+	_, err := runBindCmd(mockClient, "k1", "--sink", "ksvc:my-service", "-p", "msg=Hello")
+	assert.Error(t, err, "unknown property 'msg' for Kamelet k1, valid properties are: message, period")
+
+	_, err = runBindCmd(mockClient, "k2", "--sink", "ksvc:my-service", "-p", "msg=Hello")
+	assert.Error(t, err, "unknown property 'msg', Kamelet k2 does not define any properties")
+	recorder.Validate()
+}
+
+func TestBindErrorCasePropertyType(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "period", "integer", "Delay between messages", false)
+	addKameletProperty(kamelet, "ratio", "number", "Sampling ratio", false)
+	addKameletProperty(kamelet, "enabled", "boolean", "Enable sending", false)
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+
+	_, err := runBindCmd(mockClient, "k1", "--sink", "ksvc:my-service", "-p", "period=soon")
+	assert.Error(t, err, "invalid value 'soon' for property 'period', expected type integer")
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", "ksvc:my-service", "-p", "ratio=half")
+	assert.Error(t, err, "invalid value 'half' for property 'ratio', expected type number")
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", "ksvc:my-service", "-p", "enabled=yes")
+	assert.Error(t, err, "invalid value 'yes' for property 'enabled', expected type boolean")
+	recorder.Validate()
+}
+
+func TestBindErrorCaseMissingRequiredProperty(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "message", "string", "The message to send", true)
+	addKameletProperty(kamelet, "period", "integer", "Delay between messages", true)
+	addKameletProperty(kamelet, "format", "string", "Message format", true)
+	addKameletProperty(kamelet, "repeat", "integer", "Repeat count", true)
+	setKameletPropertyDefault(kamelet, "repeat", "1")
+	recorder.Get(kamelet, nil)
+
+	_, err := runBindCmd(mockClient, "k1", "--sink", "ksvc:my-service", "-p", "format=text")
+	assert.Error(t, err, "missing required properties for Kamelet k1: message, period")
+	recorder.Validate()
+}
+
+func TestBindTypedProperties(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "period", "integer", "Delay between messages", true)
+	addKameletProperty(kamelet, "ratio", "number", "Sampling ratio", false)
+	addKameletProperty(kamelet, "enabled", "boolean", "Enable sending", false)
+	recorder.Get(kamelet, nil)
+
+	expected := createKameletBindingFor("k1", "k1-binding")
+	expected.Spec.Sink = camelkapis.Endpoint{
+		Ref: &corev1.ObjectReference{
+			Kind:       "Broker",
+			APIVersion: "eventing.knative.dev/v1",
+			Name:       "default",
+			Namespace:  "current",
+		},
+	}
+	setBindingProperties(t, expected, `{"enabled":true,"period":1000,"ratio":0.5}`)
+	bindingRecorder.Create(expected, nil)
+
+	_, err := runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", "broker:default",
+		"-p", "period=1000", "-p", "ratio=0.5", "-p", "enabled=true", "--no-wait")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindStrict(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "period", "integer", "Delay between messages", false)
+	addKameletProperty(kamelet, "format", "string", "Message format", false)
+	addKameletProperty(kamelet, "topic", "string", "Target topic", false)
+	period := kamelet.Spec.Definition.Properties["period"]
+	minimum, maximum := json.Number("100"), json.Number("60000")
+	period.Minimum, period.Maximum = &minimum, &maximum
+	kamelet.Spec.Definition.Properties["period"] = period
+	format := kamelet.Spec.Definition.Properties["format"]
+	format.Enum = []*camelkapis.JSON{{RawMessage: []byte(`"text"`)}, {RawMessage: []byte(`"json"`)}}
+	kamelet.Spec.Definition.Properties["format"] = format
+	topic := kamelet.Spec.Definition.Properties["topic"]
+	topic.Pattern = "^[a-z.]+$"
+	kamelet.Spec.Definition.Properties["topic"] = topic
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+
+	expected := createKameletBindingFor("k1", "k1-binding")
+	expected.Spec.Sink = camelkapis.Endpoint{
+		Ref: &corev1.ObjectReference{
+			Kind:       "Broker",
+			APIVersion: "eventing.knative.dev/v1",
+			Name:       "default",
+			Namespace:  "current",
+		},
+	}
+	setBindingProperties(t, expected, `{"format":"xml","period":10,"topic":"Orders"}`)
+	bindingRecorder.Create(expected, nil)
+
+	// without --strict only the property types are checked
+	_, err := runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", "broker:default",
+		"-p", "period=10", "-p", "format=xml", "-p", "topic=Orders", "--no-wait")
+	assert.NilError(t, err)
+
+	_, err = runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", "broker:default",
+		"-p", "period=10", "-p", "format=xml", "-p", "topic=Orders", "--no-wait", "--strict")
+	assert.Error(t, err, "properties violate the schema of Kamelet k1: "+
+		"property 'format' must be one of: \"text\", \"json\"; "+
+		"property 'period' must be greater than or equal to 100; "+
+		"property 'topic' must match the pattern '^[a-z.]+$'")
+	var violation *ErrPropertyConstraintViolation
+	assert.Assert(t, errors.As(err, &violation))
+	assert.Equal(t, len(violation.Violations), 3)
+
+	setBindingProperties(t, expected, `{"format":"json","period":60000,"topic":"orders.new"}`)
+	bindingRecorder.Create(expected, nil)
+
+	_, err = runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", "broker:default",
+		"-p", "period=60000", "-p", "format=json", "-p", "topic=orders.new", "--no-wait", "--strict")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestPropertyConstraintViolations(t *testing.T) {
+	minimum, maximum := json.Number("0.5"), json.Number("2")
+	minLength, maxLength := int64(2), int64(4)
+	property := camelkapis.JSONSchemaProps{
+		Minimum:          &minimum,
+		ExclusiveMinimum: true,
+		Maximum:          &maximum,
+		ExclusiveMaximum: true,
+		MinLength:        &minLength,
+		MaxLength:        &maxLength,
+		Enum:             []*camelkapis.JSON{{RawMessage: []byte(`1`)}, {RawMessage: []byte(`1.5`)}},
+	}
+	assert.Assert(t, len(propertyConstraintViolations(property, int64(1))) == 0)
+	assert.Assert(t, len(propertyConstraintViolations(property, 1.5)) == 0)
+	assert.DeepEqual(t, propertyConstraintViolations(property, 0.5), []string{
+		"must be one of: 1, 1.5", "must be greater than 0.5"})
+	assert.DeepEqual(t, propertyConstraintViolations(property, int64(2)), []string{
+		"must be one of: 1, 1.5", "must be less than 2"})
+
+	property = camelkapis.JSONSchemaProps{MinLength: &minLength, MaxLength: &maxLength, Pattern: "("}
+	assert.DeepEqual(t, propertyConstraintViolations(property, "a"), []string{
+		"must be at least 2 characters long", "declares the invalid pattern '('"})
+	assert.DeepEqual(t, propertyConstraintViolations(property, "abcde")[0], "must be at most 4 characters long")
+}
+
+func TestBindSecret(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "accessKey", "string", "The access key", true)
+	addKameletProperty(kamelet, "secretKey", "string", "The secret key", true)
+	addKameletProperty(kamelet, "port", "integer", "The port", true)
+	recorder.Get(kamelet, nil)
+
+	expected := createKameletBindingFor("k1", "k1-binding")
+	expected.Spec.Sink = camelkapis.Endpoint{
+		Ref: &corev1.ObjectReference{
+			Kind:       "Broker",
+			APIVersion: "eventing.knative.dev/v1",
+			Name:       "default",
+			Namespace:  "current",
+		},
+	}
+	setBindingProperties(t, expected, `{"accessKey":"AKIA","port":"{{secret:aws/port}}","secretKey":"{{secret:aws/aws-secret}}"}`)
+	bindingRecorder.Create(expected, nil)
+
+	// secret references satisfy required properties and skip the type checks
+	_, err := runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", "broker:default", "-p", "accessKey=AKIA",
+		"--secret", "secretKey=aws/aws-secret", "--secret", "port=aws", "--no-wait", "--strict")
+	assert.NilError(t, err)
+
+	// plain values shaped like secret placeholders are checked like any other value
+	recorder.Get(kamelet, nil)
+	_, err = runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", "broker:default", "-p", "accessKey=AKIA",
+		"--secret", "secretKey=aws/aws-secret", "-p", "port={{secret:aws/port}}", "--no-wait", "--strict")
+	assert.Error(t, err, "invalid value '{{secret:aws/port}}' for property 'port', expected type integer")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindPropertyFromSecret(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "accessKey", "string", "The access key", true)
+	addKameletProperty(kamelet, "secretKey", "string", "The secret key", true)
+	recorder.Get(kamelet, nil)
+
+	expected := createKameletBindingFor("k1", "k1-binding")
+	expected.Spec.Sink = camelkapis.Endpoint{
+		Ref: &corev1.ObjectReference{
+			Kind:       "Broker",
+			APIVersion: "eventing.knative.dev/v1",
+			Name:       "default",
+			Namespace:  "current",
+		},
+	}
+	setBindingProperties(t, expected, `{"accessKey":"{{secret:aws/access-key}}","secretKey":"{{secret:aws/secret.key}}"}`)
+	bindingRecorder.Create(expected, nil)
+
+	_, err := runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", "broker:default",
+		"--property-from-secret", "accessKey=aws:access-key", "--property-from-secret", "secretKey=aws:secret.key", "--no-wait")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindPropertyFromEnv(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	for name, value := range map[string]string{"TEST_ACCESS_KEY": "AKIA", "TEST_PERIOD": "1000", "TEST_INVALID_PERIOD": "soon"} {
+		assert.NilError(t, os.Setenv(name, value))
+		defer os.Unsetenv(name)
+	}
+	assert.NilError(t, os.Unsetenv("TEST_MISSING_KEY"))
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "accessKey", "string", "The access key", true)
+	addKameletProperty(kamelet, "period", "integer", "The interval", false)
+	recorder.Get(kamelet, nil)
+
+	expected := createKameletBindingFor("k1", "k1-binding")
+	expected.Spec.Sink = camelkapis.Endpoint{
+		Ref: &corev1.ObjectReference{
+			Kind:       "Broker",
+			APIVersion: "eventing.knative.dev/v1",
+			Name:       "default",
+			Namespace:  "current",
+		},
+	}
+	setBindingProperties(t, expected, `{"accessKey":"AKIA","period":1000}`)
+	bindingRecorder.Create(expected, nil)
+
+	_, err := runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", "broker:default",
+		"--property-from-env", "accessKey=TEST_ACCESS_KEY", "--property-from-env", "period=TEST_PERIOD", "--no-wait")
+	assert.NilError(t, err)
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--property-from-env", "accessKey")
+	assert.Error(t, err, "invalid property from environment 'accessKey', expected format property=ENV_VAR")
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--property-from-env", "accessKey=TEST_MISSING_KEY")
+	assert.Error(t, err, "environment variable 'TEST_MISSING_KEY' of property 'accessKey' is not set")
+
+	recorder.Get(kamelet, nil)
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "-p", "accessKey=AKIA",
+		"--property-from-env", "accessKey=TEST_ACCESS_KEY")
+	assert.Error(t, err, "property 'accessKey' is given with both --property and --property-from-env")
+
+	// the values read from the environment are validated against the Kamelet schema
+	recorder.Get(kamelet, nil)
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--property-from-env", "accessKey=TEST_ACCESS_KEY",
+		"--property-from-env", "period=TEST_INVALID_PERIOD")
+	assert.Error(t, err, "invalid value 'soon' for property 'period', expected type integer")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindErrorCasePropertyFromSecret(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "secretKey", "string", "The secret key", true)
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+
+	_, err := runBindCmd(mockClient, "k1", "--sink", "broker:default", "--property-from-secret", "secretKey=aws")
+	assert.Error(t, err, "invalid property from secret 'secretKey=aws', expected format property=secret:key")
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--property-from-secret", "secretKey=aws:")
+	assert.ErrorContains(t, err, "invalid secret key '' for property 'secretKey'")
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--property-from-secret", "secretKey=aws_1:key")
+	assert.ErrorContains(t, err, "invalid secret name 'aws_1' for property 'secretKey'")
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--property-from-secret", "secretKey=aws:key",
+		"--secret", "secretKey=aws")
+	assert.Error(t, err, "property 'secretKey' is given with both --secret and --property-from-secret")
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--property-from-secret", "secretKey=aws:key",
+		"-p", "secretKey=s3cr3t")
+	assert.Error(t, err, "property 'secretKey' is given as plain value and read from a secret, use only one of them")
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--property-from-secret", "password=aws:key")
+	assert.Error(t, err, "unknown property 'password' for Kamelet k1, valid properties are: secretKey")
+
+	recorder.Validate()
+}
+
+func TestBindErrorCaseSecret(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "secretKey", "string", "The secret key", true)
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+
+	_, err := runBindCmd(mockClient, "k1", "--sink", "broker:default", "--secret", "secretKey")
+	assert.Error(t, err, "invalid secret reference 'secretKey', expected format property=secret[/key]")
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--secret", "secretKey=AWS")
+	assert.ErrorContains(t, err, "invalid secret name 'AWS' for property 'secretKey'")
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--secret", "secretKey=aws/a key")
+	assert.ErrorContains(t, err, "invalid secret key 'a key' for property 'secretKey'")
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--secret", "secretKey=aws", "-p", "secretKey=s3cr3t")
+	assert.Error(t, err, "property 'secretKey' is given as plain value and read from a secret, use only one of them")
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--secret", "password=aws")
+	assert.Error(t, err, "unknown property 'password' for Kamelet k1, valid properties are: secretKey")
+
+	recorder.Validate()
+}
+
+func TestBindPropertiesFile(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "message", "string", "The message to send", true)
+	addKameletProperty(kamelet, "period", "integer", "Delay between messages", false)
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+
+	expected := createKameletBindingFor("k1", "k1-binding")
+	expected.Spec.Sink = camelkapis.Endpoint{
+		Ref: &corev1.ObjectReference{
+			Kind:       "Broker",
+			APIVersion: "eventing.knative.dev/v1",
+			Name:       "default",
+			Namespace:  "current",
+		},
+	}
+	setBindingProperties(t, expected, `{"message":"Hello from file","period":2000}`)
+	bindingRecorder.Create(expected, nil)
+
+	_, err := runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", "broker:default",
+		"--properties-file", "testdata/properties.yaml", "--no-wait")
+	assert.NilError(t, err)
+
+	overridden := expected.DeepCopy()
+	setBindingProperties(t, overridden, `{"message":"Hello inline","period":500}`)
+	bindingRecorder.Create(overridden, nil)
+
+	_, err = runBindCmdWithInput(mockClient, `{"message": "Hello from stdin", "period": 500}`, "k1", "--name", "k1-binding",
+		"--sink", "broker:default", "--properties-file", "-", "-p", "message=Hello inline", "--no-wait")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindInteractive(t *testing.T) {
+	isTerminal := isTerminalInput
+	defer func() { isTerminalInput = isTerminal }()
+	isTerminalInput = func(in io.Reader) bool { return true }
+
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "message", "string", "The message to send", true)
+	addKameletProperty(kamelet, "period", "integer", "Delay between messages", true)
+	addKameletProperty(kamelet, "count", "integer", "Number of messages", false)
+	setKameletPropertyDefault(kamelet, "period", "1000")
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+
+	expected := createKameletBindingFor("k1", "k1-binding")
+	expected.Spec.Sink = camelkapis.Endpoint{
+		Ref: &corev1.ObjectReference{
+			Kind:       "Broker",
+			APIVersion: "eventing.knative.dev/v1",
+			Name:       "default",
+			Namespace:  "current",
+		},
+	}
+	setBindingProperties(t, expected, `{"message":"Hello","period":1000}`)
+	bindingRecorder.Create(expected, nil)
+
+	// empty message is asked for again, the invalid period as well before the default is taken
+	_, err := runBindCmdWithInput(mockClient, "\nHello\nsoon\n\n", "k1", "--name", "k1-binding",
+		"--sink", "broker:default", "--interactive", "--no-wait")
+	assert.NilError(t, err)
+
+	_, err = runBindCmdWithInput(mockClient, "Hello\n", "k1", "--name", "k1-binding",
+		"--sink", "broker:default", "-i", "--no-wait")
+	assert.ErrorContains(t, err, "unable to read value for property 'period'")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindErrorCaseInteractive(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+
+	_, err := runBindCmd(mockClient, "k1", "--sink", "broker:default", "--interactive")
+	assert.Error(t, err, "--interactive requires a terminal attached to stdin")
+
+	isTerminal := isTerminalInput
+	defer func() { isTerminalInput = isTerminal }()
+	isTerminalInput = func(in io.Reader) bool { return true }
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--interactive", "--properties-file", "-")
+	assert.Error(t, err, "--interactive can not be combined with reading the properties file from stdin")
+
+	mockClient.Recorder().Validate()
+}
+
+func TestBindErrorCasePropertiesFile(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "message", "string", "The message to send", false)
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+
+	_, err := runBindCmd(mockClient, "k1", "--sink", "broker:default", "--properties-file", "testdata/missing.yaml")
+	assert.ErrorContains(t, err, "unable to read properties file 'testdata/missing.yaml'")
+
+	_, err = runBindCmdWithInput(mockClient, "- message", "k1", "--sink", "broker:default", "--properties-file", "-")
+	assert.ErrorContains(t, err, "invalid properties file '-', expected YAML or JSON object")
+
+	_, err = runBindCmdWithInput(mockClient, "msg: Hello", "k1", "--sink", "broker:default", "--properties-file", "-")
+	assert.Error(t, err, "unknown property 'msg' for Kamelet k1, valid properties are: message")
+
+	recorder.Validate()
+}
+
+func TestBindCloudEventOverrides(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+
+	expected := createKameletBindingFor("k1", "k1-binding")
+	expected.Spec.Sink = camelkapis.Endpoint{
+		Ref: &corev1.ObjectReference{
+			Kind:       "Broker",
+			APIVersion: "eventing.knative.dev/v1",
+			Name:       "default",
+			Namespace:  "current",
+		},
+		Properties: &camelkapis.EndpointProperties{
+			RawMessage: []byte(`{"ce.override.ce-source":"timer","ce.override.ce-type":"dev.example.timer"}`),
+		},
+	}
+	bindingRecorder.Create(expected, nil)
+
+	_, err := runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", "broker:default",
+		"--ce-override", "type=dev.example.timer", "--ce-override", "source=timer", "--no-wait")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindErrorCaseCloudEventOverride(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+	recorder.Get(createKamelet("k1"), nil)
+
+	_, err := runBindCmd(mockClient, "k1", "--sink", "broker:default", "--ce-override", "type")
+	assert.Error(t, err, "invalid CloudEvent override 'type', expected format key=value")
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--ce-override", "Event-Type=timer")
+	assert.Error(t, err, "invalid CloudEvent attribute name 'Event-Type', must consist of lowercase letters and digits only")
+
+	recorder.Validate()
+}
+
+func TestBindErrorCaseInvalidSink(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+
+	_, err := runBindCmd(mockClient, "k1", "--sink", "foo:bar")
+	assert.Error(t, err, "unsupported sink prefix 'foo', supported prefixes are: broker, channel, ksvc or an URI with 'http://' or 'https://' schema")
+	recorder.Validate()
+}
+
+func TestBindToService(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "message", "string", "The message to send", true)
+	addKameletProperty(kamelet, "count", "integer", "Number of messages", false)
+	recorder.Get(kamelet, nil)
+
+	expected := createKameletBindingFor("k1", "k1-binding")
+	expected.Spec.Sink = camelkapis.Endpoint{
+		Ref: &corev1.ObjectReference{
+			Kind:       "Service",
+			APIVersion: "serving.knative.dev/v1",
+			Name:       "my-service",
+			Namespace:  "current",
+		},
+	}
+	setBindingProperties(t, expected, `{"count":10,"message":"Hello=World"}`)
+	bindingRecorder.Create(expected, nil)
+
+	output, err := runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", "ksvc:my-service",
+		"-p", "message=Hello=World", "--property", "count=10", "--no-wait")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "KameletBinding", "k1-binding", "created", "namespace", "current"))
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindToURI(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+
+	uri := "https://event.receiver.uri"
+	expected := createKameletBindingFor("k1", "")
+	expected.GenerateName = "k1-"
+	expected.Spec.Sink = camelkapis.Endpoint{URI: &uri}
+	bindingRecorder.Create(expected, nil)
+
+	_, err := runBindCmd(mockClient, "k1", "--sink", uri, "--no-wait")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindWait(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+
+	uri := "https://event.receiver.uri"
+	expected := createKameletBindingFor("k1", "k1-binding")
+	expected.Spec.Sink = camelkapis.Endpoint{URI: &uri}
+	bindingRecorder.Create(expected, nil)
+
+	creating := expected.DeepCopy()
+	creating.Status.Phase = camelkapis.KameletBindingPhaseCreating
+	creating.Status.Conditions = []camelkapis.KameletBindingCondition{
+		{Type: camelkapis.KameletBindingConditionReady, Status: corev1.ConditionFalse, Reason: "IntegrationPhaseDeploying"},
+	}
+	ready := expected.DeepCopy()
+	ready.Status.Phase = camelkapis.KameletBindingPhaseReady
+	ready.Status.Conditions = []camelkapis.KameletBindingCondition{
+		{Type: camelkapis.KameletBindingConditionReady, Status: corev1.ConditionTrue},
+	}
+	watcher := watch.NewFakeWithChanSize(3, false)
+	watcher.Modify(creating)
+	watcher.Modify(creating)
+	watcher.Modify(ready)
+	bindingRecorder.Watch(watcher, nil)
+
+	output, err := runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", uri)
+	assert.NilError(t, err)
+	outputLines := strings.Split(output, "\n")
+	assert.Check(t, util.ContainsAll(outputLines[0], "KameletBinding 'k1-binding' created in namespace 'current'."))
+	assert.Check(t, util.ContainsAll(outputLines[1], "Waiting for KameletBinding 'k1-binding' to become ready: IntegrationPhaseDeploying (0s/1m0s)"))
+	assert.Check(t, util.ContainsAll(outputLines[2], "KameletBinding 'k1-binding' is ready."))
+	assert.Assert(t, watcher.IsStopped())
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindWaitProgress(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	interval := bindingProgressLogInterval
+	bindingProgressLogInterval = 10 * time.Millisecond
+	defer func() {
+		bindingProgressLogInterval = interval
+	}()
+
+	recorder.Get(createKamelet("k1"), nil)
+	uri := "https://event.receiver.uri"
+	expected := createKameletBindingFor("k1", "k1-binding")
+	expected.Spec.Sink = camelkapis.Endpoint{URI: &uri}
+	bindingRecorder.Create(expected, nil)
+
+	ready := expected.DeepCopy()
+	ready.Status.Conditions = []camelkapis.KameletBindingCondition{
+		{Type: camelkapis.KameletBindingConditionReady, Status: corev1.ConditionTrue},
+	}
+	watcher := watch.NewFake()
+	bindingRecorder.Watch(watcher, nil)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		watcher.Modify(ready)
+	}()
+
+	// without a terminal the progress is printed as additional lines
+	output, err := runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", uri, "--timeout", "2m")
+	assert.NilError(t, err)
+	outputLines := strings.Split(strings.TrimSpace(output), "\n")
+	assert.Assert(t, len(outputLines) > 3, output)
+	assert.Check(t, util.ContainsAll(outputLines[1], "Waiting for KameletBinding 'k1-binding' to become ready... (0s/2m0s)"))
+	assert.Check(t, util.ContainsAll(outputLines[2], "Waiting for KameletBinding 'k1-binding' to become ready... (0s/2m0s)"))
+	assert.Check(t, util.ContainsAll(outputLines[len(outputLines)-1], "KameletBinding 'k1-binding' is ready."))
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindingProgressLine(t *testing.T) {
+	assert.Equal(t, bindingProgressLine("KameletBinding", "foo", "", 12500*time.Millisecond, 2*time.Minute),
+		"Waiting for KameletBinding 'foo' to become ready... (12s/2m0s)")
+	assert.Equal(t, bindingProgressLine("Pipe", "foo", "IntegrationPhaseDeploying", 90*time.Second, 5*time.Minute),
+		"Waiting for Pipe 'foo' to become ready: IntegrationPhaseDeploying (1m30s/5m0s)")
+}
+
+func TestBindWaitFailed(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+
+	uri := "https://event.receiver.uri"
+	expected := createKameletBindingFor("k1", "k1-binding")
+	expected.Spec.Sink = camelkapis.Endpoint{URI: &uri}
+	bindingRecorder.Create(expected, nil)
+
+	failed := expected.DeepCopy()
+	failed.Status.Phase = camelkapis.KameletBindingPhaseError
+	failed.Status.Conditions = []camelkapis.KameletBindingCondition{
+		{Type: camelkapis.KameletBindingConditionReady, Status: corev1.ConditionFalse, Reason: "Error", Message: "sink not found"},
+	}
+	watcher := watch.NewFakeWithChanSize(1, false)
+	watcher.Modify(failed)
+	bindingRecorder.Watch(watcher, nil)
+
+	_, err := runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", uri)
+	assert.Error(t, err, "KameletBinding 'k1-binding' failed: Error : sink not found")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindWaitWindow(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+
+	uri := "https://event.receiver.uri"
+	expected := createKameletBindingFor("k1", "k1-binding")
+	expected.Spec.Sink = camelkapis.Endpoint{URI: &uri}
+	bindingRecorder.Create(expected, nil)
+
+	ready := expected.DeepCopy()
+	ready.Status.Phase = camelkapis.KameletBindingPhaseReady
+	ready.Status.Conditions = []camelkapis.KameletBindingCondition{
+		{Type: camelkapis.KameletBindingConditionReady, Status: corev1.ConditionTrue},
+	}
+	flapped := expected.DeepCopy()
+	flapped.Status.Phase = camelkapis.KameletBindingPhaseCreating
+	flapped.Status.Conditions = []camelkapis.KameletBindingCondition{
+		{Type: camelkapis.KameletBindingConditionReady, Status: corev1.ConditionFalse, Reason: "IntegrationPhaseDeploying"},
+	}
+	watcher := watch.NewFakeWithChanSize(3, false)
+	watcher.Modify(ready)
+	watcher.Modify(flapped)
+	watcher.Modify(ready)
+	bindingRecorder.Watch(watcher, nil)
+
+	output, err := runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", uri, "--wait-window", "20ms")
+	assert.NilError(t, err)
+	outputLines := strings.Split(output, "\n")
+	assert.Check(t, util.ContainsAll(outputLines[1], "Waiting for KameletBinding 'k1-binding' to become ready: ready, checking it stays ready for 20ms"))
+	assert.Check(t, util.ContainsAll(outputLines[2], "Waiting for KameletBinding 'k1-binding' to become ready: IntegrationPhaseDeploying"))
+	assert.Check(t, util.ContainsAll(outputLines[3], "Waiting for KameletBinding 'k1-binding' to become ready: ready, checking it stays ready for 20ms"))
+	assert.Check(t, util.ContainsAll(outputLines[4], "KameletBinding 'k1-binding' is ready."))
+	assert.Assert(t, watcher.IsStopped())
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", uri, "--wait-window", "2m")
+	assert.Error(t, err, "--wait-window 2m0s must be shorter than --timeout 1m0s")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindWaitTimeout(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+
+	uri := "https://event.receiver.uri"
+	expected := createKameletBindingFor("k1", "k1-binding")
+	expected.Spec.Sink = camelkapis.Endpoint{URI: &uri}
+	bindingRecorder.Create(expected, nil)
+
+	creating := expected.DeepCopy()
+	creating.Status.Conditions = []camelkapis.KameletBindingCondition{
+		{Type: camelkapis.KameletBindingConditionReady, Status: corev1.ConditionFalse, Reason: "IntegrationPhaseDeploying"},
+	}
+	watcher := watch.NewFakeWithChanSize(1, false)
+	watcher.Modify(creating)
+	bindingRecorder.Watch(watcher, nil)
+
+	_, err := runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", uri, "--timeout", "10ms")
+	assert.Error(t, err, "timeout after 10ms waiting for KameletBinding k1-binding to become ready: IntegrationPhaseDeploying")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindMultipleSources(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+	recorder.Get(createKamelet("k2"), nil)
+	recorder.Get(createKamelet("k1"), nil)
+	recorder.Get(createKamelet("k2"), nil)
+
+	uri := "https://event.receiver.uri"
+	for _, kameletName := range []string{"k1", "k2"} {
+		expected := createKameletBindingFor(kameletName, "")
+		expected.GenerateName = kameletName + "-"
+		expected.Spec.Sink = camelkapis.Endpoint{URI: &uri}
+		bindingRecorder.Create(expected, nil)
+	}
+	for _, kameletName := range []string{"k1", "k2"} {
+		expected := createKameletBindingFor(kameletName, "fan-in-"+kameletName)
+		expected.Spec.Sink = camelkapis.Endpoint{URI: &uri}
+		bindingRecorder.Create(expected, nil)
+	}
+
+	output, err := runBindCmd(mockClient, "k1", "k2", "--sink", uri, "--no-wait")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "KameletBinding 'k1-"+client.GeneratedNameSuffix+"' created",
+		"KameletBinding 'k2-"+client.GeneratedNameSuffix+"' created"))
+
+	output, err = runBindCmd(mockClient, "k1", "k2", "--name", "fan-in", "--sink", uri, "--no-wait", "-o", "name")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "kameletbinding.camel.apache.org/fan-in-k1\nkameletbinding.camel.apache.org/fan-in-k2\n")
+
+	// long names are shortened like with --name-prefix, invalid names fail before any binding is created
+	recorder.Get(createKamelet("k1"), nil)
+	recorder.Get(createKamelet("k2"), nil)
+	bindingRecorder.Create(mock.Any(), nil)
+	bindingRecorder.Create(mock.Any(), nil)
+	output, err = runBindCmd(mockClient, "k1", "k2", "--name", strings.Repeat("n", 70), "--sink", uri, "--no-wait", "-o", "name")
+	assert.NilError(t, err)
+	for _, name := range strings.Split(strings.TrimSpace(output), "\n") {
+		assert.Assert(t, len(strings.TrimPrefix(name, "kameletbinding.camel.apache.org/")) <= 63, name)
+	}
+
+	recorder.Get(createKamelet("k1"), nil)
+	recorder.Get(createKamelet("k2"), nil)
+	_, err = runBindCmd(mockClient, "k1", "k2", "--name", "Fan_In", "--sink", uri, "--no-wait")
+	assert.ErrorContains(t, err, "invalid binding name 'Fan_In-k1' for --name 'Fan_In'")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindNamePrefix(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+	recorder.Get(createKamelet("k2"), nil)
+
+	uri := "https://event.receiver.uri"
+	for _, kameletName := range []string{"k1", "k2"} {
+		expected := createKameletBindingFor(kameletName, "team-a-"+kameletName)
+		expected.Spec.Sink = camelkapis.Endpoint{URI: &uri}
+		bindingRecorder.Create(expected, nil)
+	}
+
+	output, err := runBindCmd(mockClient, "k1", "k2", "--name-prefix", "team-a", "--sink", uri, "--no-wait", "-o", "name")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "kameletbinding.camel.apache.org/team-a-k1\nkameletbinding.camel.apache.org/team-a-k2\n")
+
+	_, err = runBindCmd(mockClient, "k1", "--name", "fan-in", "--name-prefix", "team-a", "--sink", uri)
+	assert.Error(t, err, "--name and --name-prefix can not be used together")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestPrefixedBindingName(t *testing.T) {
+	name, err := prefixedBindingName("--name-prefix", "team-a", "timer-source")
+	assert.NilError(t, err)
+	assert.Equal(t, name, "team-a-timer-source")
+
+	// long names are truncated deterministically keeping a hash of the full name
+	prefix := strings.Repeat("p", 50)
+	name, err = prefixedBindingName("--name-prefix", prefix, "timer-source")
+	assert.NilError(t, err)
+	assert.Equal(t, len(name), 63)
+	assert.Assert(t, strings.HasPrefix(name, prefix+"-time"))
+	again, err := prefixedBindingName("--name-prefix", prefix, "timer-source")
+	assert.NilError(t, err)
+	assert.Equal(t, again, name)
+	other, err := prefixedBindingName("--name-prefix", prefix, "timer-sink")
+	assert.NilError(t, err)
+	assert.Assert(t, other != name)
+
+	// the truncated name does not end in a dash before the hash
+	name, err = prefixedBindingName("--name-prefix", strings.Repeat("p", 53), "timer-source")
+	assert.NilError(t, err)
+	assert.Assert(t, strings.HasPrefix(name, strings.Repeat("p", 53)+"-"))
+	assert.Assert(t, !strings.Contains(name, "--"))
+
+	_, err = prefixedBindingName("--name-prefix", "Team_A", "timer-source")
+	assert.ErrorContains(t, err, "invalid binding name 'Team_A-timer-source' for --name-prefix 'Team_A': a DNS-1123 label must consist of lower case")
+}
+
+func TestBindMultipleSourcesRollback(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	kamelet3 := createKamelet("k3")
+	kamelet3.Labels[kameletTypeLabel] = kameletTypeSink
+	recorder.Get(createKamelet("k1"), nil)
+	recorder.Get(kamelet3, nil)
+	recorder.Get(createKamelet("k1"), nil)
+	recorder.Get(createKamelet("k2"), nil)
+	recorder.Get(createKamelet("k3"), nil)
+
+	// nothing is created when one of the Kamelets is invalid
+	_, err := runBindCmd(mockClient, "k1", "k3", "--sink", "broker:default", "--no-wait")
+	assert.Error(t, err, "Kamelet k3 is a sink, not a source; use --type sink")
+
+	uri := "https://event.receiver.uri"
+	for _, kameletName := range []string{"k1", "k2", "k3"} {
+		expected := createKameletBindingFor(kameletName, "fan-in-"+kameletName)
+		expected.Spec.Sink = camelkapis.Endpoint{URI: &uri}
+		if kameletName == "k3" {
+			bindingRecorder.Create(expected, errors.New("quota exceeded"))
+		} else {
+			bindingRecorder.Create(expected, nil)
+		}
+	}
+	bindingRecorder.Delete("fan-in-k1", nil)
+	bindingRecorder.Delete("fan-in-k2", errors.New("forbidden"))
+
+	_, err = runBindCmd(mockClient, "k1", "k2", "k3", "--name", "fan-in", "--sink", uri, "--no-wait")
+	assert.Error(t, err, "quota exceeded; rolled back already created bindings: deleted KameletBinding 'fan-in-k1', "+
+		"failed to delete KameletBinding 'fan-in-k2': forbidden")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindOutputName(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+	recorder.Get(createKamelet("k1"), nil)
+	bindingRecorder.Create(mock.Any(), nil)
+	bindingRecorder.Create(mock.Any(), nil)
+
+	output, err := runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", "ksvc:my-service", "-o", "name", "--no-wait")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "kameletbinding.camel.apache.org/k1-binding\n")
+
+	output, err = runBindCmd(mockClient, "k1", "--sink", "ksvc:my-service", "-o", "name", "--no-wait")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "kameletbinding.camel.apache.org/k1-"+client.GeneratedNameSuffix+"\n")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindQuiet(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+	recorder.Get(createKamelet("k1"), nil)
+	recorder.Get(createKamelet("k1"), nil)
+	bindingRecorder.Create(mock.Any(), nil)
+	bindingRecorder.Create(mock.Any(), nil)
+
+	p := &KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewKameletClient: func() (KameletClient, error) {
+			return mockClient, nil
+		},
+		NewKameletBindingClient: mockBindingClient(mockClient),
+		NewDiscoveryClient: func() (discovery.ServerResourcesInterface, error) {
+			return newKameletBindingDiscovery(), nil
+		},
+		Quiet: true,
+	}
+
+	output, err := runBindCmdWithParams(p, "", "k1", "--name", "k1-binding", "--sink", "ksvc:my-service", "--no-wait")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "")
+
+	output, err = runBindCmdWithParams(p, "", "k1", "--name", "k1-binding", "--sink", "ksvc:my-service", "-o", "name", "--no-wait")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "kameletbinding.camel.apache.org/k1-binding\n")
+
+	// errors are reported in quiet mode as well
+	_, err = runBindCmdWithParams(p, "", "k1", "--sink", "svc:receiver")
+	assert.ErrorContains(t, err, "unsupported sink prefix 'svc'")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindTrigger(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+	eventingClient := clienteventingv1.NewMockKnEventingClient(t, "current")
+	eventingRecorder := eventingClient.Recorder()
+
+	p := &KameletPluginParams{
+		KnParams: &commands.KnParams{
+			NewEventingClient: func(namespace string) (clienteventingv1.KnEventingClient, error) {
+				assert.Equal(t, namespace, "current")
+				return eventingClient, nil
+			},
+		},
+		Context: context.TODO(),
+		NewKameletClient: func() (KameletClient, error) {
+			return mockClient, nil
+		},
+		NewKameletBindingClient: mockBindingClient(mockClient),
+		NewDiscoveryClient: func() (discovery.ServerResourcesInterface, error) {
+			return newKameletBindingDiscovery(), nil
+		},
+	}
+
+	_, err := runBindCmdWithParams(p, "", "k1", "--sink", "broker:default", "--trigger-filter", "type")
+	assert.Error(t, err, "invalid Trigger filter 'type', expected format attribute=value")
+	_, err = runBindCmdWithParams(p, "", "k1", "--sink", "broker:default", "--trigger-filter", "Type=dev.example.timer")
+	assert.Error(t, err, "invalid Trigger filter 'Type=dev.example.timer', the CloudEvent attribute name must consist of "+
+		"lower-case letters and digits")
+	_, err = runBindCmdWithParams(p, "", "k1", "--sink", "broker:default", "--trigger-filter", "type=dev.example.timer")
+	assert.Error(t, err, "--trigger-filter requires the subscriber of the Trigger given with --trigger-subscriber")
+
+	expected := createKameletBindingFor("k1", "k1-binding")
+	expected.Spec.Sink = camelkapis.Endpoint{
+		Ref: &corev1.ObjectReference{
+			Kind:       "Broker",
+			APIVersion: "eventing.knative.dev/v1",
+			Name:       "default",
+			Namespace:  "current",
+		},
+	}
+	trigger := clienteventingv1.NewTriggerBuilder("k1-binding").
+		Namespace("current").
+		Broker("default").
+		Subscriber(&duckv1.Destination{
+			Ref: &duckv1.KReference{Kind: "Service", APIVersion: "serving.knative.dev/v1", Name: "my-service", Namespace: "current"},
+		}).
+		Filters(map[string]string{"type": "dev.example.timer", "source": "timer"}).
+		Build()
+	trigger.Labels = map[string]string{managedByLabel: managedByValue}
+
+	triggerNotFound := apierrors.NewNotFound(schema.GroupResource{Group: "eventing.knative.dev", Resource: "triggers"}, "k1-binding")
+	recorder.Get(createKamelet("k1"), nil)
+	eventingRecorder.GetTrigger("k1-binding", nil, triggerNotFound)
+	bindingRecorder.Create(expected, nil)
+	eventingRecorder.CreateTrigger(trigger, nil)
+	output, err := runBindCmdWithParams(p, "", "k1", "--name", "k1-binding", "--sink", "broker:default",
+		"--trigger-filter", "type=dev.example.timer", "--trigger-filter", "source=timer", "--trigger-subscriber", "ksvc:my-service", "--no-wait")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "KameletBinding 'k1-binding' created in namespace 'current'.",
+		"Trigger 'k1-binding' created in namespace 'current'."))
+
+	// the binding is rolled back when the Trigger can not be created
+	recorder.Get(createKamelet("k1"), nil)
+	eventingRecorder.GetTrigger("k1-binding", nil, triggerNotFound)
+	bindingRecorder.Create(expected, nil)
+	eventingRecorder.CreateTrigger(trigger, errors.New("forbidden"))
+	bindingRecorder.Delete("k1-binding", nil)
+	_, err = runBindCmdWithParams(p, "", "k1", "--name", "k1-binding", "--sink", "broker:default",
+		"--trigger-filter", "type=dev.example.timer", "--trigger-filter", "source=timer", "--trigger-subscriber", "ksvc:my-service", "--no-wait")
+	assert.Error(t, err, "unable to create Trigger 'k1-binding' in namespace 'current': forbidden; rolled back the bindings: "+
+		"deleted KameletBinding 'k1-binding'")
+
+	// without --name the Trigger is named after the binding name generated by the API server, no lookup is needed
+	generated := expected.DeepCopy()
+	generated.Name = ""
+	generated.GenerateName = "k1-"
+	generatedTrigger := trigger.DeepCopy()
+	generatedTrigger.Name = "k1-" + client.GeneratedNameSuffix
+	recorder.Get(createKamelet("k1"), nil)
+	bindingRecorder.Create(generated, nil)
+	eventingRecorder.CreateTrigger(generatedTrigger, nil)
+	output, err = runBindCmdWithParams(p, "", "k1", "--sink", "broker:default",
+		"--trigger-filter", "type=dev.example.timer", "--trigger-filter", "source=timer", "--trigger-subscriber", "ksvc:my-service", "--no-wait")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "KameletBinding 'k1-"+client.GeneratedNameSuffix+"' created in namespace 'current'.",
+		"Trigger 'k1-"+client.GeneratedNameSuffix+"' created in namespace 'current'."))
+
+	// the Trigger flags are ignored for other sinks
+	recorder.Get(createKamelet("k1"), nil)
+	bindingRecorder.Create(mock.Any(), nil)
+	output, err = runBindCmdWithParams(p, "", "k1", "--name", "k1-binding", "--sink", "ksvc:my-service",
+		"--trigger-filter", "type=dev.example.timer", "--no-wait")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsNone(output, "Trigger"))
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+	eventingRecorder.Validate()
+}
+
+func TestBindReplaceTrigger(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+	mockEventingClient := clienteventingv1.NewMockKnEventingClient(t, "current")
+	eventingRecorder := mockEventingClient.Recorder()
+	eventingClient := &updateCapturingEventingClient{KnEventingClient: mockEventingClient}
+
+	p := &KameletPluginParams{
+		KnParams: &commands.KnParams{
+			NewEventingClient: func(namespace string) (clienteventingv1.KnEventingClient, error) {
+				return eventingClient, nil
+			},
+		},
+		Context: context.TODO(),
+		NewKameletClient: func() (KameletClient, error) {
+			return mockClient, nil
+		},
+		NewKameletBindingClient: mockBindingClient(mockClient),
+		NewDiscoveryClient: func() (discovery.ServerResourcesInterface, error) {
+			return newKameletBindingDiscovery(), nil
+		},
+	}
+
+	alreadyExists := apierrors.NewAlreadyExists(schema.GroupResource{Group: "camel.apache.org", Resource: "kameletbindings"}, "k1-binding")
+	existing := createKameletBindingFor("k1", "k1-binding")
+	existing.ResourceVersion = "42"
+	existingTrigger := clienteventingv1.NewTriggerBuilder("k1-binding").
+		Namespace("current").
+		Broker("default").
+		Filters(map[string]string{"type": "dev.example.old"}).
+		Build()
+	existingTrigger.Labels = map[string]string{managedByLabel: managedByValue}
+	existingTrigger.ResourceVersion = "7"
+
+	// the Trigger of the binding created before is updated along with the replaced binding
+	recorder.Get(createKamelet("k1"), nil)
+	eventingRecorder.GetTrigger("k1-binding", existingTrigger, nil)
+	bindingRecorder.Create(mock.Any(), alreadyExists)
+	bindingRecorder.Get("k1-binding", existing, nil)
+	bindingRecorder.Update(mock.Any(), nil)
+	eventingRecorder.UpdateTrigger(mock.Any(), nil)
+	output, err := runBindCmdWithParams(p, "", "k1", "--name", "k1-binding", "--sink", "broker:default", "--replace",
+		"--trigger-filter", "type=dev.example.timer", "--trigger-subscriber", "ksvc:my-service", "--no-wait")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "KameletBinding 'k1-binding' replaced in namespace 'current'.",
+		"Trigger 'k1-binding' updated in namespace 'current'."))
+	assert.Equal(t, eventingClient.updated.ResourceVersion, "7")
+	assert.DeepEqual(t, eventingClient.updated.Spec.Filter.Attributes, eventingv1.TriggerFilterAttributes{"type": "dev.example.timer"})
+	assert.Equal(t, eventingClient.updated.Spec.Subscriber.Ref.Name, "my-service")
+
+	// a Trigger not managed by the plugin fails the bind before the binding gets replaced
+	existingTrigger.Labels = nil
+	recorder.Get(createKamelet("k1"), nil)
+	eventingRecorder.GetTrigger("k1-binding", existingTrigger, nil)
+	_, err = runBindCmdWithParams(p, "", "k1", "--name", "k1-binding", "--sink", "broker:default", "--replace",
+		"--trigger-filter", "type=dev.example.timer", "--trigger-subscriber", "ksvc:my-service", "--no-wait")
+	assert.Error(t, err, "Trigger 'k1-binding' already exists in namespace 'current' and is not managed by kn-source-kamelet, "+
+		"delete it or choose another binding name with --name")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+	eventingRecorder.Validate()
+}
+
+// updateCapturingEventingClient keeps the Trigger passed to UpdateTrigger, which the mock does not verify
+type updateCapturingEventingClient struct {
+	clienteventingv1.KnEventingClient
+	updated *eventingv1.Trigger
+}
+
+func (c *updateCapturingEventingClient) UpdateTrigger(ctx context.Context, trigger *eventingv1.Trigger) error {
+	c.updated = trigger
+	return c.KnEventingClient.UpdateTrigger(ctx, trigger)
+}
+
+func TestBindOutputYAML(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "period", "integer", "Delay between messages", false)
+	recorder.Get(kamelet, nil)
+
+	output, err := runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", "ksvc:my-service", "-p", "period=1000", "-o", "yaml")
+	assert.NilError(t, err)
+
+	// the printed manifest round-trips through the camel-k scheme
+	obj, gvk, err := scheme.Codecs.UniversalDeserializer().Decode([]byte(output), nil, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, gvk.Kind, camelkapis.KameletBindingKind)
+	binding, ok := obj.(*camelkapis.KameletBinding)
+	assert.Assert(t, ok)
+	assert.Equal(t, binding.Name, "k1-binding")
+	assert.Equal(t, binding.Namespace, "current")
+	assert.Equal(t, binding.Spec.Source.Ref.Name, "k1")
+	assert.Equal(t, string(binding.Spec.Source.Properties.RawMessage), `{"period":1000}`)
+	assert.Equal(t, binding.Spec.Sink.Ref.Kind, "Service")
+	assert.Equal(t, binding.Spec.Sink.Ref.Name, "my-service")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindOutputJSON(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+	recorder.Get(createKamelet("k1"), nil)
+	recorder.Get(createKamelet("k2"), nil)
+
+	output, err := runBindCmd(mockClient, "k1", "--sink", "broker:default", "-o", "json")
+	assert.NilError(t, err)
+	obj, _, err := scheme.Codecs.UniversalDeserializer().Decode([]byte(output), nil, nil)
+	assert.NilError(t, err)
+	binding, ok := obj.(*camelkapis.KameletBinding)
+	assert.Assert(t, ok)
+	assert.Equal(t, binding.GenerateName, "k1-")
+
+	// several bindings are printed as list
+	output, err = runBindCmd(mockClient, "k1", "k2", "--name", "fan-in", "--sink", "broker:default", "-o", "json")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, `"kind": "List"`, `"name": "fan-in-k1"`, `"name": "fan-in-k2"`))
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindOutputYAMLDryRunServer(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+	bindingRecorder.CreateWithOptions(mock.Any(), v1.CreateOptions{DryRun: []string{v1.DryRunAll},
+		FieldManager: defaultFieldManager}, nil)
+
+	output, err := runBindCmd(mockClient, "k1", "--sink", "broker:default", "-o", "yaml", "--dry-run=server")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "kind: KameletBinding", "name: k1-"+client.GeneratedNameSuffix))
+	assert.Assert(t, util.ContainsNone(output, "created"))
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindPipe(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "message", "string", "The message to send", true)
+	recorder.Get(kamelet, nil)
+
+	pipeClient := newFakePipeClient()
+	p := &KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewKameletClient: func() (KameletClient, error) {
+			return mockClient, nil
+		},
+		NewKameletBindingClient: mockBindingClient(mockClient),
+		NewPipeClient: func() (dynamic.Interface, error) {
+			return pipeClient, nil
+		},
+		NewDiscoveryClient: func() (discovery.ServerResourcesInterface, error) {
+			return newPipeDiscovery(), nil
+		},
+	}
+
+	// the cluster serves pipes, so no Kamelet binding is created
+	output, err := runBindCmdWithParams(p, "", "k1", "--name", "k1-binding", "--sink", "broker:default", "-p", "message=Hello",
+		"--no-wait", "-o", "name")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "pipe.camel.apache.org/k1-binding\n")
+
+	pipe, err := pipeClient.Resource(pipeResource).Namespace("current").Get(context.TODO(), "k1-binding", v1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, pipe.GetKind(), pipeKind)
+	assert.Equal(t, pipe.GetAPIVersion(), "camel.apache.org/v1")
+	message, _, _ := unstructured.NestedString(pipe.Object, "spec", "source", "properties", "message")
+	assert.Equal(t, message, "Hello")
+	sinkKind, _, _ := unstructured.NestedString(pipe.Object, "spec", "sink", "ref", "kind")
+	assert.Equal(t, sinkKind, "Broker")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindAPI(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+	recorder.Get(createKamelet("k1"), nil)
+	recorder.Get(createKamelet("k1"), nil)
+	bindingRecorder.Create(mock.Any(), nil)
+
+	// printing a pipe does not need the cluster to serve pipes
+	output, err := runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", "broker:default", "--api", "pipe", "-o", "yaml")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "apiVersion: camel.apache.org/v1\n", "kind: Pipe", "name: k1-binding"))
+
+	output, err = runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", "broker:default", "--api", "kameletbinding", "--no-wait")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "KameletBinding 'k1-binding' created in namespace 'current'.\n")
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--api", "integration")
+	assert.Error(t, err, "invalid API 'integration', must be one of: auto, kameletbinding, pipe")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindErrorCaseInvalidOutput(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+
+	_, err := runBindCmd(mockClient, "k1", "--sink", "ksvc:my-service", "-o", "wide")
+	assert.Error(t, err, "invalid output format 'wide', must be one of: name, yaml, json, json-summary")
+	mockClient.Recorder().Validate()
+}
+
+func TestBindDryRunClient(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "message", "string", "The message to send", true)
+	recorder.Get(kamelet, nil)
+
+	output, err := runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", "broker:default", "-p", "message=Hello", "--dry-run=client")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "apiVersion: camel.apache.org/v1alpha1", "kind: KameletBinding",
+		"name: k1-binding", "message: Hello", "kind: Broker"))
+	assert.Assert(t, util.ContainsNone(output, "created"))
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindDryRunServer(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+	bindingRecorder.CreateWithOptions(mock.Any(), v1.CreateOptions{DryRun: []string{v1.DryRunAll},
+		FieldManager: defaultFieldManager}, nil)
+
+	// no watch is recorded as a server side dry run never waits for the binding
+	output, err := runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", "broker:default", "--dry-run=server")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "KameletBinding 'k1-binding' created in namespace 'current' (server dry run).\n")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindErrorCaseDryRun(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+
+	_, err := runBindCmd(mockClient, "k1", "--sink", "broker:default", "--dry-run=local")
+	assert.Error(t, err, "invalid dry run value 'local', must be one of: none, client, server")
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--dry-run=client", "-o", "name")
+	assert.Error(t, err, "--dry-run=client can not be combined with --output name")
+
+	mockClient.Recorder().Validate()
+}
+
+func TestBindErrorCaseCreate(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+	bindingRecorder.Create(mock.Any(), errors.New("forbidden"))
+
+	_, err := runBindCmd(mockClient, "k1", "--sink", "broker:default")
+	assert.Error(t, err, "forbidden")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func runBindCmd(c *client.MockKameletClient, options ...string) (string, error) {
+	return runBindCmdWithInput(c, "", options...)
+}
+
+func runBindCmdWithInput(c *client.MockKameletClient, input string, options ...string) (string, error) {
+	p := &KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewKameletClient: func() (KameletClient, error) {
+			return c, nil
+		},
+		NewKameletBindingClient: mockBindingClient(c),
+		NewDiscoveryClient: func() (discovery.ServerResourcesInterface, error) {
+			return newKameletBindingDiscovery(), nil
+		},
+	}
+	return runBindCmdWithParams(p, input, options...)
+}
+
+func runBindCmdWithParams(p *KameletPluginParams, input string, options ...string) (string, error) {
+	bindCmd, _, output := commands.CreateSourcesTestKnCommand(NewBindCommand(p), p.KnParams)
+
+	args := []string{"bind"}
+	args = append(args, options...)
+	bindCmd.SetArgs(args)
+	bindCmd.SetIn(strings.NewReader(input))
+	err := bindCmd.Execute()
+
+	return output.String(), err
+}
+
+func TestBindReplace(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	alreadyExists := apierrors.NewAlreadyExists(schema.GroupResource{Group: "camel.apache.org", Resource: "kameletbindings"}, "k1-binding")
+	existing := createKameletBindingFor("k1", "k1-binding")
+	existing.ResourceVersion = "42"
+	expected := createKameletBindingFor("k1", "k1-binding")
+	expected.ResourceVersion = "42"
+	expected.Spec.Sink = camelkapis.Endpoint{
+		Ref: &corev1.ObjectReference{
+			Kind:       "Broker",
+			APIVersion: "eventing.knative.dev/v1",
+			Name:       "default",
+			Namespace:  "current",
+		},
+	}
+
+	recorder.Get(createKamelet("k1"), nil)
+	bindingRecorder.Create(mock.Any(), alreadyExists)
+	bindingRecorder.Get("k1-binding", existing, nil)
+	bindingRecorder.Update(expected, nil)
+
+	output, err := runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", "broker:default", "--replace", "--no-wait")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "KameletBinding 'k1-binding' replaced in namespace 'current'.\n")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindReplaceNotManaged(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	alreadyExists := apierrors.NewAlreadyExists(schema.GroupResource{Group: "camel.apache.org", Resource: "kameletbindings"}, "k1-binding")
+	existing := createKameletBindingFor("k1", "k1-binding")
+	existing.Labels = nil
+
+	recorder.Get(createKamelet("k1"), nil)
+	bindingRecorder.Create(mock.Any(), alreadyExists)
+	bindingRecorder.Get("k1-binding", existing, nil)
+
+	output, err := runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", "broker:default", "--replace", "--no-wait")
+	assert.ErrorContains(t, err, "KameletBinding 'k1-binding' in namespace 'current' has not been created by kn-source-kamelet, "+
+		"use --force to replace it anyway")
+	assert.Assert(t, util.ContainsNone(output, "replaced"))
+
+	recorder.Get(createKamelet("k1"), nil)
+	bindingRecorder.Create(mock.Any(), alreadyExists)
+	bindingRecorder.Get("k1-binding", existing, nil)
+	bindingRecorder.Update(mock.Any(), nil)
+
+	output, err = runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", "broker:default", "--replace", "--force", "--no-wait")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "KameletBinding 'k1-binding' replaced in namespace 'current'.\n")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindErrorCaseAlreadyExists(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+	bindingRecorder.Create(mock.Any(), apierrors.NewAlreadyExists(schema.GroupResource{Group: "camel.apache.org", Resource: "kameletbindings"}, "k1-binding"))
+
+	_, err := runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", "broker:default", "--no-wait")
+	assert.ErrorContains(t, err, "KameletBinding 'k1-binding' already exists in namespace 'current', "+
+		"use --replace to replace it or 'kn-source-kamelet update' to change it")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindLabelsAndAnnotations(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+	expected := createKameletBindingFor("k1", "k1-binding")
+	uri := "https://event.receiver.uri"
+	expected.Spec.Sink = camelkapis.Endpoint{URI: &uri}
+	expected.Labels["team"] = "payments"
+	expected.Labels["example.com/cost-center"] = "42"
+	expected.Annotations = map[string]string{"owner": "jane@example.com"}
+	bindingRecorder.Create(expected, nil)
+
+	_, err := runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", uri, "--no-wait",
+		"--label", "team=payments", "--label", "example.com/cost-center=42", "--annotation", "owner=jane@example.com")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindServiceAccount(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+	expected := createKameletBindingFor("k1", "k1-binding")
+	uri := "https://event.receiver.uri"
+	expected.Spec.Sink = camelkapis.Endpoint{URI: &uri}
+	expected.Spec.Integration = &camelkapisv1.IntegrationSpec{ServiceAccountName: "timer-runner"}
+	bindingRecorder.Create(expected, nil)
+
+	_, err := runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", uri, "--no-wait",
+		"--service-account", "timer-runner")
+	assert.NilError(t, err)
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", uri, "--service-account", "Timer_Runner")
+	assert.ErrorContains(t, err, "invalid service account 'Timer_Runner': a DNS-1123 subdomain must consist of lower case")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindTraits(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+	expected := createKameletBindingFor("k1", "k1-binding")
+	uri := "https://event.receiver.uri"
+	expected.Spec.Sink = camelkapis.Endpoint{URI: &uri}
+	expected.Spec.Integration = &camelkapisv1.IntegrationSpec{
+		Traits: map[string]camelkapisv1.TraitSpec{
+			"container": traitSpec(`{"limit-memory":"256Mi"}`),
+			"mount":     traitSpec(`{"configs":["configmap:a","configmap:b"]}`),
+			"future":    traitSpec(`{"enabled":"true"}`),
+		},
+	}
+	bindingRecorder.Create(expected, nil)
+
+	_, err := runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", uri, "--no-wait",
+		"--trait", "container.limit-memory=256Mi", "--trait", "mount.configs=configmap:a",
+		"--trait", "mount.configs=configmap:b", "--trait", "future.enabled=true")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindErrorCaseTraits(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+
+	_, err := runBindCmd(mockClient, "k1", "--sink", "broker:default", "--trait", "container.limit-memory")
+	assert.Error(t, err, "invalid trait 'container.limit-memory', expected format trait.property=value")
+
+	for _, key := range []string{"container", "container.", ".limit-memory", "Container.limit-memory", "a.b.c"} {
+		_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--trait", key+"=1")
+		assert.Error(t, err, "invalid trait '"+key+"=1', the key must be given as trait.property, e.g. container.limit-memory")
+	}
+}
+
+func traitSpec(configuration string) camelkapisv1.TraitSpec {
+	return camelkapisv1.TraitSpec{
+		Configuration: camelkapisv1.TraitConfiguration{RawMessage: camelkapisv1.RawMessage(configuration)},
+	}
+}
+
+func TestVerifyServiceAccountKept(t *testing.T) {
+	binding := createKameletBindingFor("k1", "k1-binding")
+	assert.NilError(t, verifyServiceAccountKept(&kameletBindingClient{}, binding, binding))
+
+	binding.Spec.Integration = &camelkapisv1.IntegrationSpec{ServiceAccountName: "timer-runner"}
+	assert.NilError(t, verifyServiceAccountKept(&kameletBindingClient{}, binding, binding.DeepCopy()))
+
+	pruned := createKameletBindingFor("k1", "k1-binding")
+	assert.Error(t, verifyServiceAccountKept(&pipeClient{}, binding, pruned), "the Pipe API of the cluster does not "+
+		"support the service account of the integration, 'timer-runner' has been dropped from Pipe 'k1-binding', "+
+		"bind without --service-account or use another --api")
+}
+
+func TestBindErrorCaseLabels(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+
+	_, err := runBindCmd(mockClient, "k1", "--sink", "broker:default", "--label", "team")
+	assert.Error(t, err, "invalid label 'team', expected format key=value")
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--label", "-team=payments")
+	assert.ErrorContains(t, err, "invalid label key '-team': name part must consist of alphanumeric characters")
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--label", "team=payments team")
+	assert.ErrorContains(t, err, "invalid value 'payments team' for label 'team'")
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--label", managedByLabel+"=me")
+	assert.Error(t, err, "invalid label 'app.kubernetes.io/managed-by', the label is set by kn-source-kamelet")
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--annotation", "owner/of/it=jane")
+	assert.ErrorContains(t, err, "invalid annotation key 'owner/of/it'")
+
+	mockClient.Recorder().Validate()
+}
@@ -0,0 +1,415 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	camelkapisv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	"github.com/spf13/pflag"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	bindingAPIAuto           = "auto"
+	bindingAPIKameletBinding = "kameletbinding"
+	bindingAPIPipe           = "pipe"
+
+	// pipeKind is the kind replacing KameletBinding in the camel.apache.org/v1 API of newer Camel K versions
+	pipeKind = "Pipe"
+)
+
+// pipeGroupVersion is the API group version serving pipes
+var pipeGroupVersion = schema.GroupVersion{Group: v1alpha1.SchemeGroupVersion.Group, Version: "v1"}
+
+// pipeResource is the resource of pipes used with the dynamic client
+var pipeResource = pipeGroupVersion.WithResource("pipes")
+
+// addBindingAPIFlag adds the flag selecting the API used for binding Kamelets
+func addBindingAPIFlag(flags *pflag.FlagSet, api *string) {
+	flags.StringVar(api, "api", bindingAPIAuto, "API used for binding the Kamelet. One of: auto|kameletbinding|pipe. "+
+		"With 'auto' a Pipe is created when the cluster serves the camel.apache.org/v1 Pipe API, a KameletBinding otherwise. "+
+		"The served APIs are cached for 10 minutes, see --cache-dir.")
+}
+
+// addExistingBindingAPIFlag adds the flag selecting the API of the existing bindings a command operates on, the
+// API is resolved the same way as for bind so that the objects created by bind are found again
+func addExistingBindingAPIFlag(flags *pflag.FlagSet, api *string) {
+	flags.StringVar(api, "api", bindingAPIAuto, "API of the Kamelet bindings. One of: auto|kameletbinding|pipe. "+
+		"With 'auto' Pipes are used when the cluster serves the camel.apache.org/v1 Pipe API, KameletBindings otherwise.")
+}
+
+// resolveBindingAPI returns the binding API to use for given flag value, detecting the available API via discovery
+// when set to auto. Cached discovery results are dropped first if refresh is set.
+func (params *KameletPluginParams) resolveBindingAPI(api string, refresh bool) (string, error) {
+	switch api {
+	case bindingAPIKameletBinding, bindingAPIPipe:
+		return api, nil
+	case bindingAPIAuto:
+		discoveryClient, err := params.NewDiscoveryClient()
+		if err != nil {
+			return "", err
+		}
+		if cached, ok := discoveryClient.(discovery.CachedDiscoveryInterface); ok && refresh {
+			cached.Invalidate()
+		}
+		return detectBindingAPI(discoveryClient)
+	default:
+		return "", fmt.Errorf("invalid API '%s', must be one of: %s, %s, %s", api, bindingAPIAuto, bindingAPIKameletBinding, bindingAPIPipe)
+	}
+}
+
+// detectBindingAPI returns pipe if the cluster serves the Pipe API, kameletbinding otherwise
+func detectBindingAPI(discoveryClient discovery.ServerResourcesInterface) (string, error) {
+	resources, err := discoveryClient.ServerResourcesForGroupVersion(pipeGroupVersion.String())
+	if apierrors.IsNotFound(err) {
+		return bindingAPIKameletBinding, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to discover the API for binding Kamelets: %w", err)
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Kind == pipeKind {
+			return bindingAPIPipe, nil
+		}
+	}
+	return bindingAPIKameletBinding, nil
+}
+
+// bindingClient creates, patches, deletes and watches the objects binding Kamelets to sinks. Implementations exist for
+// Kamelet bindings and for pipes, both are exchanged as Kamelet bindings as they share the same layout.
+type bindingClient interface {
+	// kind returns the kind of the objects managed by the client
+	kind() string
+	// serialize converts given Kamelet binding into the object sent to the API server
+	serialize(binding *v1alpha1.KameletBinding) (runtime.Object, error)
+	get(ctx context.Context, namespace string, name string) (*v1alpha1.KameletBinding, error)
+	list(ctx context.Context, namespace string, opts v1.ListOptions) (*v1alpha1.KameletBindingList, error)
+	create(ctx context.Context, binding *v1alpha1.KameletBinding, opts v1.CreateOptions) (*v1alpha1.KameletBinding, error)
+	update(ctx context.Context, binding *v1alpha1.KameletBinding, opts v1.UpdateOptions) (*v1alpha1.KameletBinding, error)
+	// patch applies given patch to the object with given name, Kamelet bindings and pipes share the patched fields
+	patch(ctx context.Context, namespace string, name string, pt types.PatchType, data []byte, opts v1.PatchOptions) (*v1alpha1.KameletBinding, error)
+	delete(ctx context.Context, namespace string, name string, opts v1.DeleteOptions) error
+	// watch returns a watcher emitting the watched objects as Kamelet bindings
+	watch(ctx context.Context, namespace string, opts v1.ListOptions) (watch.Interface, error)
+}
+
+// newBindingClient returns the binding client for given resolved binding API
+func (params *KameletPluginParams) newBindingClient(api string) bindingClient {
+	if api != bindingAPIPipe {
+		return &kameletBindingClient{newClient: params.NewKameletBindingClient}
+	}
+	return &pipeClient{newClient: params.NewPipeClient}
+}
+
+// resourceName returns the type qualified name of given object as printed with -o name
+func resourceName(client bindingClient, name string) string {
+	return fmt.Sprintf("%s.%s/%s", strings.ToLower(client.kind()), v1alpha1.SchemeGroupVersion.Group, name)
+}
+
+// kameletBindingClient manages camel.apache.org/v1alpha1 Kamelet bindings, the generated client is created on first
+// use like the client of the pipes
+type kameletBindingClient struct {
+	newClient func() (camelkv1alpha1.KameletBindingsGetter, error)
+	client    camelkv1alpha1.KameletBindingsGetter
+}
+
+// kameletBindings returns the generated client for the Kamelet bindings in given namespace
+func (c *kameletBindingClient) kameletBindings(namespace string) (camelkv1alpha1.KameletBindingInterface, error) {
+	if c.client == nil {
+		client, err := c.newClient()
+		if err != nil {
+			return nil, err
+		}
+		c.client = client
+	}
+	return c.client.KameletBindings(namespace), nil
+}
+
+func (c *kameletBindingClient) kind() string {
+	return v1alpha1.KameletBindingKind
+}
+
+func (c *kameletBindingClient) serialize(binding *v1alpha1.KameletBinding) (runtime.Object, error) {
+	binding = binding.DeepCopy()
+	binding.SetGroupVersionKind(v1alpha1.SchemeGroupVersion.WithKind(v1alpha1.KameletBindingKind))
+	return binding, nil
+}
+
+func (c *kameletBindingClient) get(ctx context.Context, namespace string, name string) (*v1alpha1.KameletBinding, error) {
+	bindings, err := c.kameletBindings(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return bindings.Get(ctx, name, v1.GetOptions{})
+}
+
+func (c *kameletBindingClient) list(ctx context.Context, namespace string, opts v1.ListOptions) (*v1alpha1.KameletBindingList, error) {
+	bindings, err := c.kameletBindings(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return bindings.List(ctx, opts)
+}
+
+func (c *kameletBindingClient) patch(ctx context.Context, namespace string, name string, pt types.PatchType, data []byte,
+	opts v1.PatchOptions) (*v1alpha1.KameletBinding, error) {
+	bindings, err := c.kameletBindings(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return bindings.Patch(ctx, name, pt, data, opts)
+}
+
+func (c *kameletBindingClient) update(ctx context.Context, binding *v1alpha1.KameletBinding, opts v1.UpdateOptions) (*v1alpha1.KameletBinding, error) {
+	bindings, err := c.kameletBindings(binding.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return bindings.Update(ctx, binding, opts)
+}
+
+func (c *kameletBindingClient) create(ctx context.Context, binding *v1alpha1.KameletBinding, opts v1.CreateOptions) (*v1alpha1.KameletBinding, error) {
+	bindings, err := c.kameletBindings(binding.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return bindings.Create(ctx, binding, opts)
+}
+
+func (c *kameletBindingClient) delete(ctx context.Context, namespace string, name string, opts v1.DeleteOptions) error {
+	bindings, err := c.kameletBindings(namespace)
+	if err != nil {
+		return err
+	}
+	return bindings.Delete(ctx, name, opts)
+}
+
+func (c *kameletBindingClient) watch(ctx context.Context, namespace string, opts v1.ListOptions) (watch.Interface, error) {
+	bindings, err := c.kameletBindings(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return bindings.Watch(ctx, opts)
+}
+
+// pipeClient manages camel.apache.org/v1 pipes using the dynamic client, which is created on first use so that
+// pipes can be serialized without connecting to the cluster
+type pipeClient struct {
+	newClient func() (dynamic.Interface, error)
+	client    dynamic.Interface
+}
+
+// pipes returns the dynamic client for the pipes in given namespace
+func (c *pipeClient) pipes(namespace string) (dynamic.ResourceInterface, error) {
+	if c.client == nil {
+		client, err := c.newClient()
+		if err != nil {
+			return nil, err
+		}
+		c.client = client
+	}
+	return c.client.Resource(pipeResource).Namespace(namespace), nil
+}
+
+func (c *pipeClient) kind() string {
+	return pipeKind
+}
+
+func (c *pipeClient) serialize(binding *v1alpha1.KameletBinding) (runtime.Object, error) {
+	return asPipe(binding)
+}
+
+func (c *pipeClient) get(ctx context.Context, namespace string, name string) (*v1alpha1.KameletBinding, error) {
+	pipes, err := c.pipes(namespace)
+	if err != nil {
+		return nil, err
+	}
+	pipe, err := pipes.Get(ctx, name, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return fromPipe(pipe)
+}
+
+func (c *pipeClient) list(ctx context.Context, namespace string, opts v1.ListOptions) (*v1alpha1.KameletBindingList, error) {
+	pipes, err := c.pipes(namespace)
+	if err != nil {
+		return nil, err
+	}
+	pipeList, err := pipes.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	bindingList := &v1alpha1.KameletBindingList{ListMeta: v1.ListMeta{ResourceVersion: pipeList.GetResourceVersion()}}
+	for i := range pipeList.Items {
+		binding, err := fromPipe(&pipeList.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		bindingList.Items = append(bindingList.Items, *binding)
+	}
+	return bindingList, nil
+}
+
+func (c *pipeClient) patch(ctx context.Context, namespace string, name string, pt types.PatchType, data []byte,
+	opts v1.PatchOptions) (*v1alpha1.KameletBinding, error) {
+	pipes, err := c.pipes(namespace)
+	if err != nil {
+		return nil, err
+	}
+	patched, err := pipes.Patch(ctx, name, pt, data, opts)
+	if err != nil {
+		return nil, err
+	}
+	return fromPipe(patched)
+}
+
+func (c *pipeClient) update(ctx context.Context, binding *v1alpha1.KameletBinding, opts v1.UpdateOptions) (*v1alpha1.KameletBinding, error) {
+	pipe, err := asPipe(binding)
+	if err != nil {
+		return nil, err
+	}
+	pipes, err := c.pipes(binding.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := pipes.Update(ctx, pipe, opts)
+	if err != nil {
+		return nil, err
+	}
+	return fromPipe(updated)
+}
+
+func (c *pipeClient) create(ctx context.Context, binding *v1alpha1.KameletBinding, opts v1.CreateOptions) (*v1alpha1.KameletBinding, error) {
+	pipe, err := asPipe(binding)
+	if err != nil {
+		return nil, err
+	}
+	pipes, err := c.pipes(binding.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	created, err := pipes.Create(ctx, pipe, opts)
+	if err != nil {
+		return nil, err
+	}
+	return fromPipe(created)
+}
+
+func (c *pipeClient) delete(ctx context.Context, namespace string, name string, opts v1.DeleteOptions) error {
+	pipes, err := c.pipes(namespace)
+	if err != nil {
+		return err
+	}
+	return pipes.Delete(ctx, name, opts)
+}
+
+func (c *pipeClient) watch(ctx context.Context, namespace string, opts v1.ListOptions) (watch.Interface, error) {
+	pipes, err := c.pipes(namespace)
+	if err != nil {
+		return nil, err
+	}
+	watcher, err := pipes.Watch(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return watch.Filter(watcher, func(event watch.Event) (watch.Event, bool) {
+		if pipe, ok := event.Object.(*unstructured.Unstructured); ok {
+			if binding, err := fromPipe(pipe); err == nil {
+				event.Object = binding
+			}
+		}
+		return event, true
+	}), nil
+}
+
+// asPipe serializes given Kamelet binding as Pipe, the Kamelet references are moved to the v1 API as well. The
+// service account of the integration is moved to the dedicated field of the Pipe spec and the trait configurations
+// are given as trait annotations.
+func asPipe(binding *v1alpha1.KameletBinding) (*unstructured.Unstructured, error) {
+	binding = binding.DeepCopy()
+	for _, endpoint := range []*v1alpha1.Endpoint{&binding.Spec.Source, &binding.Spec.Sink} {
+		if endpoint.Ref != nil && endpoint.Ref.Kind == v1alpha1.KameletKind {
+			endpoint.Ref.APIVersion = pipeGroupVersion.String()
+		}
+	}
+	// JSON is used for the conversion as the unstructured converter does not support raw endpoint properties
+	data, err := json.Marshal(binding)
+	if err != nil {
+		return nil, err
+	}
+	pipe := &unstructured.Unstructured{}
+	if err := json.Unmarshal(data, &pipe.Object); err != nil {
+		return nil, err
+	}
+	unstructured.RemoveNestedField(pipe.Object, "status")
+	if serviceAccount, ok, _ := unstructured.NestedString(pipe.Object, "spec", "integration", "serviceAccountName"); ok {
+		unstructured.RemoveNestedField(pipe.Object, "spec", "integration", "serviceAccountName")
+		if integration, _, _ := unstructured.NestedMap(pipe.Object, "spec", "integration"); len(integration) == 0 {
+			unstructured.RemoveNestedField(pipe.Object, "spec", "integration")
+		}
+		if err := unstructured.SetNestedField(pipe.Object, serviceAccount, "spec", "serviceAccountName"); err != nil {
+			return nil, err
+		}
+	}
+	if traits, ok, _ := unstructured.NestedMap(pipe.Object, "spec", "integration", "traits"); ok {
+		unstructured.RemoveNestedField(pipe.Object, "spec", "integration", "traits")
+		if integration, _, _ := unstructured.NestedMap(pipe.Object, "spec", "integration"); len(integration) == 0 {
+			unstructured.RemoveNestedField(pipe.Object, "spec", "integration")
+		}
+		annotations, err := traitAnnotations(traits)
+		if err != nil {
+			return nil, err
+		}
+		pipe.SetAnnotations(mergeMetadata(pipe.GetAnnotations(), annotations))
+	}
+	pipe.SetGroupVersionKind(pipeGroupVersion.WithKind(pipeKind))
+	return pipe, nil
+}
+
+// fromPipe converts given pipe into a Kamelet binding holding the same metadata, spec and status
+func fromPipe(pipe *unstructured.Unstructured) (*v1alpha1.KameletBinding, error) {
+	data, err := json.Marshal(pipe.Object)
+	if err != nil {
+		return nil, err
+	}
+	binding := &v1alpha1.KameletBinding{}
+	if err := json.Unmarshal(data, binding); err != nil {
+		return nil, fmt.Errorf("unable to read Pipe '%s': %w", pipe.GetName(), err)
+	}
+	if serviceAccount, _, _ := unstructured.NestedString(pipe.Object, "spec", "serviceAccountName"); serviceAccount != "" {
+		if binding.Spec.Integration == nil {
+			binding.Spec.Integration = &camelkapisv1.IntegrationSpec{}
+		}
+		binding.Spec.Integration.ServiceAccountName = serviceAccount
+	}
+	return binding, nil
+}
@@ -0,0 +1,233 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	camelkapisv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	camelkapis "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"gotest.tools/v3/assert"
+)
+
+// fakeDiscovery serves the API resources of the given group versions, all other group versions are not found
+type fakeDiscovery struct {
+	discovery.ServerResourcesInterface
+	resources map[string]*v1.APIResourceList
+	err       error
+}
+
+func (d *fakeDiscovery) ServerResourcesForGroupVersion(groupVersion string) (*v1.APIResourceList, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	if resources, ok := d.resources[groupVersion]; ok {
+		return resources, nil
+	}
+	return nil, apierrors.NewNotFound(schema.GroupResource{}, groupVersion)
+}
+
+// newPipeDiscovery returns a discovery client serving the Pipe API
+func newPipeDiscovery() *fakeDiscovery {
+	return &fakeDiscovery{resources: map[string]*v1.APIResourceList{
+		"camel.apache.org/v1": {
+			GroupVersion: "camel.apache.org/v1",
+			APIResources: []v1.APIResource{{Name: "integrations", Kind: "Integration"}, {Name: "pipes", Kind: pipeKind}},
+		},
+	}}
+}
+
+// newKameletBindingDiscovery returns a discovery client serving the Kamelet and KameletBinding API but no pipes
+func newKameletBindingDiscovery() *fakeDiscovery {
+	return &fakeDiscovery{resources: map[string]*v1.APIResourceList{
+		"camel.apache.org/v1alpha1": {
+			GroupVersion: "camel.apache.org/v1alpha1",
+			APIResources: []v1.APIResource{{Name: "kamelets", Kind: "Kamelet"}, {Name: "kameletbindings", Kind: "KameletBinding"}},
+		},
+	}}
+}
+
+// newFakePipeClient returns a fake dynamic client knowing about pipes
+func newFakePipeClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	scheme := runtime.NewScheme()
+	scheme.AddKnownTypeWithName(pipeGroupVersion.WithKind(pipeKind), &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(pipeGroupVersion.WithKind(pipeKind+"List"), &unstructured.UnstructuredList{})
+	return dynamicfake.NewSimpleDynamicClient(scheme, objects...)
+}
+
+func TestDetectBindingAPI(t *testing.T) {
+	api, err := detectBindingAPI(newPipeDiscovery())
+	assert.NilError(t, err)
+	assert.Equal(t, api, bindingAPIPipe)
+
+	// older Camel K versions serve camel.apache.org/v1 without pipes
+	api, err = detectBindingAPI(&fakeDiscovery{resources: map[string]*v1.APIResourceList{
+		"camel.apache.org/v1": {APIResources: []v1.APIResource{{Name: "integrations", Kind: "Integration"}}},
+	}})
+	assert.NilError(t, err)
+	assert.Equal(t, api, bindingAPIKameletBinding)
+
+	api, err = detectBindingAPI(&fakeDiscovery{})
+	assert.NilError(t, err)
+	assert.Equal(t, api, bindingAPIKameletBinding)
+
+	_, err = detectBindingAPI(&fakeDiscovery{err: errors.New("connection refused")})
+	assert.Error(t, err, "unable to discover the API for binding Kamelets: connection refused")
+}
+
+func TestResolveBindingAPI(t *testing.T) {
+	discoveryCalls := 0
+	p := &KameletPluginParams{
+		NewDiscoveryClient: func() (discovery.ServerResourcesInterface, error) {
+			discoveryCalls++
+			return newPipeDiscovery(), nil
+		},
+	}
+
+	api, err := p.resolveBindingAPI(bindingAPIKameletBinding, false)
+	assert.NilError(t, err)
+	assert.Equal(t, api, bindingAPIKameletBinding)
+	assert.Equal(t, discoveryCalls, 0)
+
+	api, err = p.resolveBindingAPI(bindingAPIAuto, false)
+	assert.NilError(t, err)
+	assert.Equal(t, api, bindingAPIPipe)
+	assert.Equal(t, discoveryCalls, 1)
+
+	_, err = p.resolveBindingAPI("integration", false)
+	assert.Error(t, err, "invalid API 'integration', must be one of: auto, kameletbinding, pipe")
+}
+
+func TestAsPipe(t *testing.T) {
+	binding := createKameletBindingFor("k1", "k1-binding")
+	setBindingProperties(t, binding, `{"message":"Hello"}`)
+	binding.Status.Phase = camelkapis.KameletBindingPhaseReady
+
+	pipe, err := asPipe(binding)
+	assert.NilError(t, err)
+	assert.Equal(t, pipe.GetAPIVersion(), "camel.apache.org/v1")
+	assert.Equal(t, pipe.GetKind(), pipeKind)
+	assert.Equal(t, pipe.GetName(), "k1-binding")
+	assert.Equal(t, pipe.GetNamespace(), "current")
+	_, found := pipe.Object["status"]
+	assert.Assert(t, !found)
+	refAPIVersion, _, _ := unstructured.NestedString(pipe.Object, "spec", "source", "ref", "apiVersion")
+	assert.Equal(t, refAPIVersion, "camel.apache.org/v1")
+	message, _, _ := unstructured.NestedString(pipe.Object, "spec", "source", "properties", "message")
+	assert.Equal(t, message, "Hello")
+
+	// the given binding is left untouched
+	assert.Equal(t, binding.Spec.Source.Ref.APIVersion, "camel.apache.org/v1alpha1")
+
+	converted, err := fromPipe(pipe)
+	assert.NilError(t, err)
+	assert.Equal(t, converted.Name, "k1-binding")
+	assert.Equal(t, converted.Spec.Source.Ref.Name, "k1")
+	assert.Equal(t, string(converted.Spec.Source.Properties.RawMessage), `{"message":"Hello"}`)
+}
+
+func TestAsPipeServiceAccount(t *testing.T) {
+	binding := createKameletBindingFor("k1", "k1-binding")
+	binding.Spec.Integration = &camelkapisv1.IntegrationSpec{ServiceAccountName: "timer-runner"}
+
+	pipe, err := asPipe(binding)
+	assert.NilError(t, err)
+	serviceAccount, _, _ := unstructured.NestedString(pipe.Object, "spec", "serviceAccountName")
+	assert.Equal(t, serviceAccount, "timer-runner")
+	_, found, _ := unstructured.NestedFieldNoCopy(pipe.Object, "spec", "integration")
+	assert.Assert(t, !found)
+
+	converted, err := fromPipe(pipe)
+	assert.NilError(t, err)
+	assert.Equal(t, serviceAccountName(converted), "timer-runner")
+}
+
+func TestAsPipeTraits(t *testing.T) {
+	binding := createKameletBindingFor("k1", "k1-binding")
+	binding.Annotations = map[string]string{"owner": "jane"}
+	binding.Spec.Integration = &camelkapisv1.IntegrationSpec{
+		Traits: map[string]camelkapisv1.TraitSpec{
+			"container": traitSpec(`{"limit-memory":"256Mi"}`),
+			"mount":     traitSpec(`{"configs":["configmap:a","configmap:b"]}`),
+		},
+	}
+
+	pipe, err := asPipe(binding)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, pipe.GetAnnotations(), map[string]string{
+		"owner": "jane",
+		"trait.camel.apache.org/container.limit-memory": "256Mi",
+		"trait.camel.apache.org/mount.configs":          `["configmap:a","configmap:b"]`,
+	})
+	_, found, _ := unstructured.NestedFieldNoCopy(pipe.Object, "spec", "integration")
+	assert.Assert(t, !found)
+}
+
+func TestTraitAnnotationsInvalid(t *testing.T) {
+	_, err := traitAnnotations(map[string]interface{}{"container": "256Mi"})
+	assert.Error(t, err, "invalid trait container, expected an object but got string")
+
+	_, err = traitAnnotations(map[string]interface{}{"container": map[string]interface{}{"configuration": []interface{}{"256Mi"}}})
+	assert.Error(t, err, "invalid configuration of trait container, expected an object but got []interface {}")
+
+	annotations, err := traitAnnotations(map[string]interface{}{"container": map[string]interface{}{}})
+	assert.NilError(t, err)
+	assert.Equal(t, len(annotations), 0)
+}
+
+func TestPipeClientWatch(t *testing.T) {
+	dynamicClient := newFakePipeClient()
+	client := &pipeClient{client: dynamicClient}
+
+	watcher, err := client.watch(context.TODO(), "current", v1.ListOptions{})
+	assert.NilError(t, err)
+	defer watcher.Stop()
+
+	binding := createKameletBindingFor("k1", "k1-binding")
+	binding.Status.Phase = camelkapis.KameletBindingPhaseReady
+	binding.Status.Conditions = []camelkapis.KameletBindingCondition{
+		{Type: camelkapis.KameletBindingConditionReady, Status: corev1.ConditionTrue},
+	}
+	pipe, err := runtime.DefaultUnstructuredConverter.ToUnstructured(binding)
+	assert.NilError(t, err)
+	object := &unstructured.Unstructured{Object: pipe}
+	object.SetGroupVersionKind(pipeGroupVersion.WithKind(pipeKind))
+	_, err = dynamicClient.Resource(pipeResource).Namespace("current").Create(context.TODO(), object, v1.CreateOptions{})
+	assert.NilError(t, err)
+
+	select {
+	case event := <-watcher.ResultChan():
+		watched, ok := event.Object.(*camelkapis.KameletBinding)
+		assert.Assert(t, ok, "watched pipe is not converted: %T", event.Object)
+		assert.Equal(t, watched.Name, "k1-binding")
+		assert.Assert(t, isKameletBindingReady(watched))
+	case <-time.After(time.Second):
+		t.Fatal("no watch event received")
+	}
+}
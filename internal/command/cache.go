@@ -0,0 +1,176 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/disk"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/homedir"
+)
+
+// kameletCacheTTL is the time after which the cached Kamelet list is fetched again
+const kameletCacheTTL = 5 * time.Minute
+
+// discoveryCacheTTL is the time after which the cached API discovery is done again, kubectl uses the same TTL
+const discoveryCacheTTL = 10 * time.Minute
+
+// cacheDirEnv is the environment variable kubectl reads the default cache directory from
+const cacheDirEnv = "KUBECACHEDIR"
+
+// illegalCacheFileCharacters matches the characters of a server host replaced in the name of its discovery cache
+var illegalCacheFileCharacters = regexp.MustCompile(`[^(\w/\.)]`)
+
+// newCachedDiscoveryClient returns a discovery client caching the discovered APIs on disk for the given server.
+// The cache layout matches the one of kubectl so that both share the cache when using the default directory.
+func (params *KameletPluginParams) newCachedDiscoveryClient(restConfig *rest.Config) (discovery.CachedDiscoveryInterface, error) {
+	dir := params.CacheDir
+	if dir == "" {
+		dir = defaultDiscoveryCacheDir()
+	}
+	host := strings.Replace(strings.Replace(restConfig.Host, "https://", "", 1), "http://", "", 1)
+	discoveryDir := filepath.Join(dir, "discovery", illegalCacheFileCharacters.ReplaceAllString(host, "_"))
+	return disk.NewCachedDiscoveryClientForConfig(restConfig, discoveryDir, filepath.Join(dir, "http"), discoveryCacheTTL)
+}
+
+// defaultDiscoveryCacheDir returns the cache directory given by the KUBECACHEDIR environment variable, defaulting
+// to ~/.kube/cache like kubectl
+func defaultDiscoveryCacheDir() string {
+	if dir := os.Getenv(cacheDirEnv); dir != "" {
+		return dir
+	}
+	return filepath.Join(homedir.HomeDir(), ".kube", "cache")
+}
+
+// kameletCache is the on-disk representation of the cached Kamelets of a cluster namespace
+type kameletCache struct {
+	Timestamp time.Time          `json:"timestamp"`
+	Items     []v1alpha1.Kamelet `json:"items"`
+}
+
+// cachedKamelets returns the Kamelets of given namespace from the local cache. The Kamelets are fetched from the
+// cluster and the cache is rebuilt when refresh is requested or the cache is missing, expired or unreadable.
+// Cached Kamelets hold metadata and status only, their spec is dropped to keep the cache small.
+func (params *KameletPluginParams) cachedKamelets(client KameletClient, namespace string, refresh bool) (*v1alpha1.KameletList, error) {
+	path, err := params.kameletCachePath(namespace)
+	if err == nil && !refresh {
+		if kameletList := readKameletCache(path, time.Now()); kameletList != nil {
+			return kameletList, nil
+		}
+	}
+
+	kameletList, err := listAllKamelets(params, client, namespace, v1.ListOptions{}, defaultListLimit)
+	if err != nil {
+		return nil, err
+	}
+	kameletList = trimKameletList(kameletList)
+	if path != "" {
+		// the cache is an optimization only, failing to write it must not fail the command
+		_ = writeKameletCache(path, kameletList, time.Now())
+	}
+	return kameletList, nil
+}
+
+// kameletCachePath returns the cache file of given namespace, which is keyed by the server of the current cluster
+func (params *KameletPluginParams) kameletCachePath(namespace string) (string, error) {
+	dir := params.CacheDir
+	if dir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(userCacheDir, "kn-source-kamelet")
+	}
+
+	restConfig, err := params.RestConfig()
+	if err != nil {
+		return "", err
+	}
+	key := sha256.Sum256([]byte(restConfig.Host + "\n" + namespace))
+	return filepath.Join(dir, "kamelets-"+hex.EncodeToString(key[:8])+".json"), nil
+}
+
+// readKameletCache returns the cached Kamelets or nil if the cache file is missing, expired or corrupt
+func readKameletCache(path string, now time.Time) *v1alpha1.KameletList {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	cache := kameletCache{}
+	if err := json.Unmarshal(data, &cache); err != nil || cache.Timestamp.IsZero() {
+		return nil
+	}
+	if now.Sub(cache.Timestamp) > kameletCacheTTL || now.Before(cache.Timestamp) {
+		return nil
+	}
+	return &v1alpha1.KameletList{Items: cache.Items}
+}
+
+// writeKameletCache stores given Kamelets in the cache file, the file is replaced atomically
+func writeKameletCache(path string, kameletList *v1alpha1.KameletList, now time.Time) error {
+	data, err := json.Marshal(kameletCache{Timestamp: now, Items: kameletList.Items})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// trimKameletList returns a copy of given list holding the Kamelets without their spec and managed fields
+func trimKameletList(kameletList *v1alpha1.KameletList) *v1alpha1.KameletList {
+	trimmed := &v1alpha1.KameletList{
+		TypeMeta: kameletList.TypeMeta,
+		ListMeta: kameletList.ListMeta,
+		Items:    make([]v1alpha1.Kamelet, 0, len(kameletList.Items)),
+	}
+	for i := range kameletList.Items {
+		kamelet := v1alpha1.Kamelet{
+			TypeMeta:   kameletList.Items[i].TypeMeta,
+			ObjectMeta: *kameletList.Items[i].ObjectMeta.DeepCopy(),
+			Status:     *kameletList.Items[i].Status.DeepCopy(),
+		}
+		kamelet.ManagedFields = nil
+		trimmed.Items = append(trimmed.Items, kamelet)
+	}
+	return trimmed
+}
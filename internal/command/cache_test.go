@@ -0,0 +1,174 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	camelkapis "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/homedir"
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCachedKamelets(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	p := newCacheTestParams(t)
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "message", "string", "The message to send", true)
+	recorder.List(&camelkapis.KameletList{Items: []camelkapis.Kamelet{*kamelet}}, nil)
+
+	kameletList, err := p.cachedKamelets(mockClient, "default", false)
+	assert.NilError(t, err)
+	assert.Equal(t, len(kameletList.Items), 1)
+	assert.Assert(t, kameletList.Items[0].Spec.Definition == nil)
+
+	// served from the cache, the mock fails on unexpected list calls
+	kameletList, err = p.cachedKamelets(mockClient, "default", false)
+	assert.NilError(t, err)
+	assert.Equal(t, kameletList.Items[0].Name, "k1")
+	assert.Equal(t, kameletList.Items[0].Labels[kameletTypeLabel], kameletTypeSource)
+	assert.Equal(t, kameletList.Items[0].Status.Phase, camelkapis.KameletPhaseReady)
+
+	recorder.List(&camelkapis.KameletList{Items: []camelkapis.Kamelet{*createKamelet("k1"), *createKamelet("k2")}}, nil)
+	kameletList, err = p.cachedKamelets(mockClient, "default", true)
+	assert.NilError(t, err)
+	assert.Equal(t, len(kameletList.Items), 2)
+
+	recorder.Validate()
+}
+
+func TestCachedKameletsKeyedByClusterAndNamespace(t *testing.T) {
+	p := newCacheTestParams(t)
+
+	path, err := p.kameletCachePath("default")
+	assert.NilError(t, err)
+	otherNamespace, err := p.kameletCachePath("other")
+	assert.NilError(t, err)
+	assert.Assert(t, path != otherNamespace)
+
+	other := newCacheTestParams(t)
+	other.CacheDir = p.CacheDir
+	other.KubeContext = "prod"
+	otherCluster, err := other.kameletCachePath("default")
+	assert.NilError(t, err)
+	assert.Assert(t, path != otherCluster)
+}
+
+func TestCachedKameletsCorruptCache(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	p := newCacheTestParams(t)
+
+	path, err := p.kameletCachePath("default")
+	assert.NilError(t, err)
+	assert.NilError(t, writeKameletCache(path, &camelkapis.KameletList{}, time.Now()))
+	assert.NilError(t, ioutil.WriteFile(path, []byte("{not json"), 0600))
+
+	recorder.List(&camelkapis.KameletList{Items: []camelkapis.Kamelet{*createKamelet("k1")}}, nil)
+	kameletList, err := p.cachedKamelets(mockClient, "default", false)
+	assert.NilError(t, err)
+	assert.Equal(t, len(kameletList.Items), 1)
+
+	// the rebuilt cache is used again
+	kameletList, err = p.cachedKamelets(mockClient, "default", false)
+	assert.NilError(t, err)
+	assert.Equal(t, len(kameletList.Items), 1)
+
+	recorder.Validate()
+}
+
+func TestReadKameletCacheExpired(t *testing.T) {
+	path := t.TempDir() + "/kamelets.json"
+	now := time.Now()
+	assert.NilError(t, writeKameletCache(path, &camelkapis.KameletList{Items: []camelkapis.Kamelet{*createKamelet("k1")}}, now))
+
+	assert.Assert(t, readKameletCache(path, now.Add(time.Minute)) != nil)
+	assert.Assert(t, readKameletCache(path, now.Add(kameletCacheTTL+time.Second)) == nil)
+	assert.Assert(t, readKameletCache(path, now.Add(-time.Minute)) == nil)
+	assert.Assert(t, readKameletCache(t.TempDir()+"/missing.json", now) == nil)
+}
+
+func TestCachedDiscovery(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/apis/camel.apache.org/v1" {
+			http.NotFound(w, r)
+			return
+		}
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"camel.apache.org/v1",`+
+			`"resources":[{"name":"pipes","namespaced":true,"kind":"Pipe","verbs":["get"]}]}`)
+	}))
+	defer server.Close()
+
+	p := &KameletPluginParams{CacheDir: t.TempDir()}
+	p.NewDiscoveryClient = func() (discovery.ServerResourcesInterface, error) {
+		return p.newCachedDiscoveryClient(&rest.Config{Host: server.URL})
+	}
+
+	// the API is discovered once and read from the cache by later commands
+	for i := 0; i < 2; i++ {
+		api, err := p.resolveBindingAPI(bindingAPIAuto, false)
+		assert.NilError(t, err)
+		assert.Equal(t, api, bindingAPIPipe)
+	}
+	assert.Equal(t, requests, 1)
+	// the cache of the server is named after its host like the one of kubectl
+	entries, err := ioutil.ReadDir(filepath.Join(p.CacheDir, "discovery"))
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 1)
+	assert.Equal(t, entries[0].Name(), strings.Replace(strings.TrimPrefix(server.URL, "http://"), ":", "_", 1))
+
+	api, err := p.resolveBindingAPI(bindingAPIAuto, true)
+	assert.NilError(t, err)
+	assert.Equal(t, api, bindingAPIPipe)
+	assert.Equal(t, requests, 2)
+}
+
+func TestDefaultDiscoveryCacheDir(t *testing.T) {
+	os.Setenv(cacheDirEnv, "/tmp/kube-cache")
+	defer os.Unsetenv(cacheDirEnv)
+	assert.Equal(t, defaultDiscoveryCacheDir(), "/tmp/kube-cache")
+
+	os.Unsetenv(cacheDirEnv)
+	assert.Equal(t, defaultDiscoveryCacheDir(), filepath.Join(homedir.HomeDir(), ".kube", "cache"))
+}
+
+func newCacheTestParams(t *testing.T) *KameletPluginParams {
+	return &KameletPluginParams{
+		KnParams: &commands.KnParams{KubeCfgPath: writeTestKubeConfig(t)},
+		Context:  context.TODO(),
+		CacheDir: t.TempDir(),
+	}
+}
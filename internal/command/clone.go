@@ -0,0 +1,116 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	knerrors "knative.dev/client/pkg/errors"
+	"knative.dev/client/pkg/kn/commands"
+)
+
+var cloneExample = `
+  # Copy the Kamelet timer-source from the operator namespace into the current namespace
+  kn-source-kamelet clone timer-source --from-namespace camel-k
+
+  # Copy the Kamelet timer-source within the current namespace using a new name
+  kn-source-kamelet clone timer-source --name my-timer-source`
+
+// NewCloneCommand implements 'kn-source-kamelet clone' command
+func NewCloneCommand(p *KameletPluginParams) *cobra.Command {
+	var name string
+	var fromNamespace string
+	var fieldManager string
+
+	cmd := &cobra.Command{
+		Use:     "clone NAME",
+		Short:   "Copy a Kamelet into the current namespace",
+		Example: cloneExample,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if len(args) != 1 {
+				return errors.New("'kn-source-kamelet clone' requires the Kamelet name given as single argument")
+			}
+			kameletName := args[0]
+			if err := validateFieldManager(fieldManager); err != nil {
+				return err
+			}
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			sourceNamespace := fromNamespace
+			if sourceNamespace == "" {
+				sourceNamespace = namespace
+			}
+			cloneName := name
+			if cloneName == "" {
+				cloneName = kameletName
+			}
+			if cloneName == kameletName && sourceNamespace == namespace {
+				return fmt.Errorf("'kn-source-kamelet clone' requires a new name given with --name when cloning within namespace '%s'", namespace)
+			}
+
+			client, err := p.NewKameletClient()
+			if err != nil {
+				return err
+			}
+
+			kamelet, err := p.getKamelet(client, sourceNamespace, kameletName)
+			if err != nil {
+				return knerrors.GetError(err)
+			}
+
+			clone := cloneKamelet(kamelet, namespace, cloneName)
+			if _, err := client.Create(p.Context, clone, v1.CreateOptions{FieldManager: fieldManager}); err != nil {
+				return knerrors.GetError(err)
+			}
+
+			fmt.Fprintf(p.messageWriter(cmd.OutOrStdout()), "Kamelet '%s' cloned from namespace '%s' as '%s' in namespace '%s'.\n",
+				kameletName, sourceNamespace, cloneName, namespace)
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	commands.AddNamespaceFlags(flags, false)
+	flags.StringVar(&name, "name", "", "Name of the copied Kamelet. Defaults to the name of the source Kamelet, "+
+		"required when cloning within the same namespace.")
+	flags.StringVar(&fromNamespace, "from-namespace", "", "Namespace of the source Kamelet. Defaults to the target namespace.")
+	addFieldManagerFlag(flags, &fieldManager)
+	return cmd
+}
+
+// cloneKamelet returns a copy of given Kamelet with new name and namespace ready to be created. Server managed
+// fields, the status and the last applied configuration are stripped as they do not apply to the copy.
+func cloneKamelet(kamelet *v1alpha1.Kamelet, namespace string, name string) *v1alpha1.Kamelet {
+	clone := kamelet.DeepCopy()
+	clone.ObjectMeta = v1.ObjectMeta{
+		Name:        name,
+		Namespace:   namespace,
+		Labels:      clone.Labels,
+		Annotations: clone.Annotations,
+	}
+	delete(clone.Annotations, corev1.LastAppliedConfigAnnotation)
+	clone.Status = v1alpha1.KameletStatus{}
+	return clone
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+	clienttesting "knative.dev/kn-plugin-source-kamelet/internal/client/testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCloneSetup(t *testing.T) {
+	p := KameletPluginParams{
+		Context: context.TODO(),
+	}
+
+	cloneCmd := NewCloneCommand(&p)
+	assert.Equal(t, cloneCmd.Use, "clone NAME")
+	assert.Equal(t, cloneCmd.Short, "Copy a Kamelet into the current namespace")
+	assert.Assert(t, cloneCmd.RunE != nil)
+}
+
+func TestCloneErrorCaseMissingArgument(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+
+	_, err := runCloneCmd(mockClient)
+	assert.Error(t, err, "'kn-source-kamelet clone' requires the Kamelet name given as single argument")
+
+	_, err = runCloneCmd(mockClient, "k1")
+	assert.Error(t, err, "'kn-source-kamelet clone' requires a new name given with --name when cloning within namespace 'current'")
+
+	mockClient.Recorder().Validate()
+}
+
+func TestClone(t *testing.T) {
+	kamelet := createKameletInNamespace("k1", "camel-k")
+	kamelet.ResourceVersion = "4711"
+	kamelet.UID = "4b1f36a0-0935-4c3e-9e4e-7c2e2c4a4f1a"
+	kamelet.Generation = 2
+	kamelet.Annotations = map[string]string{
+		kameletProviderAnnotation:          "Apache Software Foundation",
+		corev1.LastAppliedConfigAnnotation: "{}",
+	}
+	fakeClient := clienttesting.NewFakeKameletClient(t, kamelet)
+
+	output, err := runCloneCmd(fakeClient, "k1", "--from-namespace", "camel-k")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "Kamelet 'k1' cloned from namespace 'camel-k' as 'k1' in namespace 'current'.\n")
+
+	clone, err := fakeClient.Get(context.TODO(), "current", "k1", v1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, clone.ObjectMeta, v1.ObjectMeta{
+		Name:        "k1",
+		Namespace:   "current",
+		Labels:      map[string]string{kameletTypeLabel: kameletTypeSource},
+		Annotations: map[string]string{kameletProviderAnnotation: "Apache Software Foundation"},
+	})
+	assert.DeepEqual(t, clone.Spec, kamelet.Spec)
+
+	_, err = runCloneCmd(fakeClient, "k1", "--from-namespace", "camel-k")
+	assert.Assert(t, apierrors.IsAlreadyExists(err), err)
+
+	output, err = runCloneCmd(fakeClient, "k1", "--from-namespace", "camel-k", "--name", "my-k1")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "Kamelet 'k1' cloned from namespace 'camel-k' as 'my-k1' in namespace 'current'.\n")
+	_, err = fakeClient.Get(context.TODO(), "current", "my-k1", v1.GetOptions{})
+	assert.NilError(t, err)
+
+	// the source Kamelet is left untouched
+	source, err := fakeClient.Get(context.TODO(), "camel-k", "k1", v1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, source, kamelet)
+}
+
+func TestCloneErrorCaseNotFound(t *testing.T) {
+	fakeClient := clienttesting.NewFakeKameletClient(t)
+
+	_, err := runCloneCmd(fakeClient, "k1", "--name", "my-k1")
+	assert.Assert(t, apierrors.IsNotFound(err), err)
+}
+
+func runCloneCmd(c KameletClient, options ...string) (string, error) {
+	p := KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewKameletClient: func() (KameletClient, error) {
+			return c, nil
+		},
+	}
+
+	cloneCmd, _, output := commands.CreateSourcesTestKnCommand(NewCloneCommand(&p), p.KnParams)
+
+	args := []string{"clone"}
+	args = append(args, options...)
+	cloneCmd.SetArgs(args)
+	err := cloneCmd.Execute()
+
+	return output.String(), err
+}
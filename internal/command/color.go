@@ -0,0 +1,97 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"github.com/spf13/pflag"
+)
+
+const (
+	colorGreen = "\033[32m"
+	colorRed   = "\033[31m"
+	colorReset = "\033[0m"
+)
+
+// addNoColorFlag adds the flag disabling colored output
+func addNoColorFlag(flags *pflag.FlagSet, noColor *bool) {
+	flags.BoolVar(noColor, "no-color", false, "Disable colored output. Colors are also disabled when the NO_COLOR "+
+		"environment variable is set or the output is not a terminal.")
+}
+
+// useColor returns true if colored output should be written to given writer
+func useColor(out io.Writer, noColor bool) bool {
+	if noColor {
+		return false
+	}
+	if _, set := os.LookupEnv("NO_COLOR"); set {
+		return false
+	}
+	return isTerminal(out)
+}
+
+// colorPhase wraps given Kamelet phase in the color matching its state, Ready in green and Error in red
+func colorPhase(phase string) string {
+	switch v1alpha1.KameletPhase(phase) {
+	case v1alpha1.KameletPhaseReady:
+		return colorGreen + phase + colorReset
+	case v1alpha1.KameletPhaseError:
+		return colorRed + phase + colorReset
+	default:
+		return phase
+	}
+}
+
+// colorPhaseColumn colors the values of the PHASE column in given rendered table. Colors are applied after
+// rendering so that the escape sequences do not break the column alignment. The header line is dropped if requested.
+// The column is located by rune position as the table writer pads the cells by rune count, so that multibyte
+// values in the columns before PHASE do not shift it.
+func colorPhaseColumn(table string, dropHeader bool) string {
+	lines := strings.SplitAfter(table, "\n")
+	if len(lines) == 0 {
+		return table
+	}
+
+	start := strings.Index(lines[0], "PHASE")
+	if start < 0 {
+		return table
+	}
+	start = utf8.RuneCountInString(lines[0][:start])
+
+	result := &strings.Builder{}
+	if !dropHeader {
+		result.WriteString(lines[0])
+	}
+	for _, line := range lines[1:] {
+		runes := []rune(line)
+		if len(runes) <= start || runes[start] == ' ' {
+			result.WriteString(line)
+			continue
+		}
+		end := start
+		for end < len(runes) && runes[end] != ' ' && runes[end] != '\n' {
+			end++
+		}
+		result.WriteString(string(runes[:start]) + colorPhase(string(runes[start:end])) + string(runes[end:]))
+	}
+	return result.String()
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"text/tabwriter"
+
+	"knative.dev/client/pkg/util"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestColorPhase(t *testing.T) {
+	assert.Equal(t, colorPhase("Ready"), "\033[32mReady\033[0m")
+	assert.Equal(t, colorPhase("Error"), "\033[31mError\033[0m")
+	assert.Equal(t, colorPhase("Unknown"), "Unknown")
+	assert.Equal(t, colorPhase(""), "")
+}
+
+func TestColorPhaseColumn(t *testing.T) {
+	table := "NAME   PHASE   AGE\n" +
+		"k1     Ready   1m\n" +
+		"k2     Error   2m\n" +
+		"k3             3m\n"
+
+	assert.Equal(t, colorPhaseColumn(table, false), "NAME   PHASE   AGE\n"+
+		"k1     \033[32mReady\033[0m   1m\n"+
+		"k2     \033[31mError\033[0m   2m\n"+
+		"k3             3m\n")
+
+	assert.Equal(t, colorPhaseColumn(table, true), "k1     \033[32mReady\033[0m   1m\n"+
+		"k2     \033[31mError\033[0m   2m\n"+
+		"k3             3m\n")
+
+	assert.Equal(t, colorPhaseColumn("NAME   AGE\nk1     1m\n", false), "NAME   AGE\nk1     1m\n")
+}
+
+func TestColorPhaseColumnMultibyteTitle(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buf, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTITLE\tPHASE\tAGE")
+	fmt.Fprintln(w, "k1\tZeitgeber für Ereignisse\tReady\t1m")
+	fmt.Fprintln(w, "k2\tタイマー\tError\t2m")
+	assert.NilError(t, w.Flush())
+
+	assert.Equal(t, colorPhaseColumn(buf.String(), true),
+		"k1     Zeitgeber für Ereignisse   \033[32mReady\033[0m   1m\n"+
+			"k2     タイマー"+strings.Repeat(" ", 23)+"\033[31mError\033[0m   2m\n")
+}
+
+func TestUseColor(t *testing.T) {
+	assert.Assert(t, !useColor(&bytes.Buffer{}, false))
+	assert.Assert(t, !useColor(&bytes.Buffer{}, true))
+}
+
+func TestNoColorOutput(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1", "--no-color")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Phase:", "Ready"))
+	assert.Assert(t, util.ContainsNone(output, "\033["))
+
+	recorder.Validate()
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var completionExample = `
+  # Load the bash completion of the plugin into the current shell
+  source <(kn-source-kamelet completion bash)
+
+  # Install the zsh completion of the plugin
+  kn-source-kamelet completion zsh > "${fpath[1]}/_kn-source-kamelet"
+
+  # Load the fish completion of the plugin into the current shell
+  kn-source-kamelet completion fish | source
+
+  # Load the PowerShell completion of the plugin into the current shell
+  kn-source-kamelet completion powershell | Out-String | Invoke-Expression`
+
+// completionShells lists the shells completion scripts can be generated for, sorted as cobra sorts valid arguments
+var completionShells = []string{"bash", "fish", "powershell", "zsh"}
+
+// NewCompletionCommand implements 'kn-source-kamelet completion' command
+func NewCompletionCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:       "completion SHELL",
+		Short:     "Print the shell completion script of the plugin",
+		Long:      fmt.Sprintf("Print the completion script of the plugin for given shell. One of: %s.", strings.Join(completionShells, "|")),
+		Example:   completionExample,
+		ValidArgs: completionShells,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("'kn-source-kamelet completion' requires the shell given as single argument")
+			}
+			root := cmd.Root()
+			out := cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletion(out)
+			case "fish":
+				return root.GenFishCompletion(out, true)
+			case "powershell":
+				return root.GenPowerShellCompletionWithDesc(out)
+			case "zsh":
+				return root.GenZshCompletion(out)
+			default:
+				return fmt.Errorf("invalid shell '%s', must be one of: %s", args[0], strings.Join(completionShells, ", "))
+			}
+		},
+	}
+}
+
+// completeKameletNames returns the completion function suggesting the names of the Kamelets of the type given with
+// the type flag, or of given type if the command has no such flag. The Kamelets are read from the local cache to
+// keep completion fast, at most maxArgs Kamelet names are completed unless maxArgs is negative.
+func completeKameletNames(p *KameletPluginParams, kameletType string, maxArgs int) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if maxArgs >= 0 && len(args) >= maxArgs {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		if flag := cmd.Flags().Lookup("type"); flag != nil {
+			kameletType = flag.Value.String()
+		}
+
+		namespace, err := p.GetNamespace(cmd)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		client, err := p.NewKameletClient()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		kameletList, err := p.cachedKamelets(client, namespace, false)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		given := make(map[string]bool, len(args))
+		for _, arg := range args {
+			given[arg] = true
+		}
+		var names []string
+		for _, kamelet := range filterKamelets(kameletList, kameletOfType(kameletType)).Items {
+			if strings.HasPrefix(kamelet.Name, toComplete) && !given[kamelet.Name] {
+				names = append(names, kamelet.Name)
+			}
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+}
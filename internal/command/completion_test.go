@@ -0,0 +1,90 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"strings"
+	"testing"
+
+	camelkapis "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"github.com/spf13/cobra"
+	"knative.dev/client/pkg/util"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCompletion(t *testing.T) {
+	for _, shell := range completionShells {
+		output, err := runCompletionCmd(shell)
+		assert.NilError(t, err)
+		assert.Assert(t, util.ContainsAll(output, "kn-source-kamelet"), shell)
+	}
+
+	_, err := runCompletionCmd()
+	assert.Error(t, err, "'kn-source-kamelet completion' requires the shell given as single argument")
+
+	_, err = runCompletionCmd("tcsh")
+	assert.Error(t, err, "invalid shell 'tcsh', must be one of: bash, fish, powershell, zsh")
+}
+
+func TestCompleteKameletNames(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	sink := createKamelet("log-sink")
+	sink.Labels[kameletTypeLabel] = kameletTypeSink
+	recorder.List(&camelkapis.KameletList{Items: []camelkapis.Kamelet{
+		*createKamelet("timer-source"), *createKamelet("telegram-source"), *createKamelet("aws-s3-source"), *sink,
+	}}, nil)
+
+	p := newCacheTestParams(t)
+	p.NewKameletClient = func() (KameletClient, error) {
+		return mockClient, nil
+	}
+	p.NewKameletBindingClient = mockBindingClient(mockClient)
+	describeCmd := NewDescribeTypeCommand(p)
+
+	names, directive := describeCmd.ValidArgsFunction(describeCmd, nil, "t")
+	assert.DeepEqual(t, names, []string{"timer-source", "telegram-source"})
+	assert.Equal(t, directive, cobra.ShellCompDirectiveNoFileComp)
+
+	// the Kamelets are served from the cache and filtered by the given type
+	assert.NilError(t, describeCmd.Flags().Set("type", kameletTypeSink))
+	names, _ = describeCmd.ValidArgsFunction(describeCmd, nil, "")
+	assert.DeepEqual(t, names, []string{"log-sink"})
+
+	names, _ = describeCmd.ValidArgsFunction(describeCmd, []string{"log-sink"}, "")
+	assert.Assert(t, len(names) == 0)
+
+	// bind completes further Kamelets not given yet
+	bindCmd := NewBindCommand(p)
+	names, _ = bindCmd.ValidArgsFunction(bindCmd, []string{"timer-source"}, "")
+	assert.DeepEqual(t, names, []string{"telegram-source", "aws-s3-source"})
+
+	recorder.Validate()
+}
+
+func runCompletionCmd(args ...string) (string, error) {
+	rootCmd := &cobra.Command{Use: "kn-source-kamelet"}
+	rootCmd.AddCommand(NewCompletionCommand())
+	output := &strings.Builder{}
+	rootCmd.SetOut(output)
+	rootCmd.SetArgs(append([]string{"completion"}, args...))
+	err := rootCmd.Execute()
+	return output.String(), err
+}
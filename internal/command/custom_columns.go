@@ -0,0 +1,167 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+const (
+	customColumnsFormat     = "custom-columns"
+	customColumnsFileFormat = "custom-columns-file"
+)
+
+// customColumn holds the header and the JSONPath expression of a single custom column
+type customColumn struct {
+	header string
+	parser *jsonpath.JSONPath
+}
+
+// isCustomColumnsFormat returns true if given output format requests custom columns
+func isCustomColumnsFormat(output string) bool {
+	return strings.HasPrefix(output, customColumnsFormat+"=") || strings.HasPrefix(output, customColumnsFileFormat+"=")
+}
+
+// parseCustomColumnsFormat parses the columns given either inline with -o custom-columns=HEADER:PATH,...
+// or as a file with -o custom-columns-file=FILE holding a header line and a line of JSONPath expressions
+func parseCustomColumnsFormat(output string) ([]customColumn, error) {
+	if strings.HasPrefix(output, customColumnsFileFormat+"=") {
+		file := strings.TrimPrefix(output, customColumnsFileFormat+"=")
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read custom columns file '%s': %w", file, err)
+		}
+		return parseCustomColumnsTemplate(string(data))
+	}
+
+	spec := strings.TrimPrefix(output, customColumnsFormat+"=")
+	if spec == "" {
+		return nil, fmt.Errorf("custom-columns format specified but no custom columns given")
+	}
+
+	var headers, fieldSpecs []string
+	for _, column := range strings.Split(spec, ",") {
+		parts := strings.SplitN(column, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid custom column '%s', expected format HEADER:JSONPATH", column)
+		}
+		headers = append(headers, parts[0])
+		fieldSpecs = append(fieldSpecs, parts[1])
+	}
+	return newCustomColumns(headers, fieldSpecs)
+}
+
+// parseCustomColumnsTemplate parses a custom columns template with headers on the first and JSONPath expressions on the second line
+func parseCustomColumnsTemplate(template string) ([]customColumn, error) {
+	scanner := bufio.NewScanner(strings.NewReader(template))
+	var lines [][]string
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) > 0 {
+			lines = append(lines, fields)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) != 2 {
+		return nil, fmt.Errorf("invalid custom columns template, expected a header line and a JSONPath line but got %d lines", len(lines))
+	}
+	if len(lines[0]) != len(lines[1]) {
+		return nil, fmt.Errorf("invalid custom columns template, got %d headers but %d JSONPath expressions", len(lines[0]), len(lines[1]))
+	}
+	return newCustomColumns(lines[0], lines[1])
+}
+
+// newCustomColumns compiles given JSONPath expressions into custom columns
+func newCustomColumns(headers []string, fieldSpecs []string) ([]customColumn, error) {
+	columns := make([]customColumn, 0, len(headers))
+	for i, header := range headers {
+		parser := jsonpath.New(header).AllowMissingKeys(true)
+		if err := parser.Parse(relaxedJSONPathExpression(fieldSpecs[i])); err != nil {
+			return nil, fmt.Errorf("invalid JSONPath expression '%s' for column %s: %w", fieldSpecs[i], header, err)
+		}
+		columns = append(columns, customColumn{header: header, parser: parser})
+	}
+	return columns, nil
+}
+
+// relaxedJSONPathExpression allows JSONPath expressions without surrounding braces and leading dot,
+// e.g. 'metadata.name' is the same as '{.metadata.name}'
+func relaxedJSONPathExpression(fieldSpec string) string {
+	if strings.HasPrefix(fieldSpec, "{") && strings.HasSuffix(fieldSpec, "}") {
+		return fieldSpec
+	}
+	if !strings.HasPrefix(fieldSpec, ".") {
+		fieldSpec = "." + fieldSpec
+	}
+	return "{" + fieldSpec + "}"
+}
+
+// printCustomColumns prints one row per given object with the values of the custom columns
+func printCustomColumns(out io.Writer, columns []customColumn, objects []runtime.Object, noHeaders bool) error {
+	w := printers.GetNewTabWriter(out)
+
+	if !noHeaders {
+		headers := make([]string, 0, len(columns))
+		for _, column := range columns {
+			headers = append(headers, column.header)
+		}
+		fmt.Fprintln(w, strings.Join(headers, "\t"))
+	}
+
+	for _, obj := range objects {
+		data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return err
+		}
+
+		values := make([]string, 0, len(columns))
+		for _, column := range columns {
+			results, err := column.parser.FindResults(data)
+			if err != nil {
+				return err
+			}
+			values = append(values, customColumnValue(results))
+		}
+		fmt.Fprintln(w, strings.Join(values, "\t"))
+	}
+
+	return w.Flush()
+}
+
+// customColumnValue joins the JSONPath results of a single cell, using <none> when nothing was found
+func customColumnValue(results [][]reflect.Value) string {
+	var values []string
+	for _, result := range results {
+		for _, value := range result {
+			values = append(values, fmt.Sprintf("%v", value.Interface()))
+		}
+	}
+	if len(values) == 0 {
+		return "<none>"
+	}
+	return strings.Join(values, ",")
+}
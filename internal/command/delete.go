@@ -0,0 +1,212 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+
+	knerrors "knative.dev/client/pkg/errors"
+	"knative.dev/client/pkg/kn/commands"
+	knflags "knative.dev/client/pkg/kn/flags"
+)
+
+var deleteExample = `
+  # Delete a Kamelet binding
+  kn-source-kamelet delete timer-binding
+
+  # Delete several Kamelet bindings and wait until they are gone
+  kn-source-kamelet delete timer-binding other-binding --wait --timeout 2m
+
+  # Delete all Kamelet bindings with label team=a, asking for confirmation first
+  kn-source-kamelet delete -l team=a
+
+  # Delete all Kamelet bindings created by this plugin without asking for confirmation
+  kn-source-kamelet delete -l app.kubernetes.io/managed-by=kn-source-kamelet --force
+
+  # Check that the Kamelet binding can be deleted without actually deleting it
+  kn-source-kamelet delete timer-binding --dry-run=server`
+
+// NewDeleteCommand implements 'kn-source-kamelet delete' command
+func NewDeleteCommand(p *KameletPluginParams) *cobra.Command {
+	var wait bool
+	var timeout time.Duration
+	var dryRun string
+	var selector string
+	var force bool
+	var bindingAPI string
+
+	cmd := &cobra.Command{
+		Use:     "delete NAME...",
+		Short:   "Delete Kamelet bindings",
+		Example: deleteExample,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if len(args) == 0 && selector == "" {
+				return errors.New("'kn-source-kamelet delete' requires the KameletBinding name given as argument " +
+					"or a label selector given with --selector")
+			}
+			if len(args) > 0 && selector != "" {
+				return errors.New("'kn-source-kamelet delete' accepts either KameletBinding names or --selector, not both")
+			}
+			if _, err := labels.Parse(selector); err != nil {
+				return fmt.Errorf("invalid label selector '%s': %w", selector, err)
+			}
+
+			if err := validateDryRun(dryRun); err != nil {
+				return err
+			}
+
+			if err := knflags.ReconcileBoolFlags(cmd.Flags()); err != nil {
+				return err
+			}
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+
+			api, err := p.resolveBindingAPI(bindingAPI, false)
+			if err != nil {
+				return err
+			}
+			bindingClient := p.newBindingClient(api)
+
+			names := args
+			if selector != "" {
+				names, err = selectKameletBindings(cmd, p, bindingClient, namespace, selector, force || dryRun != dryRunNone)
+				if err != nil {
+					return err
+				}
+			}
+
+			errs := []string{}
+			for _, name := range names {
+				if err := deleteKameletBinding(p, bindingClient, namespace, name, wait, timeout, dryRun); err != nil {
+					errs = append(errs, err.Error())
+					continue
+				}
+				fmt.Fprintf(p.messageWriter(cmd.OutOrStdout()), "%s '%s' successfully deleted in namespace '%s'%s.\n", bindingClient.kind(),
+					name, namespace, dryRunSuffix(dryRun))
+			}
+			if len(errs) > 0 {
+				return errors.New(strings.Join(errs, "\n"))
+			}
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	commands.AddNamespaceFlags(flags, false)
+	knflags.AddBothBoolFlagsUnhidden(flags, &wait, "wait", "", false, "Wait until the Kamelet bindings are actually deleted.")
+	flags.DurationVar(&timeout, "timeout", 60*time.Second, "Maximum time to wait for each Kamelet binding to be deleted.")
+	flags.StringVarP(&selector, "selector", "l", "", "Delete all Kamelet bindings matching given label selector instead of "+
+		"named bindings, supports '=', '==', and '!=' (e.g. -l key1=value1,key2=value2). The matching bindings are listed and "+
+		"the deletion must be confirmed unless --force or a dry run is given.")
+	flags.BoolVar(&force, "force", false, "Delete the Kamelet bindings matching --selector without asking for confirmation.")
+	addDryRunFlag(flags, &dryRun)
+	addExistingBindingAPIFlag(flags, &bindingAPI)
+	return cmd
+}
+
+// selectKameletBindings returns the names of the Kamelet bindings matching given label selector once the deletion
+// has been confirmed. Confirmation requires a terminal, so scripts have to skip it explicitly.
+func selectKameletBindings(cmd *cobra.Command, p *KameletPluginParams, client bindingClient, namespace string, selector string,
+	confirmed bool) ([]string, error) {
+	bindingList, err := p.listKameletBindings(client, namespace, v1.ListOptions{LabelSelector: selector})
+	if isCamelKNotInstalled(err) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, knerrors.GetError(err)
+	}
+	names := make([]string, 0, len(bindingList.Items))
+	for _, binding := range bindingList.Items {
+		names = append(names, binding.Name)
+	}
+	if len(names) == 0 {
+		fmt.Fprintf(p.messageWriter(cmd.OutOrStdout()), "No %ss found matching selector '%s' in namespace '%s'.\n",
+			client.kind(), selector, namespace)
+		return nil, nil
+	}
+	if confirmed {
+		return names, nil
+	}
+
+	if !isTerminalInput(cmd.InOrStdin()) {
+		return nil, fmt.Errorf("deleting the %d %ss matching selector '%s' requires confirmation on a "+
+			"terminal, use --force to delete them without asking", len(names), client.kind(), selector)
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "%ss matching selector '%s' in namespace '%s':\n", client.kind(), selector, namespace)
+	for _, name := range names {
+		fmt.Fprintf(cmd.ErrOrStderr(), "  %s\n", name)
+	}
+	ok, err := confirm(cmd.InOrStdin(), cmd.ErrOrStderr(), fmt.Sprintf("Delete these %d %ss?", len(names), client.kind()))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("deletion not confirmed, no %ss deleted", client.kind())
+	}
+	return names, nil
+}
+
+// deleteKameletBinding deletes the Kamelet binding or pipe with given name and optionally waits for the delete event.
+// A client side dry run only checks that the binding exists, waiting is skipped on any dry run.
+func deleteKameletBinding(p *KameletPluginParams, client bindingClient, namespace string, name string,
+	wait bool, timeout time.Duration, dryRun string) error {
+	if dryRun == dryRunClient {
+		_, err := p.getKameletBinding(client, namespace, name)
+		return deleteError(err, client.kind(), namespace, name)
+	}
+	if !wait || dryRun == dryRunServer {
+		err := client.delete(p.Context, namespace, name, v1.DeleteOptions{DryRun: dryRunOptions(dryRun)})
+		return deleteError(p.checkCamelKInstalled(err), client.kind(), namespace, name)
+	}
+
+	// start watching before deleting so that the delete event can not be missed
+	watcher, err := client.watch(p.Context, namespace, v1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String(),
+	})
+	if err != nil {
+		return deleteError(p.checkCamelKInstalled(err), client.kind(), namespace, name)
+	}
+
+	if err := client.delete(p.Context, namespace, name, v1.DeleteOptions{}); err != nil {
+		watcher.Stop()
+		return deleteError(p.checkCamelKInstalled(err), client.kind(), namespace, name)
+	}
+
+	return waitUntilDeleted(p.Context, watcher, client.kind(), name, timeout)
+}
+
+// deleteError converts given delete error into a user facing error
+func deleteError(err error, kind string, namespace string, name string) error {
+	if isCamelKNotInstalled(err) {
+		return err
+	}
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("%s '%s' not found in namespace '%s'", kind, name, namespace)
+	}
+	return knerrors.GetError(err)
+}
@@ -0,0 +1,312 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	camelkapis "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/util"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestDeleteSetup(t *testing.T) {
+	p := KameletPluginParams{
+		Context: context.TODO(),
+	}
+
+	deleteCmd := NewDeleteCommand(&p)
+	assert.Equal(t, deleteCmd.Use, "delete NAME...")
+	assert.Equal(t, deleteCmd.Short, "Delete Kamelet bindings")
+	assert.Assert(t, deleteCmd.RunE != nil)
+}
+
+func TestDeleteErrorCaseMissingArgument(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+
+	_, err := runDeleteCmd(mockClient)
+	assert.Error(t, err, "'kn-source-kamelet delete' requires the KameletBinding name given as argument "+
+		"or a label selector given with --selector")
+	mockClient.BindingRecorder().Validate()
+}
+
+func TestDeleteSelector(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	bindingRecorder := mockClient.BindingRecorder()
+
+	bindings := &camelkapis.KameletBindingList{Items: []camelkapis.KameletBinding{
+		*createKameletBindingFor("k1", "k1-binding"),
+		*createKameletBindingFor("k2", "k2-binding"),
+	}}
+	bindingRecorder.ListWithOptions(v1.ListOptions{LabelSelector: "team=a"}, bindings, nil)
+	bindingRecorder.Delete("k1-binding", nil)
+	bindingRecorder.Delete("k2-binding", nil)
+
+	output, err := runDeleteCmd(mockClient, "--selector", "team=a", "--force")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "KameletBinding 'k1-binding' successfully deleted in namespace 'current'.\n"+
+		"KameletBinding 'k2-binding' successfully deleted in namespace 'current'.\n")
+
+	bindingRecorder.List(&camelkapis.KameletBindingList{}, nil)
+
+	output, err = runDeleteCmd(mockClient, "-l", "team=b")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "No KameletBindings found matching selector 'team=b' in namespace 'current'.\n")
+
+	// dry runs do not ask for confirmation
+	bindingRecorder.List(bindings, nil)
+	bindingRecorder.DeleteWithOptions("k1-binding", v1.DeleteOptions{DryRun: []string{v1.DryRunAll}}, nil)
+	bindingRecorder.DeleteWithOptions("k2-binding", v1.DeleteOptions{DryRun: []string{v1.DryRunAll}}, nil)
+
+	output, err = runDeleteCmd(mockClient, "-l", "team=a", "--dry-run=server")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "k1-binding", "k2-binding", "(server dry run)"))
+
+	bindingRecorder.Validate()
+}
+
+func TestDeleteSelectorConfirmation(t *testing.T) {
+	isTerminal := isTerminalInput
+	defer func() { isTerminalInput = isTerminal }()
+
+	mockClient := client.NewMockKameletClient(t)
+	bindingRecorder := mockClient.BindingRecorder()
+	bindings := &camelkapis.KameletBindingList{Items: []camelkapis.KameletBinding{*createKameletBindingFor("k1", "k1-binding")}}
+
+	// without a terminal the deletion can not be confirmed
+	isTerminalInput = func(in io.Reader) bool { return false }
+	bindingRecorder.List(bindings, nil)
+	_, err := runDeleteCmdWithInput(mockClient, "", "-l", "team=a")
+	assert.Error(t, err, "deleting the 1 KameletBindings matching selector 'team=a' requires confirmation on a terminal, "+
+		"use --force to delete them without asking")
+
+	isTerminalInput = func(in io.Reader) bool { return true }
+	bindingRecorder.List(bindings, nil)
+	_, err = runDeleteCmdWithInput(mockClient, "n\n", "-l", "team=a")
+	assert.Error(t, err, "deletion not confirmed, no KameletBindings deleted")
+
+	bindingRecorder.List(bindings, nil)
+	bindingRecorder.Delete("k1-binding", nil)
+	output, err := runDeleteCmdWithInput(mockClient, "yes\n", "-l", "team=a")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "KameletBinding 'k1-binding' successfully deleted in namespace 'current'.\n")
+
+	bindingRecorder.Validate()
+}
+
+func TestDeleteErrorCaseSelector(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+
+	_, err := runDeleteCmd(mockClient, "k1-binding", "-l", "team=a")
+	assert.Error(t, err, "'kn-source-kamelet delete' accepts either KameletBinding names or --selector, not both")
+
+	_, err = runDeleteCmd(mockClient, "-l", "team in (a")
+	assert.ErrorContains(t, err, "invalid label selector 'team in (a'")
+
+	mockClient.BindingRecorder().Validate()
+}
+
+func TestConfirm(t *testing.T) {
+	for input, expected := range map[string]bool{"y\n": true, "Yes\n": true, "yes": true, "\n": false, "no\n": false, "yep\n": false} {
+		out := &bytes.Buffer{}
+		ok, err := confirm(strings.NewReader(input), out, "Delete?")
+		assert.NilError(t, err)
+		assert.Equal(t, ok, expected, input)
+		assert.Equal(t, out.String(), "Delete? [y/N]: ")
+	}
+	_, err := confirm(strings.NewReader(""), &bytes.Buffer{}, "Delete?")
+	assert.ErrorContains(t, err, "unable to read confirmation")
+}
+
+func TestDelete(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	bindingRecorder := mockClient.BindingRecorder()
+
+	bindingRecorder.Delete("k1-binding", nil)
+
+	output, err := runDeleteCmd(mockClient, "k1-binding")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "KameletBinding 'k1-binding' successfully deleted in namespace 'current'.\n")
+
+	bindingRecorder.Validate()
+}
+
+func TestDeleteMultiple(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	bindingRecorder := mockClient.BindingRecorder()
+
+	bindingRecorder.Delete("k1-binding", nil)
+	bindingRecorder.Delete("k2-binding", newBindingNotFoundError("k2-binding"))
+	bindingRecorder.Delete("k3-binding", errors.New("forbidden"))
+	bindingRecorder.Delete("k4-binding", nil)
+
+	output, err := runDeleteCmd(mockClient, "k1-binding", "k2-binding", "k3-binding", "k4-binding")
+	assert.Error(t, err, "KameletBinding 'k2-binding' not found in namespace 'current'\nforbidden")
+	assert.Assert(t, util.ContainsAll(output, "'k1-binding' successfully deleted", "'k4-binding' successfully deleted"))
+	assert.Assert(t, util.ContainsNone(output, "k2-binding", "k3-binding"))
+
+	bindingRecorder.Validate()
+}
+
+func TestDeleteWait(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	bindingRecorder := mockClient.BindingRecorder()
+
+	watcher := watch.NewFakeWithChanSize(2, false)
+	watcher.Modify(createKameletBindingFor("k1", "k1-binding"))
+	watcher.Delete(createKameletBindingFor("k1", "k1-binding"))
+	bindingRecorder.Watch(watcher, nil)
+	bindingRecorder.Delete("k1-binding", nil)
+
+	output, err := runDeleteCmd(mockClient, "k1-binding", "--wait")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "'k1-binding' successfully deleted"))
+
+	bindingRecorder.Validate()
+}
+
+func TestDeleteWaitTimeout(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	bindingRecorder := mockClient.BindingRecorder()
+
+	bindingRecorder.Watch(watch.NewFake(), nil)
+	bindingRecorder.Delete("k1-binding", nil)
+
+	_, err := runDeleteCmd(mockClient, "k1-binding", "--wait", "--timeout", "10ms")
+	assert.Error(t, err, "timeout after 10ms waiting for KameletBinding k1-binding to be deleted")
+
+	bindingRecorder.Validate()
+}
+
+func TestDeleteNoWait(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	bindingRecorder := mockClient.BindingRecorder()
+
+	bindingRecorder.Delete("k1-binding", nil)
+
+	_, err := runDeleteCmd(mockClient, "k1-binding", "--no-wait")
+	assert.NilError(t, err)
+
+	bindingRecorder.Validate()
+}
+
+func TestDeleteDryRun(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	bindingRecorder := mockClient.BindingRecorder()
+
+	bindingRecorder.Get("k1-binding", createKameletBindingFor("k1", "k1-binding"), nil)
+	bindingRecorder.Get("k2-binding", nil, newBindingNotFoundError("k2-binding"))
+	bindingRecorder.DeleteWithOptions("k1-binding", v1.DeleteOptions{DryRun: []string{v1.DryRunAll}}, nil)
+
+	output, err := runDeleteCmd(mockClient, "k1-binding", "k2-binding", "--dry-run=client")
+	assert.Error(t, err, "KameletBinding 'k2-binding' not found in namespace 'current'")
+	assert.Assert(t, util.ContainsAll(output, "KameletBinding 'k1-binding' successfully deleted in namespace 'current' (dry run)."))
+
+	// waiting is skipped as nothing gets deleted
+	output, err = runDeleteCmd(mockClient, "k1-binding", "--dry-run=server", "--wait")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "KameletBinding 'k1-binding' successfully deleted in namespace 'current' (server dry run).\n")
+
+	bindingRecorder.Validate()
+}
+
+func runDeleteCmd(c *client.MockKameletClient, options ...string) (string, error) {
+	return runDeleteCmdWithInput(c, "", options...)
+}
+
+func runDeleteCmdWithInput(c *client.MockKameletClient, input string, options ...string) (string, error) {
+	p := &KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+	}
+	return runDeleteCmdWithParams(p, c, input, options...)
+}
+
+func runDeleteCmdWithParams(p *KameletPluginParams, c *client.MockKameletClient, input string, options ...string) (string, error) {
+	p.NewKameletClient = func() (KameletClient, error) {
+		return c, nil
+	}
+	p.NewKameletBindingClient = mockBindingClient(c)
+	if p.NewDiscoveryClient == nil {
+		p.NewDiscoveryClient = func() (discovery.ServerResourcesInterface, error) {
+			return newKameletBindingDiscovery(), nil
+		}
+	}
+
+	deleteCmd, _, output := commands.CreateSourcesTestKnCommand(NewDeleteCommand(p), p.KnParams)
+
+	args := []string{"delete"}
+	args = append(args, options...)
+	deleteCmd.SetArgs(args)
+	deleteCmd.SetIn(strings.NewReader(input))
+	err := deleteCmd.Execute()
+
+	return output.String(), err
+}
+
+func TestDeletePipe(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+
+	objects := []runtime.Object{}
+	for _, name := range []string{"a1", "a2", "b1"} {
+		binding := createKameletBindingFor("k1", name)
+		binding.Labels["team"] = name[:1]
+		pipe, err := asPipe(binding)
+		assert.NilError(t, err)
+		objects = append(objects, pipe)
+	}
+	pipeClient := newFakePipeClient(objects...)
+	p := &KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewPipeClient: func() (dynamic.Interface, error) {
+			return pipeClient, nil
+		},
+		NewDiscoveryClient: func() (discovery.ServerResourcesInterface, error) {
+			return newPipeDiscovery(), nil
+		},
+	}
+
+	output, err := runDeleteCmdWithParams(p, mockClient, "", "b1")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "Pipe 'b1' successfully deleted in namespace 'current'.\n")
+
+	// the selector lists the pipes as well
+	output, err = runDeleteCmdWithParams(p, mockClient, "", "-l", "team=a", "--force")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Pipe 'a1' successfully deleted", "Pipe 'a2' successfully deleted"))
+
+	pipes, err := pipeClient.Resource(pipeResource).Namespace("current").List(context.TODO(), v1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(pipes.Items), 0)
+
+	_, err = runDeleteCmdWithParams(p, mockClient, "", "b1")
+	assert.Error(t, err, "Pipe 'b1' not found in namespace 'current'")
+}
@@ -17,15 +17,26 @@
 package command
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"knative.dev/client/pkg/printers"
 	"knative.dev/pkg/apis"
+	"sigs.k8s.io/yaml"
 
 	knerrors "knative.dev/client/pkg/errors"
 	"knative.dev/client/pkg/kn/commands"
@@ -33,104 +44,1045 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// jsonSchemaDraft07 is the meta schema of the JSON Schema documents printed with --schema
+const jsonSchemaDraft07 = "http://json-schema.org/draft-07/schema#"
+
+// jsonPropertiesFormat is the output format printing the flattened Kamelet properties as JSON
+const jsonPropertiesFormat = "json-properties"
+
+// validateDescribeOutputFormat checks the given output format before the Kamelet is fetched, the custom url,
+// json-properties and wide-json formats are accepted in addition to the formats of the print flags
+func validateDescribeOutputFormat(printFlags *genericclioptions.PrintFlags) error {
+	if !printFlags.OutputFlagSpecified() {
+		return nil
+	}
+	switch strings.ToLower(*printFlags.OutputFormat) {
+	case "url", jsonPropertiesFormat, wideJSONFormat:
+		return nil
+	}
+	if _, err := printFlags.ToPrinter(); err != nil {
+		if genericclioptions.IsNoCompatiblePrinterError(err) {
+			return fmt.Errorf("unable to match a printer suitable for the output format \"%s\", allowed formats are: %s",
+				*printFlags.OutputFormat, strings.Join(append(printFlags.AllowedFormats(), "url", jsonPropertiesFormat, wideJSONFormat), ","))
+		}
+		return err
+	}
+	return nil
+}
+
+const (
+	propertySortByName     = "name"
+	propertySortByRequired = "required"
+)
+
+// maxDefaultWidth is the maximum width of default values in the verbose properties table
+const maxDefaultWidth = 24
+
+// propertyTypeWidth is the width of the TYPE column in the verbose properties table
+const propertyTypeWidth = 8
+
+// propertyTableIndent is the indentation of the verbose properties table, two spaces for the Properties section and
+// two more for the Required and Optional Properties groups
+const propertyTableIndent = 4
+
+// propertyTablePadding is the width taken on each line of the verbose properties table besides the name, default and
+// description columns, that is the indentation, the TYPE column and the spaces separating the four columns
+const propertyTablePadding = propertyTableIndent + propertyTypeWidth + 3
+
+// minDescriptionWidth is the width property descriptions are truncated at when the other columns fill the line
+const minDescriptionWidth = 20
+
+// propertySortByValues lists all supported sort orders for Kamelet properties
+var propertySortByValues = []string{propertySortByName, propertySortByRequired}
+
 var describeExample = `
   # Describe given Kamelets
   kn-source-kamelet describe-type NAME
 
+  # Describe several Kamelets at once
+  kn-source-kamelet describe-type NAME1 NAME2
+
+  # Describe the Kamelet defined in a local manifest without connecting to the cluster
+  kn-source-kamelet describe-type -f my-source.kamelet.yaml
+
+  # Describe all Kamelets defined in the manifests of a local directory and its subdirectories
+  kn-source-kamelet describe-type -f kamelets/ -R
+
   # Describe given Kamelets in YAML output format
-  kn-source-kamelet describe-type NAME -o yaml`
+  kn-source-kamelet describe-type NAME -o yaml
+
+  # Write given Kamelet in YAML output format to given file
+  kn-source-kamelet describe-type NAME -o yaml --output-file kamelets/NAME.yaml
+
+  # Print the phase of given Kamelet
+  kn-source-kamelet describe-type NAME -o jsonpath='{.status.phase}'
+
+  # Print the default value of the period property of given Kamelet
+  kn-source-kamelet describe-type NAME -o jsonpath='{.spec.definition.properties.period.default}'
+
+  # Describe given Kamelet including its route template
+  kn-source-kamelet describe-type NAME --show-source
+
+  # Describe given Kamelet in YAML output format converted to API version camel.apache.org/v1alpha1
+  kn-source-kamelet describe-type NAME -o yaml --output-version camel.apache.org/v1alpha1
+
+  # Print name and phase of given Kamelet using a Go template
+  kn-source-kamelet describe-type NAME -o go-template='{{.metadata.name}}: {{.status.phase}}'
+
+  # Print the properties of given Kamelet as flat JSON array
+  kn-source-kamelet describe-type NAME -o json-properties
+
+  # Print a short summary of given Kamelet using the built-in summary template
+  kn-source-kamelet describe-type NAME -o template=summary
+
+  # Print given Kamelet as JSON along with computed fields like its provider, the number of required properties and
+  # whether it is deprecated, -o json prints the Kamelet unmodified
+  kn-source-kamelet describe-type NAME -o wide-json
+
+  # Show all details of a single property of given Kamelet
+  kn-source-kamelet describe-type NAME --property period
+
+  # Print the properties of given Kamelet as JSON Schema document
+  kn-source-kamelet describe-type NAME --schema
+
+  # Describe given sink Kamelet
+  kn-source-kamelet describe-type NAME --type sink
+
+  # Describe given Kamelet and watch its conditions until it becomes ready
+  kn-source-kamelet describe-type NAME --watch --timeout 5m
+
+  # Watch only the conditions of given Kamelet until it becomes ready
+  kn-source-kamelet describe-type NAME --conditions-only --watch
+
+  # Describe given Kamelet with its markdown description rendered for the terminal
+  kn-source-kamelet describe-type NAME --markdown
+
+  # Print an example bind command for given Kamelet holding its required properties
+  kn-source-kamelet describe-type NAME --example
+
+  # Print given Kamelet exactly as returned by the API server, e.g. for a bug report
+  kn-source-kamelet describe-type NAME --raw
+
+  # Describe given Kamelet and flag the required properties that must be supplied at bind time
+  kn-source-kamelet describe-type NAME --check -v`
 
 // NewDescribeTypeCommand implements 'kn-source-kamelet describe-type' command
 func NewDescribeTypeCommand(p *KameletPluginParams) *cobra.Command {
 	printFlags := genericclioptions.NewPrintFlags("")
+	var kameletType string
+	var sortBy string
+	var watchReady bool
+	var timeout time.Duration
+	var waitWindow time.Duration
+	var example bool
+	var noColor bool
+	var markdown bool
+	var filename string
+	var conditionsOnly bool
+	var schema bool
+	var propertyName string
+	var outputVersion string
+	var export bool
+	var showSource bool
+	var check bool
+	var outputFile string
+	var recursive bool
+	var raw bool
 
 	cmd := &cobra.Command{
 		Use:     "describe-type",
 		Short:   "Show details of given Kamelet source type",
 		Aliases: []string{"dt"},
 		Example: describeExample,
+		// the type given with --type is completed, several Kamelets can be described at once
+		ValidArgsFunction: completeKameletNames(p, kameletTypeSource, -1),
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
-			if len(args) != 1 {
-				return errors.New("'kn-source-kamelet describe-type' requires the Kamelet name given as single argument")
+			if filename != "" && len(args) != 0 {
+				return errors.New("'kn-source-kamelet describe-type' accepts either the Kamelet name or --filename, not both")
+			}
+			if filename == "" && len(args) == 0 {
+				return errors.New("'kn-source-kamelet describe-type' requires at least one Kamelet name given as argument")
+			}
+			multiple := len(args) > 1
+			if multiple && (watchReady || schema || propertyName != "" ||
+				strings.ToLower(*printFlags.OutputFormat) == jsonPropertiesFormat) {
+				return errors.New("--watch, --schema, --property and --output json-properties require a single Kamelet name")
 			}
-			name := args[0]
 
-			namespace, err := p.GetNamespace(cmd)
-			if err != nil {
+			if err := validateKameletType(kameletType); err != nil {
+				return err
+			}
+			if err := validatePropertySortBy(sortBy); err != nil {
+				return err
+			}
+			if watchReady && printFlags.OutputFlagSpecified() {
+				return errors.New("--watch can not be combined with --output")
+			}
+			if example && (watchReady || printFlags.OutputFlagSpecified()) {
+				return errors.New("--example can not be combined with --watch or --output")
+			}
+			if schema && (example || watchReady || printFlags.OutputFlagSpecified()) {
+				return errors.New("--schema can not be combined with --example, --watch or --output")
+			}
+			if propertyName != "" && (example || schema || watchReady || printFlags.OutputFlagSpecified()) {
+				return errors.New("--property can not be combined with --example, --schema, --watch or --output")
+			}
+			if showSource && (example || schema || propertyName != "" || printFlags.OutputFlagSpecified()) {
+				return errors.New("--show-source can not be combined with --example, --schema, --property or --output")
+			}
+			if err := validateOutputVersion(outputVersion, *printFlags.OutputFormat); err != nil {
 				return err
 			}
+			if err := validateExport(export, *printFlags.OutputFormat); err != nil {
+				return err
+			}
+			if err := validateDescribeOutputFormat(printFlags); err != nil {
+				return err
+			}
+			// named templates are rendered by the plugin, inline templates and template files by the print flags
+			templateName, namedTemplate := namedTemplateArgument(*printFlags.OutputFormat)
+			var templateText string
+			if namedTemplate {
+				if templateText, err = p.loadNamedTemplate(templateName); err != nil {
+					return err
+				}
+			}
+			if conditionsOnly && (example || schema || propertyName != "" || showSource || printFlags.OutputFlagSpecified()) {
+				return errors.New("--conditions-only can not be combined with --example, --schema, --property, --show-source or --output")
+			}
+			if check && (example || schema || propertyName != "" || conditionsOnly || printFlags.OutputFlagSpecified()) {
+				return errors.New("--check can not be combined with --example, --schema, --property, --conditions-only or --output")
+			}
+			if waitWindow != 0 && !watchReady {
+				return errors.New("--wait-window requires --watch")
+			}
+			if err := validateWaitWindow(waitWindow, timeout); err != nil {
+				return err
+			}
+			if outputFile != "" && watchReady {
+				return errors.New("--output-file can not be combined with --watch")
+			}
+			if filename != "" && watchReady {
+				return errors.New("--filename can not be combined with --watch")
+			}
+			if recursive && filename == "" {
+				return errors.New("--recursive requires a directory given with --filename")
+			}
+			if raw && (multiple || filename != "") {
+				return errors.New("--raw requires a single Kamelet name, Kamelets read with --filename are no server objects")
+			}
+			if raw && (example || schema || propertyName != "" || showSource || conditionsOnly || check || markdown ||
+				watchReady || printFlags.OutputFlagSpecified()) {
+				return errors.New("--raw can not be combined with --output, --watch, --example, --schema, --property, " +
+					"--show-source, --conditions-only, --check or --markdown")
+			}
+
+			var kamelets []*v1alpha1.Kamelet
+			var client KameletClient
+			var namespace string
+			// with several names a failing Kamelet is reported and the remaining ones are described anyway
+			var failed []string
+			var failure error
+			if filename != "" {
+				// Kamelets read from files are described without connecting to the cluster
+				kamelets, err = readKameletFiles(cmd.InOrStdin(), cmd.ErrOrStderr(), filename, recursive)
+				if err != nil {
+					return err
+				}
+				for _, kamelet := range kamelets {
+					if err := verifyKameletType(kamelet, kameletType); err != nil {
+						return err
+					}
+				}
+				multiple = len(kamelets) > 1
+				if multiple && (schema || propertyName != "" ||
+					strings.ToLower(*printFlags.OutputFormat) == jsonPropertiesFormat) {
+					return fmt.Errorf("--schema, --property and --output json-properties require a single Kamelet, "+
+						"but '%s' holds %d Kamelets", filename, len(kamelets))
+				}
+			} else {
+				namespace, err = p.GetNamespace(cmd)
+				if err != nil {
+					return err
+				}
+
+				client, err = p.NewKameletClient()
+				if err != nil {
+					return err
+				}
+
+				if raw {
+					return writeRawKamelet(p, client, namespace, args[0], cmd.OutOrStdout(), outputFile)
+				}
 
-			client, err := p.NewKameletClient()
+				for _, name := range args {
+					kamelet, err := p.getKamelet(client, namespace, name)
+					if err == nil {
+						err = verifyKameletType(kamelet, kameletType)
+					}
+					if err != nil {
+						err = knerrors.GetError(err)
+						if !multiple {
+							return err
+						}
+						fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
+						if failure == nil {
+							failure = err
+						}
+						failed = append(failed, name)
+						continue
+					}
+					kamelets = append(kamelets, kamelet)
+				}
+			}
+			out, finish, err := openOutput(cmd.OutOrStdout(), outputFile)
 			if err != nil {
 				return err
 			}
+			defer func() { err = finish(err) }()
+			// the output file is discarded as well if any Kamelet failed
+			defer func() {
+				if err == nil && len(failed) > 0 {
+					err = fmt.Errorf("unable to describe %d of %d Kamelets (%s): %w", len(failed), len(args),
+						strings.Join(failed, ", "), failure)
+				}
+			}()
 
-			kamelet, err := client.Kamelets(namespace).Get(p.Context, name, v1.GetOptions{})
-			if err != nil {
-				return knerrors.GetError(err)
+			for _, kamelet := range kamelets {
+				if isKameletDeprecated(kamelet) {
+					fmt.Fprintln(cmd.ErrOrStderr(), deprecationWarning(kamelet))
+				}
+			}
+
+			if example {
+				for _, kamelet := range kamelets {
+					fmt.Fprintln(out, bindCommandExample(kamelet))
+				}
+				return nil
 			}
 
-			out := cmd.OutOrStdout()
+			if schema {
+				return writeKameletJSONSchema(out, kamelets[0])
+			}
 
-			if !isEventSourceType(kamelet) {
-				return fmt.Errorf("Kamelet %s is not an event source", name)
+			if propertyName != "" {
+				dw := printers.NewPrefixWriter(out)
+				if err := writeKameletProperty(dw, kamelets[0], propertyName); err != nil {
+					return err
+				}
+				return dw.Flush()
 			}
 
 			if printFlags.OutputFlagSpecified() {
-				if strings.ToLower(*printFlags.OutputFormat) == "url" {
-					fmt.Fprintf(out, "%s\n", kamelet.GetSelfLink())
+				switch strings.ToLower(*printFlags.OutputFormat) {
+				case "url":
+					for _, kamelet := range kamelets {
+						fmt.Fprintf(out, "%s\n", kameletURL(kamelet))
+					}
 					return nil
+				case jsonPropertiesFormat:
+					return writeKameletPropertiesJSON(out, kamelets[0], sortBy)
+				case wideJSONFormat:
+					return writeKameletsWideJSON(out, kamelets, multiple)
+				}
+				if namedTemplate {
+					return writeKameletsWithTemplate(out, templateName, templateText, kamelets)
 				}
 				printer, err := printFlags.ToPrinter()
 				if err != nil {
 					return err
 				}
-				return printer.PrintObj(kamelet, out)
+				// several Kamelets are printed as list
+				if multiple {
+					kameletList := &v1alpha1.KameletList{}
+					for _, kamelet := range kamelets {
+						kameletList.Items = append(kameletList.Items, *kamelet)
+					}
+					updateKameletListGVK(kameletList)
+					// the name printer does not support lists
+					if strings.ToLower(*printFlags.OutputFormat) == "name" {
+						for i := range kameletList.Items {
+							if err := printer.PrintObj(&kameletList.Items[i], out); err != nil {
+								return err
+							}
+						}
+						return nil
+					}
+					var printed runtime.Object = kameletList
+					if outputVersion != "" {
+						if printed, err = convertKameletListToOutputVersion(kameletList, outputVersion); err != nil {
+							return err
+						}
+					}
+					if export {
+						if printed, err = exportObject(printed); err != nil {
+							return err
+						}
+					}
+					return printer.PrintObj(printed, out)
+				}
+				var printed runtime.Object = kamelets[0]
+				if outputVersion != "" {
+					if printed, err = convertToOutputVersion(kamelets[0], outputVersion); err != nil {
+						return err
+					}
+				}
+				if export {
+					if printed, err = exportObject(printed); err != nil {
+						return err
+					}
+				}
+				return printer.PrintObj(printed, out)
 			}
 
-			dw := printers.NewPrefixWriter(out)
-
 			printDetails, err := cmd.Flags().GetBool("verbose")
 			if err != nil {
 				return err
 			}
 
-			writeKamelet(dw, kamelet, printDetails)
-			dw.WriteLine()
-			if err := dw.Flush(); err != nil {
-				return err
+			var lines int
+			for i, kamelet := range kamelets {
+				// the Kamelets are separated by an empty line, each starts with its name
+				if i > 0 {
+					fmt.Fprintln(out)
+				}
+				if !conditionsOnly {
+					dw := printers.NewPrefixWriter(out)
+					writeKamelet(dw, kamelet, printDetails, useColor(out, noColor), markdown)
+					writeKameletProperties(dw, kamelet, printDetails, sortBy, check)
+					if check {
+						writeKameletCheck(dw, kamelet)
+					}
+					writeKameletSecrets(dw, kamelet)
+					if showSource {
+						if err := writeKameletSource(dw, kamelet); err != nil {
+							return err
+						}
+					}
+					dw.WriteLine()
+					if err := dw.Flush(); err != nil {
+						return err
+					}
+				}
+
+				// Condition info
+				lines, err = writeKameletConditions(out, kamelet, printDetails)
+				if err != nil {
+					return err
+				}
 			}
 
-			// Condition info
-			commands.WriteConditions(dw, asApiConditions(kamelet.Status.Conditions), printDetails)
-			if err := dw.Flush(); err != nil {
-				return err
+			// watching is limited to a single Kamelet
+			if len(kamelets) == 0 {
+				return nil
+			}
+			kamelet := kamelets[0]
+			name := kamelet.Name
+			readyAtStart := isKameletReady(kamelet)
+			if !watchReady || (readyAtStart && waitWindow <= 0) {
+				return nil
 			}
 
-			return nil
+			watcher, err := client.Watch(p.Context, namespace, v1.ListOptions{
+				FieldSelector:   fields.OneTermEqualSelector("metadata.name", name).String(),
+				ResourceVersion: kamelet.ResourceVersion,
+			})
+			if err != nil {
+				return knerrors.GetError(err)
+			}
+
+			redraw := isTerminal(out)
+			return waitUntilReadyWithProgress(p.Context, watcher, v1alpha1.KameletKind, name, timeout, 0, kameletReadiness, func(obj runtime.Object) error {
+				kamelet, ok := obj.(*v1alpha1.Kamelet)
+				if !ok {
+					return fmt.Errorf("unexpected object type %T", obj)
+				}
+				if redraw {
+					clearLines(out, lines)
+				}
+				lines, err = writeKameletConditions(out, kamelet, printDetails)
+				return err
+			}, nil, readinessWindow{duration: waitWindow, readyAtStart: readyAtStart})
 		},
 	}
 	flags := cmd.Flags()
 	commands.AddNamespaceFlags(flags, false)
 	flags.BoolP("verbose", "v", false, "More output.")
+	flags.StringVar(&kameletType, "type", kameletTypeSource, fmt.Sprintf("Expected type of the Kamelet. One of: %s.", strings.Join(kameletTypes, "|")))
+	flags.BoolVarP(&watchReady, "watch", "w", false, "Watch the Kamelet conditions until the Kamelet becomes ready.")
+	flags.DurationVar(&timeout, "timeout", 60*time.Second, "Maximum time to watch for the Kamelet to become ready.")
+	flags.DurationVar(&waitWindow, "wait-window", 0, "Time the Kamelet must stay ready before --watch succeeds, e.g. 10s, "+
+		"so that a Ready condition flapping during startup is not taken for success. The window restarts whenever the "+
+		"Kamelet is seen not ready. By default the watch ends as soon as the Kamelet is ready.")
+	addNoColorFlag(flags, &noColor)
+	flags.BoolVar(&markdown, "markdown", false, "Render the markdown of the Kamelet description for the terminal, "+
+		"e.g. bullet points and code spans. The description is printed raw by default and with --output.")
+	flags.BoolVar(&example, "example", false, "Print an example bind command for the Kamelet instead of its details. "+
+		"Required properties are given as placeholders, defaults are filled in where available.")
+	flags.StringVar(&propertyName, "property", "", "Print all details of the Kamelet property with given name instead of the Kamelet details.")
+	flags.BoolVar(&schema, "schema", false, "Print the properties of the Kamelet as standalone JSON Schema (draft-07) document.")
+	flags.StringVar(&sortBy, "sort-by", propertySortByName, fmt.Sprintf("Sort order of the Kamelet properties. One of: %s. "+
+		"Verbose output always groups the properties into required and optional ones sorted by name.", strings.Join(propertySortByValues, "|")))
+	flags.BoolVar(&conditionsOnly, "conditions-only", false, "Print only the conditions of the Kamelet, e.g. to poll "+
+		"its readiness combined with --watch.")
+	flags.StringVarP(&filename, "filename", "f", "", "Describe the Kamelet defined in given local YAML or JSON manifest "+
+		"instead of a Kamelet of the cluster. Use '-' to read from stdin. Files holding several YAML documents "+
+		"describe each Kamelet.")
+	flags.BoolVarP(&recursive, "recursive", "R", false, "Describe the Kamelets of all YAML and JSON files in the "+
+		"directory given with --filename and its subdirectories. Files that are no Kamelets are skipped with a warning.")
 	printFlags.AddFlags(cmd)
-	cmd.Flag("output").Usage = fmt.Sprintf("Output format. One of: %s.", strings.Join(append(printFlags.AllowedFormats(), "url"), "|"))
+	addOutputVersionFlag(flags, &outputVersion)
+	addExportFlag(flags, &export)
+	flags.BoolVar(&showSource, "show-source", false, "Print the route template of the Kamelet as YAML in an additional "+
+		"Source section. Route templates can be large, so they are not shown by default.")
+	flags.BoolVar(&check, "check", false, "Check whether all required properties of the Kamelet have defaults. Required "+
+		"properties without default are flagged as to be supplied at bind time, in verbose output as extra NOTE column.")
+	flags.BoolVar(&raw, "raw", false, "Print the Kamelet exactly as returned by the API server including its managed "+
+		"fields and status, e.g. for bug reports. Unlike -o json the JSON is not decoded and printed again, the type "+
+		"of the Kamelet is not checked.")
+	addOutputFileFlag(flags, &outputFile)
+	cmd.Flag("output").Usage = fmt.Sprintf("Output format. One of: %s.", strings.Join(append(printFlags.AllowedFormats(), "url", jsonPropertiesFormat, wideJSONFormat), "|")) +
+		goTemplateUsage + namedTemplateUsage + jsonPathUsage
 	return cmd
 }
 
-func writeKamelet(dw printers.PrefixWriter, kamelet *v1alpha1.Kamelet, printDetails bool) {
-	commands.WriteMetadata(dw, &kamelet.ObjectMeta, printDetails)
-	if kamelet.Spec.Definition.Title != "" {
-		dw.WriteAttribute("Description", fmt.Sprintf("%s - %s", kamelet.Spec.Definition.Title, kamelet.Spec.Definition.Description))
+// writeRawKamelet prints the Kamelet with given name as JSON document untouched by the plugin
+func writeRawKamelet(p *KameletPluginParams, client KameletClient, namespace string,
+	name string, stdout io.Writer, outputFile string) (err error) {
+	data, err := p.getKameletRaw(client, namespace, name)
+	if err != nil {
+		return knerrors.GetError(err)
+	}
+	out, finish, err := openOutput(stdout, outputFile)
+	if err != nil {
+		return err
+	}
+	defer func() { err = finish(err) }()
+	_, err = out.Write(data)
+	return err
+}
+
+func writeKamelet(dw printers.PrefixWriter, kamelet *v1alpha1.Kamelet, printDetails bool, colored bool, markdown bool) {
+	if printDetails {
+		// presentation annotations get their own section in verbose mode
+		commands.WriteMetadata(dw, withoutPresentationAnnotations(&kamelet.ObjectMeta), printDetails)
+	} else {
+		commands.WriteMetadata(dw, &kamelet.ObjectMeta, printDetails)
+	}
+	definition := kameletDefinition(kamelet)
+	if markdown {
+		writeKameletMarkdownDescription(dw, kamelet)
+	} else if definition.Title != "" {
+		dw.WriteAttribute("Description", fmt.Sprintf("%s - %s", definition.Title, definition.Description))
+	} else {
+		dw.WriteAttribute("Description", definition.Description)
+	}
+
+	if kameletType := kamelet.Labels[kameletTypeLabel]; kameletType != "" {
+		dw.WriteAttribute("Type", kameletType)
+	}
+
+	if provider := extractKameletProvider(kamelet); provider != "" {
+		dw.WriteAttribute("Provider", provider)
+	}
+
+	if colored {
+		dw.WriteAttribute("Phase", colorPhase(string(kamelet.Status.Phase)))
 	} else {
-		dw.WriteAttribute("Description", kamelet.Spec.Definition.Description)
+		dw.WriteAttribute("Phase", string(kamelet.Status.Phase))
+	}
+
+	if isKameletDeprecated(kamelet) {
+		dw.WriteAttribute("Deprecated", "true")
+		if replacement := kamelet.Annotations[kameletReplacedByAnnotation]; replacement != "" {
+			dw.WriteAttribute("Replaced By", replacement)
+		}
+	}
+
+	if printDetails {
+		writeKameletPresentation(dw, kamelet)
+		writeKameletDataTypes(dw, kamelet)
+		writeKameletDependencies(dw, kamelet)
+	}
+}
+
+// writeKameletMarkdownDescription prints the rendered markdown description of given Kamelet. Descriptions spanning
+// multiple lines are written as indented block below the title.
+func writeKameletMarkdownDescription(dw printers.PrefixWriter, kamelet *v1alpha1.Kamelet) {
+	definition := kameletDefinition(kamelet)
+	title := definition.Title
+	description := renderMarkdown(definition.Description)
+	if !strings.Contains(description, "\n") {
+		if title != "" && description != "" {
+			description = title + " - " + description
+		} else if title != "" {
+			description = title
+		}
+		dw.WriteAttribute("Description", description)
+		return
+	}
+
+	section := dw.WriteAttribute("Description", title)
+	for _, line := range strings.Split(description, "\n") {
+		section.Writef("%s\n", line)
+	}
+}
+
+// writeKameletPresentation prints the known presentation annotations of given Kamelet with human friendly labels
+func writeKameletPresentation(dw printers.PrefixWriter, kamelet *v1alpha1.Kamelet) {
+	var section printers.PrefixWriter
+	for _, presentation := range presentationAnnotations {
+		value := kamelet.Annotations[presentation.annotation]
+		if value == "" {
+			continue
+		}
+		if section == nil {
+			section = dw.WriteAttribute("Presentation", "")
+		}
+		section.WriteAttribute(presentation.label, truncate(value, commands.TruncateAt))
+	}
+}
+
+// withoutPresentationAnnotations returns a copy of given metadata without the known presentation annotations
+func withoutPresentationAnnotations(meta *v1.ObjectMeta) *v1.ObjectMeta {
+	filtered := meta.DeepCopy()
+	for _, presentation := range presentationAnnotations {
+		delete(filtered.Annotations, presentation.annotation)
+	}
+	return filtered
+}
+
+// writeKameletDependencies prints the runtime dependencies declared by given Kamelet
+func writeKameletDependencies(dw printers.PrefixWriter, kamelet *v1alpha1.Kamelet) {
+	if len(kamelet.Spec.Dependencies) == 0 {
+		return
+	}
+
+	section := dw.WriteAttribute("Dependencies", "")
+	for _, dependency := range kamelet.Spec.Dependencies {
+		section.Writef("%s\n", dependency)
+	}
+}
+
+// writeKameletDataTypes prints the media types declared for the event slots of given Kamelet
+func writeKameletDataTypes(dw printers.PrefixWriter, kamelet *v1alpha1.Kamelet) {
+	if len(kamelet.Spec.Types) == 0 {
+		return
+	}
+
+	// well known slots come first in their natural order, any other slots follow sorted by name
+	var slots, otherSlots []v1alpha1.EventSlot
+	for _, slot := range []v1alpha1.EventSlot{v1alpha1.EventSlotIn, v1alpha1.EventSlotOut, v1alpha1.EventSlotError} {
+		if _, ok := kamelet.Spec.Types[slot]; ok {
+			slots = append(slots, slot)
+		}
+	}
+	for slot := range kamelet.Spec.Types {
+		if slot != v1alpha1.EventSlotIn && slot != v1alpha1.EventSlotOut && slot != v1alpha1.EventSlotError {
+			otherSlots = append(otherSlots, slot)
+		}
+	}
+	sort.Slice(otherSlots, func(i, j int) bool { return otherSlots[i] < otherSlots[j] })
+	slots = append(slots, otherSlots...)
+
+	section := dw.WriteAttribute("Data Types", "")
+	for _, slot := range slots {
+		mediaType := kamelet.Spec.Types[slot].MediaType
+		if mediaType == "" {
+			mediaType = "<unknown>"
+		}
+		section.WriteAttribute(string(slot), mediaType)
+	}
+}
+
+// writeKameletConditions prints the conditions block of given Kamelet and returns the number of lines written
+func writeKameletConditions(out io.Writer, kamelet *v1alpha1.Kamelet, printDetails bool) (int, error) {
+	buf := &bytes.Buffer{}
+	dw := printers.NewPrefixWriter(buf)
+	commands.WriteConditions(dw, asApiConditions(kamelet.Status.Conditions), printDetails)
+	if err := dw.Flush(); err != nil {
+		return 0, err
+	}
+	if _, err := out.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return bytes.Count(buf.Bytes(), []byte("\n")), nil
+}
+
+// isKameletReady returns true if the Ready condition of given Kamelet is true
+func isKameletReady(kamelet *v1alpha1.Kamelet) bool {
+	return readyCondition(kamelet.Status.Conditions) == string(corev1.ConditionTrue)
+}
+
+// kameletReadiness is the readiness function used when watching Kamelets
+func kameletReadiness(obj runtime.Object) (bool, string) {
+	kamelet, ok := obj.(*v1alpha1.Kamelet)
+	if !ok {
+		return false, ""
+	}
+	return isKameletReady(kamelet), nonReadyConditionReason(kamelet.Status.Conditions)
+}
+
+// writeKameletSource prints the flow of the Kamelet holding its route template as YAML document
+func writeKameletSource(dw printers.PrefixWriter, kamelet *v1alpha1.Kamelet) error {
+	if kamelet.Spec.Flow == nil || len(kamelet.Spec.Flow.RawMessage) == 0 {
+		dw.WriteAttribute("Source", "Kamelet defines no flow")
+		return nil
+	}
+	source, err := yaml.JSONToYAML(kamelet.Spec.Flow.RawMessage)
+	if err != nil {
+		return fmt.Errorf("unable to print the flow of Kamelet %s: %w", kamelet.Name, err)
+	}
+
+	section := dw.WriteAttribute("Source", "")
+	section.Writef("---\n")
+	for _, line := range strings.Split(strings.TrimSuffix(string(source), "\n"), "\n") {
+		section.Writef("%s\n", line)
+	}
+	section.Writef("...\n")
+	return nil
+}
+
+// writeKameletProperties prints the Kamelet properties either as verbose tables grouped into required and
+// optional properties or as single line summary in given sort order. With check the verbose tables get a NOTE
+// column flagging the required properties without default.
+func writeKameletProperties(dw printers.PrefixWriter, kamelet *v1alpha1.Kamelet, printDetails bool, sortBy string, check bool) {
+	definition := kameletDefinition(kamelet)
+	if len(definition.Properties) == 0 {
+		dw.WriteAttribute("Properties", "No properties")
+		return
+	}
+
+	if !printDetails {
+		propertyNames := sortedPropertyNames(definition, sortBy)
+		summary := make([]string, 0, len(propertyNames))
+		for _, propertyName := range propertyNames {
+			entry := propertyName
+			if isRequired(definition, propertyName) {
+				entry += "*"
+			}
+			if defaultValue := propertyDefault(definition.Properties[propertyName]); defaultValue != "" {
+				entry += "=" + truncate(defaultValue, maxDefaultWidth)
+			}
+			summary = append(summary, entry)
+		}
+		dw.WriteAttribute("Properties", truncate(strings.Join(summary, ", "), commands.TruncateAt))
+		return
+	}
+
+	propertyNames := sortedPropertyNames(definition, propertySortByName)
+	required := []string{}
+	optional := []string{}
+	defaults := make(map[string]string, len(propertyNames))
+	defaultWidth := len("DEFAULT")
+	for _, propertyName := range propertyNames {
+		if isRequired(definition, propertyName) {
+			required = append(required, propertyName)
+		} else {
+			optional = append(optional, propertyName)
+		}
+		defaults[propertyName] = truncate(propertyDefault(definition.Properties[propertyName]), maxDefaultWidth)
+		if width := utf8.RuneCountInString(defaults[propertyName]); width > defaultWidth {
+			defaultWidth = width
+		}
+	}
+
+	section := dw.WriteAttribute("Properties", "")
+	// column widths are shared by both sub-sections so that they line up
+	maxLen := getMaxPropertyNameLen(propertyNames)
+	typeFormat := " %-" + strconv.Itoa(propertyTypeWidth) + "s"
+	format := "%-" + strconv.Itoa(maxLen) + "s" + typeFormat + " %-" + strconv.Itoa(defaultWidth) + "s %s\n"
+	descriptionWidth := commands.TruncateAt - maxLen - defaultWidth - propertyTablePadding
+	if check {
+		format = "%-" + strconv.Itoa(maxLen) + "s" + typeFormat + " %-" + strconv.Itoa(defaultWidth) + "s %-" +
+			strconv.Itoa(len(bindTimeNote)) + "s %s\n"
+		descriptionWidth -= len(bindTimeNote) + 1
+	}
+	// long property names or defaults would leave no room for the description, which overflows the line instead
+	if descriptionWidth < minDescriptionWidth {
+		descriptionWidth = minDescriptionWidth
+	}
+	writeGroup := func(label string, names []string) {
+		if len(names) == 0 {
+			return
+		}
+		group := section.WriteAttribute(label, "")
+		if check {
+			group.Writef(format, "NAME", "TYPE", "DEFAULT", "NOTE", "DESCRIPTION")
+		} else {
+			group.Writef(format, "NAME", "TYPE", "DEFAULT", "DESCRIPTION")
+		}
+		for _, propertyName := range names {
+			property := definition.Properties[propertyName]
+			description := truncate(propertyDescriptionWithEnum(property), descriptionWidth)
+			if !check {
+				group.Writef(format, propertyName, property.Type, defaults[propertyName], description)
+				continue
+			}
+			note := ""
+			if isRequired(definition, propertyName) && defaults[propertyName] == "" {
+				note = bindTimeNote
+			}
+			group.Writef(format, propertyName, property.Type, defaults[propertyName], note, description)
+		}
+	}
+	writeGroup("Required Properties", required)
+	writeGroup("Optional Properties", optional)
+}
+
+// bindTimeNote flags required properties without default in the output of describe-type --check
+const bindTimeNote = "must be supplied at bind time"
+
+// writeKameletCheck prints whether all required properties of the Kamelet have defaults, listing those that do not
+func writeKameletCheck(dw printers.PrefixWriter, kamelet *v1alpha1.Kamelet) {
+	missing := missingRequiredProperties(kameletDefinition(kamelet), nil)
+	if len(missing) == 0 {
+		dw.WriteAttribute("Check", "All required properties have defaults")
+		return
+	}
+	section := dw.WriteAttribute("Check", fmt.Sprintf("%d required properties without default", len(missing)))
+	for _, propertyName := range missing {
+		section.WriteAttribute(propertyName, bindTimeNote)
+	}
+}
+
+// kameletDefinition returns the definition of given Kamelet, or an empty definition for Kamelets defining none
+func kameletDefinition(kamelet *v1alpha1.Kamelet) *v1alpha1.JSONSchemaProps {
+	if kamelet.Spec.Definition == nil {
+		return &v1alpha1.JSONSchemaProps{}
+	}
+	return kamelet.Spec.Definition
+}
+
+// writeKameletProperty prints all details of the Kamelet property with given name
+func writeKameletProperty(dw printers.PrefixWriter, kamelet *v1alpha1.Kamelet, propertyName string) error {
+	definition := kamelet.Spec.Definition
+	if definition == nil || len(definition.Properties) == 0 {
+		return &ErrPropertyUnknown{Kamelet: kamelet.Name, Property: propertyName}
+	}
+	property, ok := definition.Properties[propertyName]
+	if !ok {
+		return &ErrPropertyUnknown{Kamelet: kamelet.Name, Property: propertyName,
+			ValidProperties: sortedPropertyNames(definition, propertySortByName)}
+	}
+
+	dw.WriteAttribute("Name", propertyName)
+	if property.Title != "" {
+		dw.WriteAttribute("Title", property.Title)
+	}
+	dw.WriteAttribute("Type", property.Type)
+	if property.Format != "" {
+		dw.WriteAttribute("Format", property.Format)
+	}
+	dw.WriteAttribute("Required", strconv.FormatBool(isRequired(definition, propertyName)))
+	if defaultValue := propertyDefault(property); defaultValue != "" {
+		dw.WriteAttribute("Default", defaultValue)
+	}
+	if values := enumValues(property); len(values) > 0 {
+		dw.WriteAttribute("Enum", strings.Join(values, ", "))
+	}
+	dw.WriteAttribute("Description", property.Description)
+	return nil
+}
+
+// bindCommandExample returns a bind command line for given Kamelet holding its required properties and
+// the properties with default values
+func bindCommandExample(kamelet *v1alpha1.Kamelet) string {
+	args := []string{"kn-source-kamelet", "bind", kamelet.Name, "--sink", shellQuote("<SINK>")}
+	if kamelet.Spec.Definition != nil {
+		definition := kamelet.Spec.Definition
+		for _, propertyName := range sortedPropertyNames(definition, propertySortByRequired) {
+			property := definition.Properties[propertyName]
+			value, hasDefault := propertyExampleValue(property)
+			if !hasDefault && !isRequired(definition, propertyName) {
+				continue
+			}
+			args = append(args, "-p", propertyName+"="+shellQuote(value))
+		}
+	}
+	return strings.Join(args, " ")
+}
+
+// propertyExampleValue returns the default of given property as plain value suitable for the bind command,
+// or a placeholder holding the property type if there is no default
+func propertyExampleValue(property v1alpha1.JSONSchemaProps) (string, bool) {
+	defaultValue := propertyDefault(property)
+	if defaultValue == "" {
+		propertyType := property.Type
+		if propertyType == "" {
+			propertyType = "value"
+		}
+		return "<" + propertyType + ">", false
+	}
+	var value string
+	if err := json.Unmarshal([]byte(defaultValue), &value); err == nil {
+		return value, true
+	}
+	return defaultValue, true
+}
+
+// shellQuote wraps given value in single quotes if it contains characters the shell would interpret
+func shellQuote(value string) string {
+	if value != "" && strings.IndexFunc(value, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("_-.,/:=@%+", r))
+	}) < 0 {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// kameletPropertyInfo is the flattened representation of a Kamelet property printed with -o json-properties
+type kameletPropertyInfo struct {
+	Name        string            `json:"name"`
+	Type        string            `json:"type"`
+	Required    bool              `json:"required"`
+	Default     *json.RawMessage  `json:"default"`
+	Enum        []json.RawMessage `json:"enum,omitempty"`
+	Description string            `json:"description"`
+}
+
+// writeKameletPropertiesJSON prints the Kamelet properties as JSON array of flattened property objects
+func writeKameletPropertiesJSON(out io.Writer, kamelet *v1alpha1.Kamelet, sortBy string) error {
+	properties := []kameletPropertyInfo{}
+	if definition := kamelet.Spec.Definition; definition != nil {
+		for _, propertyName := range sortedPropertyNames(definition, sortBy) {
+			property := definition.Properties[propertyName]
+			info := kameletPropertyInfo{
+				Name:        propertyName,
+				Type:        property.Type,
+				Required:    isRequired(definition, propertyName),
+				Description: property.Description,
+			}
+			if defaultValue := propertyDefault(property); defaultValue != "" {
+				raw := json.RawMessage(defaultValue)
+				info.Default = &raw
+			}
+			for _, value := range property.Enum {
+				info.Enum = append(info.Enum, json.RawMessage(compactJSON(value)))
+			}
+			properties = append(properties, info)
+		}
+	}
+
+	data, err := json.MarshalIndent(properties, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "%s\n", data)
+	return err
+}
+
+// writeKameletJSONSchema prints the definition of given Kamelet as draft-07 JSON Schema object
+func writeKameletJSONSchema(out io.Writer, kamelet *v1alpha1.Kamelet) error {
+	definition := v1alpha1.JSONSchemaProps{}
+	if kamelet.Spec.Definition != nil {
+		definition = *kamelet.Spec.Definition
+	}
+	definition.Schema = jsonSchemaDraft07
+	definition.Type = "object"
+
+	data, err := json.MarshalIndent(definition, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "%s\n", data)
+	return err
+}
+
+// enumValues returns the allowed values of given property as plain values, strings are given without quotes
+func enumValues(property v1alpha1.JSONSchemaProps) []string {
+	values := make([]string, 0, len(property.Enum))
+	for _, value := range property.Enum {
+		raw := compactJSON(value)
+		var text string
+		if err := json.Unmarshal([]byte(raw), &text); err == nil {
+			raw = text
+		}
+		values = append(values, raw)
+	}
+	return values
+}
+
+// propertyDescriptionWithEnum returns the description of given property followed by its allowed values if restricted
+func propertyDescriptionWithEnum(property v1alpha1.JSONSchemaProps) string {
+	values := enumValues(property)
+	if len(values) == 0 {
+		return property.Description
+	}
+	return strings.TrimSpace(property.Description + " [one of: " + strings.Join(values, ", ") + "]")
+}
+
+// propertyDefault returns the default value of given property as compact JSON or empty string if not set
+func propertyDefault(property v1alpha1.JSONSchemaProps) string {
+	return compactJSON(property.Default)
+}
+
+// compactJSON returns given JSON value without insignificant whitespace or empty string if not set
+func compactJSON(value *v1alpha1.JSON) string {
+	if value == nil || len(value.RawMessage) == 0 {
+		return ""
+	}
+	buf := &bytes.Buffer{}
+	if err := json.Compact(buf, value.RawMessage); err != nil {
+		return string(value.RawMessage)
 	}
+	return buf.String()
+}
+
+// sortedPropertyNames returns the property names ordered by name or with the required properties first
+func sortedPropertyNames(definition *v1alpha1.JSONSchemaProps, sortBy string) []string {
+	propertyNames := make([]string, 0, len(definition.Properties))
+	for propertyName := range definition.Properties {
+		propertyNames = append(propertyNames, propertyName)
+	}
+	sort.Strings(propertyNames)
+
+	if sortBy == propertySortByRequired {
+		sort.SliceStable(propertyNames, func(i, j int) bool {
+			return isRequired(definition, propertyNames[i]) && !isRequired(definition, propertyNames[j])
+		})
+	}
+	return propertyNames
+}
+
+// validatePropertySortBy checks that given sort order is supported
+func validatePropertySortBy(sortBy string) error {
+	for _, s := range propertySortByValues {
+		if s == sortBy {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid sort order '%s', must be one of: %s", sortBy, strings.Join(propertySortByValues, ", "))
+}
+
+// isRequired returns true if given property is listed as required in the Kamelet definition
+func isRequired(definition *v1alpha1.JSONSchemaProps, propertyName string) bool {
+	for _, required := range definition.Required {
+		if required == propertyName {
+			return true
+		}
+	}
+	return false
+}
 
-	dw.WriteAttribute("Phase", string(kamelet.Status.Phase))
+// getMaxPropertyNameLen returns the length of the longest property name, at least the header length
+func getMaxPropertyNameLen(propertyNames []string) int {
+	max := len("NAME")
+	for _, propertyName := range propertyNames {
+		if width := utf8.RuneCountInString(propertyName); width > max {
+			max = width
+		}
+	}
+	return max
 }
 
-func isEventSourceType(kamelet *v1alpha1.Kamelet) bool {
-	return kamelet.Labels["camel.apache.org/kamelet.type"] == "source"
+// truncate cuts off given value with an ellipsis when it exceeds given width
+func truncate(value string, width int) string {
+	runes := []rune(value)
+	if width < 4 || len(runes) <= width {
+		return value
+	}
+	return string(runes[:width-4]) + " ..."
 }
 
 func asApiConditions(conditions []v1alpha1.KameletCondition) apis.Conditions {
@@ -17,15 +17,27 @@
 package command
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	camelv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	camelkapis "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
 	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
 	"knative.dev/client/pkg/kn/commands"
 	"knative.dev/client/pkg/util"
 	"knative.dev/kn-plugin-source-kamelet/internal/client"
+	clienttesting "knative.dev/kn-plugin-source-kamelet/internal/client/testing"
 
 	"gotest.tools/v3/assert"
 )
@@ -45,7 +57,7 @@ func TestDescribeTypeErrorCase(t *testing.T) {
 	recorder := mockClient.Recorder()
 
 	_, err := runDescribeTypeCmd(mockClient)
-	assert.Error(t, err, "'kn-source-kamelet describe-type' requires the Kamelet name given as single argument")
+	assert.Error(t, err, "'kn-source-kamelet describe-type' requires at least one Kamelet name given as argument")
 	recorder.Validate()
 }
 
@@ -61,43 +73,1194 @@ func TestDescribeTypeErrorCaseNotFound(t *testing.T) {
 	recorder.Validate()
 }
 
+func TestDescribeTypeMultiple(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+	recorder.Get(createKamelet("k2"), nil)
+	output, err := runDescribeTypeCmd(mockClient, "k1", "k2")
+	assert.NilError(t, err)
+	outputLines := strings.Split(output, "\n")
+	assert.Check(t, util.ContainsAll(outputLines[0], "Name:", "k1"))
+	// the second Kamelet follows after an empty line
+	second := indexOfLine(outputLines[1:], "Name:") + 1
+	assert.Assert(t, second > 1)
+	assert.Equal(t, outputLines[second-1], "")
+	assert.Check(t, util.ContainsAll(outputLines[second], "k2"))
+
+	recorder.Get(createKamelet("k1"), nil)
+	recorder.Get(createKamelet("k2"), nil)
+	output, err = runDescribeTypeCmd(mockClient, "k1", "k2", "-o", "yaml")
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsAll(output, "kind: KameletList", "name: k1", "name: k2"))
+
+	_, err = runDescribeTypeCmd(mockClient, "k1", "k2", "--watch")
+	assert.Error(t, err, "--watch, --schema, --property and --output json-properties require a single Kamelet name")
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeMultipleNotFound(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	notFound := apierrors.NewNotFound(schema.GroupResource{Group: "camel.apache.org", Resource: "kamelets"}, "k2")
+	recorder.Get(createKamelet("k1"), nil)
+	recorder.Get(nil, notFound)
+	recorder.Get(createKamelet("k3"), nil)
+	output, err := runDescribeTypeCmd(mockClient, "k1", "k2", "k3", "-o", "name")
+	assert.ErrorContains(t, err, "unable to describe 1 of 3 Kamelets (k2): ")
+	assert.Equal(t, ExitCode(err), NotFoundExitCode)
+	assert.Assert(t, strings.HasPrefix(output, "kamelet.camel.apache.org/k1\nkamelet.camel.apache.org/k3\n"))
+
+	recorder.Validate()
+}
+
 func TestDescribeTypeErrorCaseNoEventSource(t *testing.T) {
 	mockClient := client.NewMockKameletClient(t)
 	recorder := mockClient.Recorder()
 
 	kamelet := createKamelet("k1")
-	kamelet.Labels = map[string]string{
-		"camel.apache.org/kamelet.type": "sink",
+	kamelet.Labels = map[string]string{
+		"camel.apache.org/kamelet.type": "sink",
+	}
+	recorder.Get(kamelet, nil)
+
+	_, err := runDescribeTypeCmd(mockClient, "k1")
+	assert.Error(t, err, "Kamelet k1 is a sink, not a source; use --type sink")
+	recorder.Validate()
+}
+
+func TestDescribeTypeErrorCaseNoType(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	kamelet.Labels = map[string]string{}
+	recorder.Get(kamelet, nil)
+
+	_, err := runDescribeTypeCmd(mockClient, "k1", "--type", "action")
+	assert.Error(t, err, "Kamelet k1 has no type, not an action")
+	recorder.Validate()
+}
+
+func TestDescribeTypeErrorCaseInvalidType(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	_, err := runDescribeTypeCmd(mockClient, "k1", "--type", "foo")
+	assert.Error(t, err, "invalid Kamelet type 'foo', must be one of: source, sink, action")
+	recorder.Validate()
+}
+
+func TestDescribeTypeErrorCaseInvalidOutput(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	_, err := runDescribeTypeCmd(mockClient, "k1", "-o", "yml")
+	assert.ErrorContains(t, err, "unable to match a printer suitable for the output format \"yml\", allowed formats are: ")
+	assert.ErrorContains(t, err, "yaml")
+	assert.ErrorContains(t, err, "url")
+	// the output format is rejected before the Kamelet is fetched
+	recorder.Validate()
+}
+
+func TestDescribeTypeURLOutputValidated(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	recorder.Get(kamelet, nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1", "-o", "URL")
+	assert.NilError(t, err)
+	assert.Equal(t, output, kameletURL(kamelet)+"\n")
+	recorder.Validate()
+}
+
+func TestDescribeTypeFromFile(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	output, err := runDescribeTypeCmd(mockClient, "-f", "testdata/timer-source.kamelet.yaml")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "timer-source", "Timer Source", "Produces periodic events",
+		"message", "period", "1000"))
+
+	output, err = runDescribeTypeCmd(mockClient, "-f", "testdata/timer-source.kamelet.yaml", "--example")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "kn-source-kamelet bind timer-source", "message="))
+
+	output, err = runDescribeTypeCmd(mockClient, "-f", "testdata/timer-source.kamelet.yaml", "-o", "jsonpath={.spec.types.out.mediaType}")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "text/plain")
+
+	// the cluster is not used at all
+	recorder.Validate()
+}
+
+func TestDescribeTypeFromDirectory(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	output, err := runDescribeTypeCmd(mockClient, "-f", "testdata/kamelets", "-R", "-o", "name")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "kamelet.camel.apache.org/ping-source\nkamelet.camel.apache.org/hello-source\n"+
+		"kamelet.camel.apache.org/tick-source\n")
+
+	output, err = runDescribeTypeCmd(mockClient, "-f", "testdata/kamelets", "--recursive")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Ping Source", "Hello Source", "greeting", "Tick Source"))
+
+	_, err = runDescribeTypeCmd(mockClient, "-f", "testdata/kamelets")
+	assert.Error(t, err, "'testdata/kamelets' is a directory, use --recursive to describe the Kamelets in it")
+
+	_, err = runDescribeTypeCmd(mockClient, "-f", "testdata/kamelets", "-R", "--schema")
+	assert.Error(t, err, "--schema, --property and --output json-properties require a single Kamelet, "+
+		"but 'testdata/kamelets' holds 3 Kamelets")
+
+	_, err = runDescribeTypeCmd(mockClient, "k1", "-R")
+	assert.Error(t, err, "--recursive requires a directory given with --filename")
+
+	// the cluster is not used at all
+	recorder.Validate()
+}
+
+func TestReadKameletFiles(t *testing.T) {
+	warnings := &bytes.Buffer{}
+	kamelets, err := readKameletFiles(nil, warnings, "testdata/kamelets", true)
+	assert.NilError(t, err)
+	assert.Equal(t, len(kamelets), 3)
+	assert.Equal(t, warnings.String(), "Warning: skipping document: invalid Kamelet file 'testdata/kamelets/nested/binding.yaml', "+
+		"expected kind Kamelet but found KameletBinding\n"+
+		"Warning: skipping document: invalid Kamelet file 'testdata/kamelets/sources.yaml', "+
+		"expected kind Kamelet but found KameletBinding\n")
+
+	// documents of a file given directly must all be Kamelets
+	_, err = readKameletFiles(nil, warnings, "testdata/kamelets/sources.yaml", false)
+	assert.Error(t, err, "invalid Kamelet file 'testdata/kamelets/sources.yaml', expected kind Kamelet but found KameletBinding")
+
+	kamelets, err = readKameletFiles(strings.NewReader("kind: Kamelet\napiVersion: camel.apache.org/v1\nmetadata:\n  name: a\n"+
+		"---\nkind: Kamelet\napiVersion: camel.apache.org/v1\nmetadata:\n  name: b\n"), warnings, "-", false)
+	assert.NilError(t, err)
+	assert.Equal(t, len(kamelets), 2)
+	assert.Equal(t, kamelets[1].Name, "b")
+
+	_, err = readKameletFiles(nil, warnings, "testdata/custom-columns.txt", true)
+	assert.ErrorContains(t, err, "invalid Kamelet file 'testdata/custom-columns.txt'")
+
+	_, err = readKameletFiles(strings.NewReader("---\n"), warnings, "-", false)
+	assert.Error(t, err, "invalid Kamelet file '-', the file holds no manifest")
+}
+
+func TestDescribeTypeErrorCaseFromFile(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+
+	_, err := runDescribeTypeCmd(mockClient, "k1", "-f", "testdata/timer-source.kamelet.yaml")
+	assert.Error(t, err, "'kn-source-kamelet describe-type' accepts either the Kamelet name or --filename, not both")
+
+	_, err = runDescribeTypeCmd(mockClient, "-f", "testdata/timer-source.kamelet.yaml", "--watch")
+	assert.Error(t, err, "--filename can not be combined with --watch")
+
+	_, err = runDescribeTypeCmd(mockClient, "-f", "testdata/missing.yaml")
+	assert.ErrorContains(t, err, "unable to read Kamelet file 'testdata/missing.yaml'")
+
+	_, err = runDescribeTypeCmd(mockClient, "-f", "testdata/binding.yaml")
+	assert.Error(t, err, "invalid Kamelet file 'testdata/binding.yaml', expected kind Kamelet but found KameletBinding")
+
+	_, err = runDescribeTypeCmd(mockClient, "-f", "testdata/properties.yaml")
+	assert.Error(t, err, "invalid Kamelet file 'testdata/properties.yaml', the manifest has no kind, expected Kamelet")
+
+	_, err = runDescribeTypeCmd(mockClient, "-f", "testdata/timer-source.kamelet.yaml", "--type", "sink")
+	assert.Error(t, err, "Kamelet timer-source is a source, not a sink; use --type source")
+
+	mockClient.Recorder().Validate()
+}
+
+func TestDecodeKamelet(t *testing.T) {
+	_, err := decodeKamelet([]byte("kind: Kamelet\napiVersion: example.com/v1\n"), "k.yaml")
+	assert.Error(t, err, "invalid Kamelet file 'k.yaml', expected API group camel.apache.org but found API version 'example.com/v1'")
+
+	_, err = decodeKamelet([]byte("- kind: Kamelet"), "k.yaml")
+	assert.ErrorContains(t, err, "invalid Kamelet file 'k.yaml', expected YAML or JSON manifest")
+
+	_, err = decodeKamelet([]byte(`{"kind":"Kamelet","apiVersion":"camel.apache.org/v1alpha1","spec":{"definition":"none"}}`), "k.json")
+	assert.ErrorContains(t, err, "invalid Kamelet file 'k.json': ")
+
+	kamelet, err := decodeKamelet([]byte(`{"kind":"Kamelet","apiVersion":"camel.apache.org/v1","metadata":{"name":"k1"}}`), "k.json")
+	assert.NilError(t, err)
+	assert.Equal(t, kamelet.Name, "k1")
+}
+
+func TestDescribeTypeSecrets(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "accessKey", "string", "The access key", true)
+	addKameletProperty(kamelet, "secretKey", "string", "The secret key", true)
+	addKameletProperty(kamelet, "token", "string", "The token", false)
+	addKameletProperty(kamelet, "region", "string", "The region", false)
+	secretKey := kamelet.Spec.Definition.Properties["secretKey"]
+	secretKey.Format = "password"
+	kamelet.Spec.Definition.Properties["secretKey"] = secretKey
+	token := kamelet.Spec.Definition.Properties["token"]
+	token.XDescriptors = []string{"urn:alm:descriptor:com.tectonic.ui:password"}
+	kamelet.Spec.Definition.Properties["token"] = token
+	recorder.Get(kamelet, nil)
+	recorder.Get(createKamelet("k2"), nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1")
+	assert.NilError(t, err)
+	outputLines := strings.Split(output, "\n")
+	i := indexOfLine(outputLines, "Secrets:")
+	assert.Assert(t, i >= 0)
+	assert.Check(t, util.ContainsAll(outputLines[i], "secretKey, token"))
+	assert.Check(t, util.ContainsNone(outputLines[i], "accessKey", "region"))
+	assert.Check(t, util.ContainsAll(outputLines[i+2], "--secret PROPERTY=SECRET[/KEY]"))
+
+	output, err = runDescribeTypeCmd(mockClient, "k2")
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsNone(output, "Secrets:"))
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeSinkOutput(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("log-sink")
+	kamelet.Labels[kameletTypeLabel] = kameletTypeSink
+	recorder.Get(kamelet, nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "log-sink", "--type", "sink")
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsAll(output, "Name:", "log-sink"))
+	assert.Check(t, util.ContainsAll(output, "Type:", "sink"))
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeOutput(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	recorder.Get(kamelet, nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1")
+	assert.NilError(t, err)
+
+	outputLines := strings.Split(output, "\n")
+
+	assert.Check(t, util.ContainsAll(outputLines[0], "Name:", "k1"))
+	assert.Check(t, util.ContainsAll(outputLines[1], "Namespace:", "default"))
+	assert.Check(t, util.ContainsAll(outputLines[2], "Labels:", "camel.apache.org/kamelet.type=source"))
+	assert.Check(t, util.ContainsAll(outputLines[3], "Age:", "0s"))
+	assert.Check(t, util.ContainsAll(outputLines[4], "Description:", "Kamelet k1 - Sample Kamelet source"))
+	assert.Check(t, util.ContainsAll(outputLines[5], "Type:", "source"))
+	assert.Check(t, util.ContainsAll(outputLines[6], "Phase:", "Ready"))
+	assert.Check(t, util.ContainsAll(outputLines[7], "Properties:", "No properties"))
+
+	assert.Check(t, util.ContainsAll(outputLines[9], "Conditions:"))
+	assert.Check(t, util.ContainsAll(outputLines[10], "OK", "TYPE", "AGE", "REASON"))
+	assert.Check(t, util.ContainsAll(outputLines[11], "++", "Ready", "", ""))
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeEmptyDefinition(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	kamelet.Spec.Definition = nil
+	for _, args := range [][]string{{"k1"}, {"k1", "--verbose"}, {"k1", "--markdown"}} {
+		recorder.Get(kamelet, nil)
+		output, err := runDescribeTypeCmd(mockClient, args...)
+		assert.NilError(t, err)
+		assert.Assert(t, util.ContainsAll(output, "Name:", "k1", "Description:", "Properties:", "No properties", "Conditions:"))
+	}
+
+	// a definition without properties is described the same way
+	kamelet = createKamelet("k1")
+	kamelet.Spec.Definition.Properties = nil
+	recorder.Get(kamelet, nil)
+	output, err := runDescribeTypeCmd(mockClient, "k1", "--verbose")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Properties:", "No properties"))
+	assert.Assert(t, util.ContainsNone(output, "Required Properties", "Optional Properties"))
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeMarkdownOutput(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	kamelet.Spec.Definition.Description = "Produces events with a `message`:\n- every **period**\n- see [docs](https://camel.apache.org/)"
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1")
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsAll(output, "Kamelet k1 - Produces events with a `message`:", "- every **period**"))
+
+	output, err = runDescribeTypeCmd(mockClient, "k1", "--markdown")
+	assert.NilError(t, err)
+	outputLines := strings.Split(output, "\n")
+	start := indexOfLine(outputLines, "Description:")
+	assert.Assert(t, start >= 0)
+	assert.Check(t, util.ContainsAll(outputLines[start], "Kamelet k1"))
+	assert.Check(t, util.ContainsAll(outputLines[start+1], "Produces events with a message:"))
+	assert.Check(t, util.ContainsAll(outputLines[start+2], "• every period"))
+	assert.Check(t, util.ContainsAll(outputLines[start+3], "• see docs (https://camel.apache.org/)"))
+	assert.Check(t, util.ContainsAll(outputLines[start+4], "Type:", "source"))
+
+	output, err = runDescribeTypeCmd(mockClient, "k1", "--markdown", "-o", "yaml")
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsAll(output, "`message`", "**period**"))
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeProviderOutput(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	kamelet.Annotations = map[string]string{
+		kameletProviderAnnotation: "Apache Software Foundation",
+	}
+	recorder.Get(kamelet, nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1")
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsAll(output, "Provider:", "Apache Software Foundation"))
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeDataTypesOutput(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	kamelet.Spec.Types = map[camelkapis.EventSlot]camelkapis.EventTypeSpec{
+		camelkapis.EventSlotError: {},
+		camelkapis.EventSlotOut:   {MediaType: "application/json"},
+	}
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1")
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsNone(output, "Data Types:", "application/json"))
+
+	output, err = runDescribeTypeCmd(mockClient, "k1", "--verbose")
+	assert.NilError(t, err)
+	outputLines := strings.Split(output, "\n")
+	start := indexOfLine(outputLines, "Data Types:")
+	assert.Assert(t, start >= 0)
+	assert.Check(t, util.ContainsAll(outputLines[start+1], "out:", "application/json"))
+	assert.Check(t, util.ContainsAll(outputLines[start+2], "error:", "<unknown>"))
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeNoDataTypesOutput(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1", "--verbose")
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsNone(output, "Data Types:"))
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeExample(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "message", "string", "The message to generate", true)
+	addKameletProperty(kamelet, "period", "integer", "The time interval between two events", true)
+	addKameletProperty(kamelet, "greeting", "string", "The greeting to use", false)
+	addKameletProperty(kamelet, "count", "integer", "The number of events", false)
+	addKameletProperty(kamelet, "user", "string", "The user name", false)
+	setKameletPropertyDefault(kamelet, "period", "1000")
+	setKameletPropertyDefault(kamelet, "greeting", `"Hello World"`)
+	setKameletPropertyDefault(kamelet, "user", `"O'Neil"`)
+	recorder.Get(kamelet, nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1", "--example")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "kn-source-kamelet bind k1 --sink '<SINK>' -p message='<string>' -p period=1000 "+
+		`-p greeting='Hello World' -p user='O'\''Neil'`+"\n")
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeExampleWithOutput(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+
+	_, err := runDescribeTypeCmd(mockClient, "k1", "--example", "-o", "yaml")
+	assert.Error(t, err, "--example can not be combined with --watch or --output")
+
+	mockClient.Recorder().Validate()
+}
+
+func TestDescribeTypeCheck(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "message", "string", "The message to generate", true)
+	addKameletProperty(kamelet, "period", "integer", "The time interval between two events", true)
+	addKameletProperty(kamelet, "user", "string", "The user name", false)
+	setKameletPropertyDefault(kamelet, "period", "1000")
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1", "--check")
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsAll(output, "Check:", "1 required properties without default", "message:", "must be supplied at bind time"))
+	assert.Check(t, util.ContainsNone(output, "period:", "NOTE"))
+
+	output, err = runDescribeTypeCmd(mockClient, "k1", "--check", "--verbose")
+	assert.NilError(t, err)
+	outputLines := strings.Split(output, "\n")
+	required := indexOfLine(outputLines, "  Required Properties:")
+	optional := indexOfLine(outputLines, "  Optional Properties:")
+	assert.Check(t, util.ContainsAll(outputLines[required+1], "NAME", "DEFAULT", "NOTE", "DESCRIPTION"))
+	assert.Check(t, util.ContainsAll(outputLines[required+2], "message", "must be supplied at bind time", "The message to generate"))
+	assert.Check(t, util.ContainsAll(outputLines[required+3], "period", "1000", "The time interval"))
+	assert.Check(t, util.ContainsNone(outputLines[required+3], "must be supplied"))
+	assert.Check(t, util.ContainsNone(outputLines[optional+2], "must be supplied"))
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeCheckLongColumns(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	name := strings.Repeat("p", 60)
+	addKameletProperty(kamelet, name, "string", "Überschrift der Nachricht, die bei jedem Ereignis gesendet wird", true)
+	setKameletPropertyDefault(kamelet, name, `"`+strings.Repeat("d", 30)+`"`)
+	recorder.Get(kamelet, nil)
+
+	// the description is still truncated when the other columns exceed the line width
+	output, err := runDescribeTypeCmd(mockClient, "k1", "--check", "--verbose")
+	assert.NilError(t, err)
+	outputLines := strings.Split(output, "\n")
+	required := indexOfLine(outputLines, "  Required Properties:")
+	assert.Check(t, util.ContainsAll(outputLines[required+2], name, " Überschrift der  ..."))
+	assert.Check(t, util.ContainsNone(outputLines[required+2], "Nachricht"))
+
+	recorder.Validate()
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, truncate("short", 10), "short")
+	assert.Equal(t, truncate("a longer text", 10), "a long ...")
+	// multi-byte characters are not cut in half
+	assert.Equal(t, truncate("Größenänderung", 10), "Größen ...")
+	assert.Equal(t, truncate("äöü", 3), "äöü")
+}
+
+func TestDescribeTypeCheckAllDefaults(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "period", "integer", "The time interval between two events", true)
+	setKameletPropertyDefault(kamelet, "period", "1000")
+	recorder.Get(kamelet, nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1", "--check")
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsAll(output, "Check:", "All required properties have defaults"))
+
+	_, err = runDescribeTypeCmd(mockClient, "k1", "--check", "-o", "yaml")
+	assert.Error(t, err, "--check can not be combined with --example, --schema, --property, --conditions-only or --output")
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeShowSource(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	kamelet.Spec.Flow = &camelv1.Flow{RawMessage: camelv1.RawMessage(`{"from":{"uri":"timer:tick","steps":[{"to":"kamelet:sink"}]}}`)}
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+	recorder.Get(createKamelet("k2"), nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsNone(output, "Source:", "timer:tick"))
+
+	output, err = runDescribeTypeCmd(mockClient, "k1", "--show-source")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Source:", "  ---\n", "  from:\n", "    steps:\n", "    - to: kamelet:sink\n",
+		"    uri: timer:tick\n", "  ...\n"))
+
+	output, err = runDescribeTypeCmd(mockClient, "k2", "--show-source")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Source:", "Kamelet defines no flow"))
+
+	_, err = runDescribeTypeCmd(mockClient, "k1", "--show-source", "-o", "yaml")
+	assert.Error(t, err, "--show-source can not be combined with --example, --schema, --property or --output")
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeDependenciesOutput(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	kamelet.Spec.Dependencies = []string{"camel:timer", "mvn:org.example:custom-component:1.0.0"}
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+	recorder.Get(createKamelet("k2"), nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1")
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsNone(output, "Dependencies:", "camel:timer"))
+
+	output, err = runDescribeTypeCmd(mockClient, "k1", "--verbose")
+	assert.NilError(t, err)
+	outputLines := strings.Split(output, "\n")
+	start := indexOfLine(outputLines, "Dependencies:")
+	assert.Assert(t, start >= 0)
+	assert.Check(t, util.ContainsAll(outputLines[start+1], "camel:timer"))
+	assert.Check(t, util.ContainsAll(outputLines[start+2], "mvn:org.example:custom-component:1.0.0"))
+
+	output, err = runDescribeTypeCmd(mockClient, "k2", "--verbose")
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsNone(output, "Dependencies:"))
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeJSONProperties(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "period", "integer", "The time interval between two events", false)
+	addKameletProperty(kamelet, "message", "string", "The message to generate", true)
+	setKameletPropertyDefault(kamelet, "period", "1000")
+	recorder.Get(kamelet, nil)
+	recorder.Get(createKamelet("k2"), nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1", "-o", "json-properties", "--sort-by", "required")
+	assert.NilError(t, err)
+	assert.Equal(t, output, `[
+    {
+        "name": "message",
+        "type": "string",
+        "required": true,
+        "default": null,
+        "description": "The message to generate"
+    },
+    {
+        "name": "period",
+        "type": "integer",
+        "required": false,
+        "default": 1000,
+        "description": "The time interval between two events"
+    }
+]
+`)
+
+	output, err = runDescribeTypeCmd(mockClient, "k2", "-o", "json-properties")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "[]\n")
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeProperty(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "message", "string", "The message to generate", true)
+	addKameletProperty(kamelet, "unit", "string", "The unit of the period", false)
+	setKameletPropertyDefault(kamelet, "unit", `"ms"`)
+	unit := kamelet.Spec.Definition.Properties["unit"]
+	unit.Format = "duration-unit"
+	unit.Enum = []*camelkapis.JSON{{RawMessage: []byte(`"ms"`)}, {RawMessage: []byte(` "s" `)}}
+	kamelet.Spec.Definition.Properties["unit"] = unit
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1", "--property", "unit")
+	assert.NilError(t, err)
+	outputLines := strings.Split(output, "\n")
+	assert.Check(t, util.ContainsAll(outputLines[0], "Name:", "unit"))
+	assert.Check(t, util.ContainsAll(outputLines[1], "Type:", "string"))
+	assert.Check(t, util.ContainsAll(outputLines[2], "Format:", "duration-unit"))
+	assert.Check(t, util.ContainsAll(outputLines[3], "Required:", "false"))
+	assert.Check(t, util.ContainsAll(outputLines[4], "Default:", `"ms"`))
+	assert.Check(t, util.ContainsAll(outputLines[5], "Enum:", "ms, s"))
+	assert.Check(t, util.ContainsAll(outputLines[6], "Description:", "The unit of the period"))
+	assert.Check(t, util.ContainsNone(output, "message", "Phase:"))
+
+	_, err = runDescribeTypeCmd(mockClient, "k1", "--property", "period")
+	assert.Error(t, err, "unknown property 'period' for Kamelet k1, valid properties are: message, unit")
+
+	_, err = runDescribeTypeCmd(mockClient, "k1", "--property", "unit", "-o", "yaml")
+	assert.Error(t, err, "--property can not be combined with --example, --schema, --watch or --output")
+
+	recorder.Validate()
+}
+
+func TestDescribeTypePropertiesEnum(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "level", "string", "The log level", false)
+	addKameletProperty(kamelet, "message", "string", "The message to generate", true)
+	level := kamelet.Spec.Definition.Properties["level"]
+	level.Enum = []*camelkapis.JSON{{RawMessage: []byte(`"INFO"`)}, {RawMessage: []byte(`"WARN"`)}, {RawMessage: []byte(`"ERROR"`)}}
+	kamelet.Spec.Definition.Properties["level"] = level
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1", "--verbose")
+	assert.NilError(t, err)
+	outputLines := strings.Split(output, "\n")
+	required := indexOfLine(outputLines, "  Required Properties:")
+	optional := indexOfLine(outputLines, "  Optional Properties:")
+	assert.Check(t, util.ContainsAll(outputLines[required+2], "message", "The message to generate"))
+	assert.Check(t, util.ContainsNone(outputLines[required+2], "one of"))
+	assert.Check(t, util.ContainsAll(outputLines[optional+2], "level", "The log level [one of: INFO, WARN, ERROR]"))
+
+	output, err = runDescribeTypeCmd(mockClient, "k1", "-o", "json-properties")
+	assert.NilError(t, err)
+	assert.Equal(t, output, `[
+    {
+        "name": "level",
+        "type": "string",
+        "required": false,
+        "default": null,
+        "enum": [
+            "INFO",
+            "WARN",
+            "ERROR"
+        ],
+        "description": "The log level"
+    },
+    {
+        "name": "message",
+        "type": "string",
+        "required": true,
+        "default": null,
+        "description": "The message to generate"
+    }
+]
+`)
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeSchema(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "message", "string", "The message to generate", true)
+	addKameletProperty(kamelet, "headers", "object", "The headers to set", false)
+	kamelet.Spec.Definition.Properties["headers"] = camelkapis.JSONSchemaProps{
+		Type:        "object",
+		Description: "The headers to set",
+		Properties: map[string]camelkapis.JSONSchemaProps{
+			"source": {Type: "string"},
+		},
 	}
 	recorder.Get(kamelet, nil)
 
-	_, err := runDescribeTypeCmd(mockClient, "k1")
-	assert.Error(t, err, "Kamelet k1 is not an event source")
+	output, err := runDescribeTypeCmd(mockClient, "k1", "--schema")
+	assert.NilError(t, err)
+	assert.Equal(t, output, `{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "description": "Sample Kamelet source",
+    "type": "object",
+    "title": "Kamelet k1",
+    "required": [
+        "message"
+    ],
+    "properties": {
+        "headers": {
+            "description": "The headers to set",
+            "type": "object",
+            "properties": {
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "message": {
+            "description": "The message to generate",
+            "type": "string"
+        }
+    }
+}
+`)
+
+	_, err = runDescribeTypeCmd(mockClient, "k1", "--schema", "-o", "json")
+	assert.Error(t, err, "--schema can not be combined with --example, --watch or --output")
+
 	recorder.Validate()
 }
 
-func TestDescribeTypeOutput(t *testing.T) {
+func TestDescribeTypeDeprecated(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	deprecated := createKamelet("k1")
+	deprecated.Annotations = map[string]string{kameletSupportLevelAnnotation: "Deprecated", kameletReplacedByAnnotation: "k2"}
+	annotated := createKamelet("k3")
+	annotated.Annotations = map[string]string{kameletDeprecatedAnnotation: "true"}
+	recorder.Get(deprecated, nil)
+	recorder.Get(deprecated, nil)
+	recorder.Get(annotated, nil)
+	recorder.Get(createKamelet("k2"), nil)
+
+	p := &KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewKameletClient: func() (KameletClient, error) {
+			return mockClient, nil
+		},
+		NewKameletBindingClient: mockBindingClient(mockClient),
+	}
+	describe := func(args ...string) (string, string) {
+		cmd := NewDescribeTypeCommand(p)
+		stdout := &strings.Builder{}
+		stderr := &strings.Builder{}
+		cmd.SetOut(stdout)
+		cmd.SetErr(stderr)
+		cmd.SetArgs(args)
+		assert.NilError(t, cmd.Execute())
+		return stdout.String(), stderr.String()
+	}
+
+	stdout, stderr := describe("k1")
+	assert.Equal(t, stderr, "Warning: Kamelet k1 is deprecated and should not be used for new bindings, use k2 instead.\n")
+	outputLines := strings.Split(stdout, "\n")
+	assert.Check(t, util.ContainsAll(outputLines[indexOfLine(outputLines, "Deprecated:")], "Deprecated:", "true"))
+	assert.Check(t, util.ContainsAll(outputLines[indexOfLine(outputLines, "Replaced By:")], "Replaced By:", "k2"))
+
+	stdout, stderr = describe("k1", "-o", "yaml")
+	assert.Assert(t, util.ContainsAll(stderr, "Kamelet k1 is deprecated"))
+	assert.Assert(t, util.ContainsNone(stdout, "Warning"))
+
+	stdout, stderr = describe("k3")
+	assert.Equal(t, stderr, "Warning: Kamelet k3 is deprecated and should not be used for new bindings.\n")
+	assert.Assert(t, util.ContainsAll(stdout, "Deprecated:"))
+	assert.Assert(t, util.ContainsNone(stdout, "Replaced By:"))
+
+	stdout, stderr = describe("k2")
+	assert.Equal(t, stderr, "")
+	assert.Assert(t, util.ContainsNone(stdout, "Deprecated:"))
+
+	recorder.Validate()
+}
+
+func TestDescribeTypePresentationOutput(t *testing.T) {
 	mockClient := client.NewMockKameletClient(t)
 	recorder := mockClient.Recorder()
 
 	kamelet := createKamelet("k1")
+	kamelet.Annotations = map[string]string{
+		camelkapis.AnnotationIcon:     "data:image/svg+xml;base64," + strings.Repeat("PHN2Zz", 50),
+		kameletGroupAnnotation:        "Timer",
+		kameletSupportLevelAnnotation: "Stable",
+		"example.com/custom":          "value",
+	}
+	recorder.Get(kamelet, nil)
 	recorder.Get(kamelet, nil)
 
 	output, err := runDescribeTypeCmd(mockClient, "k1")
 	assert.NilError(t, err)
+	assert.Check(t, util.ContainsNone(output, "Presentation:", "Support Level:"))
 
+	output, err = runDescribeTypeCmd(mockClient, "k1", "--verbose")
+	assert.NilError(t, err)
 	outputLines := strings.Split(output, "\n")
+	start := indexOfLine(outputLines, "Presentation:")
+	assert.Assert(t, start >= 0)
+	assert.Check(t, util.ContainsAll(outputLines[start+1], "Icon:", "data:image/svg+xml;base64,", " ..."))
+	assert.Check(t, util.ContainsAll(outputLines[start+2], "Group:", "Timer"))
+	assert.Check(t, util.ContainsAll(outputLines[start+3], "Support Level:", "Stable"))
 
-	assert.Check(t, util.ContainsAll(outputLines[0], "Name:", "k1"))
-	assert.Check(t, util.ContainsAll(outputLines[1], "Namespace:", "default"))
-	assert.Check(t, util.ContainsAll(outputLines[2], "Labels:", "camel.apache.org/kamelet.type=source"))
-	assert.Check(t, util.ContainsAll(outputLines[3], "Age:", "0s"))
-	assert.Check(t, util.ContainsAll(outputLines[4], "Description:", "Kamelet k1 - Sample Kamelet source"))
-	assert.Check(t, util.ContainsAll(outputLines[5], "Phase:", "Ready"))
+	annotations := indexOfLine(outputLines, "Annotations:")
+	assert.Assert(t, annotations >= 0)
+	assert.Check(t, util.ContainsAll(outputLines[annotations], "example.com/custom=value"))
+	assert.Check(t, util.ContainsNone(output, "camel.apache.org/kamelet.icon", "camel.apache.org/kamelet.group"))
+
+	recorder.Validate()
+}
+
+func TestDescribeTypePropertiesOutput(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "message", "string", "The message to generate", true)
+	addKameletProperty(kamelet, "period", "integer", "The time interval between two events", false)
+	addKameletProperty(kamelet, "count", "integer", "The number of events", false)
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1")
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsAll(output, "Properties:", "count, message*, period"))
+
+	output, err = runDescribeTypeCmd(mockClient, "k1", "--verbose")
+	assert.NilError(t, err)
+	outputLines := strings.Split(output, "\n")
+	start := indexOfLine(outputLines, "Properties:")
+	assert.Assert(t, start >= 0)
+	assert.Check(t, util.ContainsAll(outputLines[start+1], "Required Properties:"))
+	assert.Check(t, util.ContainsAll(outputLines[start+2], "NAME", "TYPE", "DEFAULT", "DESCRIPTION"))
+	assert.Check(t, util.ContainsAll(outputLines[start+3], "message", "string", "The message to generate"))
+	assert.Check(t, util.ContainsAll(outputLines[start+4], "Optional Properties:"))
+	assert.Check(t, util.ContainsAll(outputLines[start+5], "NAME", "TYPE", "DEFAULT", "DESCRIPTION"))
+	assert.Check(t, util.ContainsAll(outputLines[start+6], "count", "integer", "The number of events"))
+	assert.Check(t, util.ContainsAll(outputLines[start+7], "period", "integer", "The time interval between two events"))
+
+	recorder.Validate()
+}
+
+func TestDescribeTypePropertiesDefaults(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "message", "string", "The message to generate", true)
+	addKameletProperty(kamelet, "period", "integer", "The time interval between two events", false)
+	addKameletProperty(kamelet, "headers", "object", "The headers to set", false)
+	setKameletPropertyDefault(kamelet, "period", "1000")
+	setKameletPropertyDefault(kamelet, "headers", `{ "source": "timer", "type": "dev.knative.timer.event", "extra": "some long value" }`)
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1")
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsAll(output, "Properties:", `headers={"source":"timer","t ..., message*, period=1000`))
+
+	output, err = runDescribeTypeCmd(mockClient, "k1", "--verbose")
+	assert.NilError(t, err)
+	outputLines := strings.Split(output, "\n")
+	start := indexOfLine(outputLines, "Properties:")
+	assert.Check(t, util.ContainsAll(outputLines[start+3], "message", "string", "The message to generate"))
+	assert.Check(t, util.ContainsAll(outputLines[start+6], "headers", "object", `{"source":"timer","t ...`, "The headers to set"))
+	assert.Check(t, util.ContainsAll(outputLines[start+7], "period", "integer", "1000", "The time interval between two events"))
+
+	descriptionColumn := strings.Index(outputLines[start+2], "DESCRIPTION")
+	for _, i := range []int{3, 6, 7} {
+		assert.Check(t, outputLines[start+i][descriptionColumn-1] == ' ' && outputLines[start+i][descriptionColumn] == 'T')
+	}
+
+	recorder.Validate()
+}
+
+func TestDescribeTypePropertiesStableOrder(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	for _, name := range []string{"zeta", "alpha", "mu", "beta", "omega", "gamma"} {
+		addKameletProperty(kamelet, name, "string", "Property "+name, name == "mu")
+	}
+
+	var previous string
+	for i := 0; i < 5; i++ {
+		recorder.Get(kamelet, nil)
+		output, err := runDescribeTypeCmd(mockClient, "k1", "--verbose")
+		assert.NilError(t, err)
+		if i > 0 {
+			assert.Equal(t, output, previous)
+		}
+		previous = output
+	}
+
+	outputLines := strings.Split(previous, "\n")
+	start := indexOfLine(outputLines, "Properties:")
+	assert.Check(t, strings.HasPrefix(strings.TrimSpace(outputLines[start+3]), "mu"))
+	for i, name := range []string{"alpha", "beta", "gamma", "omega", "zeta"} {
+		assert.Check(t, strings.HasPrefix(strings.TrimSpace(outputLines[start+6+i]), name))
+	}
+
+	recorder.Validate()
+}
+
+func TestDescribeTypePropertiesSortByRequired(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "b", "string", "Property b", false)
+	addKameletProperty(kamelet, "d", "string", "Property d", true)
+	addKameletProperty(kamelet, "a", "string", "Property a", false)
+	addKameletProperty(kamelet, "c", "string", "Property c", true)
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1", "--sort-by", "required")
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsAll(output, "Properties:", "c*, d*, a, b"))
+
+	output, err = runDescribeTypeCmd(mockClient, "k1", "--sort-by", "name", "--verbose")
+	assert.NilError(t, err)
+	outputLines := strings.Split(output, "\n")
+	required := indexOfLine(outputLines, "  Required Properties:")
+	optional := indexOfLine(outputLines, "  Optional Properties:")
+	assert.Assert(t, required >= 0 && optional > required)
+	assert.Check(t, strings.HasPrefix(strings.TrimSpace(outputLines[required+2]), "c"))
+	assert.Check(t, strings.HasPrefix(strings.TrimSpace(outputLines[required+3]), "d"))
+	assert.Check(t, strings.HasPrefix(strings.TrimSpace(outputLines[optional+2]), "a"))
+	assert.Check(t, strings.HasPrefix(strings.TrimSpace(outputLines[optional+3]), "b"))
+
+	_, err = runDescribeTypeCmd(mockClient, "k1", "--sort-by", "type")
+	assert.Error(t, err, "invalid sort order 'type', must be one of: name, required")
+
+	recorder.Validate()
+}
+
+func TestDescribeTypePropertiesOnlyOptional(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "period", "integer", "The time interval between two events", false)
+	recorder.Get(kamelet, nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1", "--verbose")
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsNone(output, "Required Properties:"))
+	outputLines := strings.Split(output, "\n")
+	start := indexOfLine(outputLines, "Properties:")
+	assert.Assert(t, start >= 0)
+	assert.Check(t, util.ContainsAll(outputLines[start+1], "Optional Properties:"))
+	assert.Check(t, util.ContainsAll(outputLines[start+3], "period", "integer"))
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeWatch(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	kamelet.Status.Phase = camelkapis.KameletPhaseNone
+	kamelet.Status.Conditions[0].Status = corev1.ConditionFalse
+	kamelet.Status.Conditions[0].Reason = "Initializing"
+	recorder.Get(kamelet, nil)
+
+	watcher := watch.NewFakeWithChanSize(2, false)
+	watcher.Modify(kamelet)
+	watcher.Modify(createKamelet("k1"))
+	recorder.Watch(watcher, nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1", "--watch")
+	assert.NilError(t, err)
+	assert.Equal(t, strings.Count(output, "Conditions:"), 3)
+	assert.Check(t, util.ContainsAll(output, "!!", "Initializing", "++", "Ready"))
+	assert.Assert(t, watcher.IsStopped())
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeConditionsOnly(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "message", "string", "The message to send", true)
+	recorder.Get(kamelet, nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1", "--conditions-only")
+	assert.NilError(t, err)
+	assert.Assert(t, strings.HasPrefix(output, "Conditions:"))
+	assert.Check(t, util.ContainsAll(output, "++", "Ready"))
+	assert.Check(t, util.ContainsNone(output, "Name:", "Properties:", "message"))
+
+	notReady := createKamelet("k2")
+	notReady.Status.Conditions[0].Status = corev1.ConditionFalse
+	notReady.Status.Conditions[0].Reason = "Initializing"
+	recorder.Get(notReady, nil)
+	watcher := watch.NewFakeWithChanSize(1, false)
+	watcher.Modify(createKamelet("k2"))
+	recorder.Watch(watcher, nil)
+
+	output, err = runDescribeTypeCmd(mockClient, "k2", "--conditions-only", "--watch")
+	assert.NilError(t, err)
+	assert.Equal(t, strings.Count(output, "Conditions:"), 2)
+	assert.Check(t, util.ContainsNone(output, "Name:"))
+
+	_, err = runDescribeTypeCmd(mockClient, "k1", "--conditions-only", "-o", "yaml")
+	assert.Error(t, err, "--conditions-only can not be combined with --example, --schema, --property, --show-source or --output")
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeWatchAlreadyReady(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1", "-w")
+	assert.NilError(t, err)
+	assert.Equal(t, strings.Count(output, "Conditions:"), 1)
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeWatchWaitWindow(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	// a Kamelet already ready has to stay ready for the window as well
+	recorder.Get(createKamelet("k1"), nil)
+	watcher := watch.NewFake()
+	recorder.Watch(watcher, nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1", "-w", "--wait-window", "20ms")
+	assert.NilError(t, err)
+	assert.Equal(t, strings.Count(output, "Conditions:"), 1)
+	assert.Assert(t, watcher.IsStopped())
+
+	// flapping back to not ready restarts the window, which does not elapse before the timeout
+	notReady := createKamelet("k1")
+	notReady.Status.Conditions[0].Status = corev1.ConditionFalse
+	notReady.Status.Conditions[0].Reason = "Initializing"
+	recorder.Get(notReady, nil)
+	flapping := watch.NewFakeWithChanSize(2, false)
+	flapping.Modify(createKamelet("k1"))
+	flapping.Modify(notReady)
+	recorder.Watch(flapping, nil)
+
+	_, err = runDescribeTypeCmd(mockClient, "k1", "-w", "--wait-window", "50ms", "--timeout", "100ms")
+	assert.Error(t, err, "timeout after 100ms waiting for Kamelet k1 to become ready: Initializing")
+
+	// being ready without staying ready for the window times out
+	recorder.Get(notReady, nil)
+	becomingReady := watch.NewFake()
+	recorder.Watch(becomingReady, nil)
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		becomingReady.Modify(createKamelet("k1"))
+	}()
+
+	_, err = runDescribeTypeCmd(mockClient, "k1", "-w", "--wait-window", "500ms", "--timeout", "600ms")
+	assert.Error(t, err, "timeout after 600ms waiting for Kamelet k1 to stay ready for 500ms")
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeErrorCaseWaitWindow(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+
+	_, err := runDescribeTypeCmd(mockClient, "k1", "--wait-window", "10s")
+	assert.Error(t, err, "--wait-window requires --watch")
+
+	_, err = runDescribeTypeCmd(mockClient, "k1", "-w", "--wait-window", "-1s")
+	assert.Error(t, err, "invalid --wait-window '-1s', must not be negative")
+
+	_, err = runDescribeTypeCmd(mockClient, "k1", "-w", "--wait-window", "1m")
+	assert.Error(t, err, "--wait-window 1m0s must be shorter than --timeout 1m0s")
+}
+
+func TestDescribeTypeWatchTimeout(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	kamelet.Status.Conditions[0].Status = corev1.ConditionFalse
+	kamelet.Status.Conditions[0].Reason = "Initializing"
+	recorder.Get(kamelet, nil)
+
+	notReady := kamelet.DeepCopy()
+	notReady.Status.Conditions[0].Reason = "MissingDependency"
+	notReady.Status.Conditions[0].Message = "camel-timer not available"
+	watcher := watch.NewFakeWithChanSize(1, false)
+	watcher.Modify(notReady)
+	recorder.Watch(watcher, nil)
+
+	_, err := runDescribeTypeCmd(mockClient, "k1", "--watch", "--timeout", "100ms")
+	assert.Error(t, err, "timeout after 100ms waiting for Kamelet k1 to become ready: MissingDependency : camel-timer not available")
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeWatchWithOutput(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+
+	_, err := runDescribeTypeCmd(mockClient, "k1", "--watch", "-o", "yaml")
+	assert.Error(t, err, "--watch can not be combined with --output")
+	mockClient.Recorder().Validate()
+}
+
+func TestDescribeTypeGoTemplate(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1", "-o", "go-template={{.metadata.name}}: {{.status.phase}}")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "k1: Ready")
+
+	output, err = runDescribeTypeCmd(mockClient, "k1", "-o", "go-template", "--template", "{{.spec.definition.title}}")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "Kamelet k1")
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeJSONPath(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "period", "integer", "The time interval between two events", false)
+	setKameletPropertyDefault(kamelet, "period", "1000")
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1", "-o", "jsonpath={.status.phase}")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "Ready")
+
+	output, err = runDescribeTypeCmd(mockClient, "k1", "-o", "jsonpath", "--template", "{.spec.definition.properties.period.default}")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "1000")
 
-	assert.Check(t, util.ContainsAll(outputLines[7], "Conditions:"))
-	assert.Check(t, util.ContainsAll(outputLines[8], "OK", "TYPE", "AGE", "REASON"))
-	assert.Check(t, util.ContainsAll(outputLines[9], "++", "Ready", "", ""))
+	// the url format is still handled by the plugin
+	output, err = runDescribeTypeCmd(mockClient, "k1", "-o", "url")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "/apis/camel.apache.org/v1alpha1/namespaces/default/kamelets/k1\n")
 
 	recorder.Validate()
 }
@@ -118,11 +1281,25 @@ func TestDescribeTypeURL(t *testing.T) {
 	recorder.Validate()
 }
 
-func runDescribeTypeCmd(c *client.MockKameletClient, options ...string) (string, error) {
+func TestDescribeTypeURLWithoutSelfLink(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKameletInNamespace("k1", "camel-k")
+	kamelet.SelfLink = ""
+	recorder.Get(kamelet, nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1", "-o", "url")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "/apis/camel.apache.org/v1alpha1/namespaces/camel-k/kamelets/k1\n")
+	recorder.Validate()
+}
+
+func runDescribeTypeCmd(c KameletClient, options ...string) (string, error) {
 	p := KameletPluginParams{
 		KnParams: &commands.KnParams{},
 		Context:  context.TODO(),
-		NewKameletClient: func() (camelkv1alpha1.CamelV1alpha1Interface, error) {
+		NewKameletClient: func() (KameletClient, error) {
 			return c, nil
 		},
 	}
@@ -136,3 +1313,81 @@ func runDescribeTypeCmd(c *client.MockKameletClient, options ...string) (string,
 
 	return output.String(), err
 }
+
+func indexOfLine(lines []string, prefix string) int {
+	for i, line := range lines {
+		if strings.HasPrefix(line, prefix) {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestDescribeTypeRaw(t *testing.T) {
+	// the document is printed as served, including its formatting, managed fields and status
+	served := `{"kind":"Kamelet","apiVersion":"camel.apache.org/v1alpha1","metadata":{"name":"k1","namespace":"current",` +
+		`"managedFields":[{"manager":"kubectl","operation":"Update"}]},"spec":{},"status":{"phase":"Ready"}}` + "\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/apis/camel.apache.org/v1alpha1/namespaces/current/kamelets/k1" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404,` +
+				`"message":"kamelets.camel.apache.org \"k2\" not found","details":{"name":"k2","group":"camel.apache.org","kind":"kamelets"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(served))
+	}))
+	defer server.Close()
+
+	p := &KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewKameletClient: func() (KameletClient, error) {
+			client, err := camelkv1alpha1.NewForConfig(&rest.Config{Host: server.URL})
+			if err != nil {
+				return nil, err
+			}
+			return newKameletClientFor(client), nil
+		},
+	}
+
+	output, err := runDescribeTypeCmdWithParams(p, "k1", "--raw")
+	assert.NilError(t, err)
+	assert.Equal(t, output, served)
+
+	_, err = runDescribeTypeCmdWithParams(p, "k2", "--raw")
+	assert.Assert(t, apierrors.IsNotFound(err), err)
+	var notFoundErr *ErrKameletNotFound
+	assert.Assert(t, errors.As(err, &notFoundErr))
+}
+
+func TestDescribeTypeRawErrorCaseNotServed(t *testing.T) {
+	fakeClient := clienttesting.NewFakeKameletClient(t, createKameletInNamespace("k1", "current"))
+
+	_, err := runDescribeTypeCmd(fakeClient, "k1", "--raw")
+	assert.Error(t, err, "the Kamelet client is not backed by an API server, Kamelets can not be read as served")
+}
+
+func TestDescribeTypeErrorCaseRaw(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+
+	_, err := runDescribeTypeCmd(mockClient, "k1", "k2", "--raw")
+	assert.Error(t, err, "--raw requires a single Kamelet name, Kamelets read with --filename are no server objects")
+	_, err = runDescribeTypeCmd(mockClient, "--filename", "testdata/timer-source.kamelet.yaml", "--raw")
+	assert.Error(t, err, "--raw requires a single Kamelet name, Kamelets read with --filename are no server objects")
+	for _, flag := range []string{"-o=json", "--watch", "--example", "--schema", "--property=message", "--show-source",
+		"--conditions-only", "--check", "--markdown"} {
+		_, err = runDescribeTypeCmd(mockClient, "k1", "--raw", flag)
+		assert.Error(t, err, "--raw can not be combined with --output, --watch, --example, --schema, --property, "+
+			"--show-source, --conditions-only, --check or --markdown", flag)
+	}
+
+	mockClient.Recorder().Validate()
+}
+
+func runDescribeTypeCmdWithParams(p *KameletPluginParams, options ...string) (string, error) {
+	describeCmd, _, output := commands.CreateSourcesTestKnCommand(NewDescribeTypeCommand(p), p.KnParams)
+	describeCmd.SetArgs(append([]string{"describe-type"}, options...))
+	err := describeCmd.Execute()
+	return output.String(), err
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"github.com/spf13/pflag"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	dryRunNone   = "none"
+	dryRunClient = "client"
+	dryRunServer = "server"
+)
+
+// addDryRunFlag adds the flag selecting the dry run strategy of mutating commands
+func addDryRunFlag(flags *pflag.FlagSet, dryRun *string) {
+	flags.StringVar(dryRun, "dry-run", dryRunNone, "Must be one of: none, client or server. With 'client' the "+
+		"outcome is only printed without sending the change to the API server, created or updated Kamelet bindings "+
+		"are printed as YAML. With 'server' the change is validated by the API server without persisting it.")
+	flags.Lookup("dry-run").NoOptDefVal = dryRunClient
+}
+
+// validateDryRun checks that given dry run strategy is supported
+func validateDryRun(dryRun string) error {
+	switch dryRun {
+	case dryRunNone, dryRunClient, dryRunServer:
+		return nil
+	default:
+		return fmt.Errorf("invalid dry run value '%s', must be one of: none, client, server", dryRun)
+	}
+}
+
+// dryRunOptions returns the dry run directive passed to the API server for given dry run strategy
+func dryRunOptions(dryRun string) []string {
+	if dryRun == dryRunServer {
+		return []string{v1.DryRunAll}
+	}
+	return nil
+}
+
+// dryRunSuffix returns the suffix appended to status messages for given dry run strategy
+func dryRunSuffix(dryRun string) string {
+	switch dryRun {
+	case dryRunClient:
+		return " (dry run)"
+	case dryRunServer:
+		return " (server dry run)"
+	default:
+		return ""
+	}
+}
+
+// writeBindingObjects prints given Kamelet bindings serialized for the API of given binding client in given format,
+// either as YAML documents or as JSON
+func writeBindingObjects(out io.Writer, format string, client bindingClient, bindings []*v1alpha1.KameletBinding) error {
+	objects := make([]runtime.Object, 0, len(bindings))
+	for _, binding := range bindings {
+		object, err := client.serialize(binding)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, object)
+	}
+	return writeObjects(out, format, objects...)
+}
+
+// writeObjects prints given objects in given format, either as YAML documents or as JSON. Several objects are
+// printed as JSON list.
+func writeObjects(out io.Writer, format string, objects ...runtime.Object) error {
+	if format == "json" {
+		var object interface{} = objects[0]
+		if len(objects) > 1 {
+			object = &v1.List{
+				TypeMeta: v1.TypeMeta{APIVersion: "v1", Kind: "List"},
+				Items:    rawExtensions(objects),
+			}
+		}
+		data, err := json.MarshalIndent(object, "", "    ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(data))
+		return err
+	}
+
+	for i, object := range objects {
+		data, err := yaml.Marshal(object)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprintln(out, "---")
+		}
+		if _, err := out.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rawExtensions wraps given objects for embedding them into a list
+func rawExtensions(objects []runtime.Object) []runtime.RawExtension {
+	items := make([]runtime.RawExtension, 0, len(objects))
+	for _, object := range objects {
+		items = append(items, runtime.RawExtension{Object: object})
+	}
+	return items
+}
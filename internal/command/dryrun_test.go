@@ -0,0 +1,93 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"strings"
+	"testing"
+
+	camelkapis "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"github.com/spf13/pflag"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestDryRunFlag(t *testing.T) {
+	var dryRun string
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	addDryRunFlag(flags, &dryRun)
+
+	assert.NilError(t, flags.Parse([]string{}))
+	assert.Equal(t, dryRun, dryRunNone)
+
+	// a bare flag means a client side dry run like with kubectl
+	assert.NilError(t, flags.Parse([]string{"--dry-run"}))
+	assert.Equal(t, dryRun, dryRunClient)
+
+	assert.NilError(t, flags.Parse([]string{"--dry-run=server"}))
+	assert.Equal(t, dryRun, dryRunServer)
+}
+
+func TestValidateDryRun(t *testing.T) {
+	for _, dryRun := range []string{dryRunNone, dryRunClient, dryRunServer} {
+		assert.NilError(t, validateDryRun(dryRun))
+	}
+	assert.Error(t, validateDryRun("all"), "invalid dry run value 'all', must be one of: none, client, server")
+}
+
+func TestDryRunOptions(t *testing.T) {
+	assert.Assert(t, dryRunOptions(dryRunNone) == nil)
+	assert.Assert(t, dryRunOptions(dryRunClient) == nil)
+	assert.DeepEqual(t, dryRunOptions(dryRunServer), []string{v1.DryRunAll})
+
+	assert.Equal(t, dryRunSuffix(dryRunNone), "")
+	assert.Equal(t, dryRunSuffix(dryRunClient), " (dry run)")
+	assert.Equal(t, dryRunSuffix(dryRunServer), " (server dry run)")
+}
+
+func TestWriteBindingObjects(t *testing.T) {
+	b1 := createKameletBindingFor("k1", "k1-binding")
+	b2 := createKameletBindingFor("k2", "k2-binding")
+	b2.TypeMeta = v1.TypeMeta{}
+
+	out := &strings.Builder{}
+	assert.NilError(t, writeBindingObjects(out, "yaml", &kameletBindingClient{}, []*camelkapis.KameletBinding{b1, b2}))
+
+	documents := strings.Split(out.String(), "---\n")
+	assert.Equal(t, len(documents), 2)
+	assert.Assert(t, strings.HasPrefix(documents[0], "apiVersion: camel.apache.org/v1alpha1\nkind: KameletBinding\n"))
+	assert.Assert(t, strings.HasPrefix(documents[1], "apiVersion: camel.apache.org/v1alpha1\nkind: KameletBinding\n"))
+	assert.Assert(t, strings.Contains(documents[1], "name: k2-binding"))
+	// the given objects are left untouched
+	assert.Equal(t, b2.Kind, "")
+
+	out.Reset()
+	assert.NilError(t, writeBindingObjects(out, "json", &kameletBindingClient{}, []*camelkapis.KameletBinding{b2}))
+	assert.Assert(t, strings.HasPrefix(out.String(), "{\n    \"kind\": \"KameletBinding\",\n    \"apiVersion\": \"camel.apache.org/v1alpha1\""))
+
+	out.Reset()
+	assert.NilError(t, writeBindingObjects(out, "json", &kameletBindingClient{}, []*camelkapis.KameletBinding{b1, b2}))
+	assert.Assert(t, strings.HasPrefix(out.String(), "{\n    \"kind\": \"List\",\n    \"apiVersion\": \"v1\""))
+	assert.Assert(t, strings.Contains(out.String(), `"name": "k2-binding"`))
+
+	// the bindings are printed as Pipes for the Pipe API
+	out.Reset()
+	assert.NilError(t, writeBindingObjects(out, "yaml", &pipeClient{}, []*camelkapis.KameletBinding{b1}))
+	assert.Assert(t, strings.HasPrefix(out.String(), "apiVersion: camel.apache.org/v1\nkind: Pipe\n"), out.String())
+	assert.Assert(t, strings.Contains(out.String(), "name: k1-binding"))
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrKameletNotFound is returned when a Kamelet does not exist in the namespace it is looked up in.
+// It wraps the error returned by the API server.
+type ErrKameletNotFound struct {
+	Name      string
+	Namespace string
+	Err       error
+}
+
+func (e *ErrKameletNotFound) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ErrKameletNotFound) Unwrap() error {
+	return e.Err
+}
+
+// ErrNotASource is returned when a Kamelet is not of the type required by a command, e.g. a Kamelet to bind
+// that is not a source. Type is empty for Kamelets without type label.
+type ErrNotASource struct {
+	Name         string
+	Type         string
+	ExpectedType string
+}
+
+func (e *ErrNotASource) Error() string {
+	if e.Type == "" {
+		return fmt.Sprintf("Kamelet %s has no type, not %s %s", e.Name, article(e.ExpectedType), e.ExpectedType)
+	}
+	return fmt.Sprintf("Kamelet %s is %s %s, not %s %s; use --type %s", e.Name,
+		article(e.Type), e.Type, article(e.ExpectedType), e.ExpectedType, e.Type)
+}
+
+// ErrPropertyUnknown is returned when a property is given that is not defined by the Kamelet.
+// ValidProperties holds the sorted names of the properties the Kamelet defines.
+type ErrPropertyUnknown struct {
+	Kamelet         string
+	Property        string
+	ValidProperties []string
+}
+
+func (e *ErrPropertyUnknown) Error() string {
+	if len(e.ValidProperties) == 0 {
+		return fmt.Sprintf("unknown property '%s', Kamelet %s does not define any properties", e.Property, e.Kamelet)
+	}
+	return fmt.Sprintf("unknown property '%s' for Kamelet %s, valid properties are: %s",
+		e.Property, e.Kamelet, strings.Join(e.ValidProperties, ", "))
+}
+
+// ErrRequiredPropertyMissing is returned when required properties of a Kamelet are neither given nor have a default.
+// Properties holds the sorted names of the missing properties.
+type ErrRequiredPropertyMissing struct {
+	Kamelet    string
+	Properties []string
+}
+
+func (e *ErrRequiredPropertyMissing) Error() string {
+	return fmt.Sprintf("missing required properties for Kamelet %s: %s", e.Kamelet, strings.Join(e.Properties, ", "))
+}
+
+// ErrPropertyConstraintViolation is returned in strict mode when property values violate the constraints declared
+// in the Kamelet definition. Violations holds one message per failed constraint, ordered by property name.
+type ErrPropertyConstraintViolation struct {
+	Kamelet    string
+	Violations []string
+}
+
+func (e *ErrPropertyConstraintViolation) Error() string {
+	return fmt.Sprintf("properties violate the schema of Kamelet %s: %s", e.Kamelet, strings.Join(e.Violations, "; "))
+}
+
+// ErrCamelKNotInstalled is returned when an API request failed as the cluster serves none of the Camel K API
+// versions, i.e. Camel K is not installed. It wraps the error returned by the API server.
+type ErrCamelKNotInstalled struct {
+	Err error
+}
+
+func (e *ErrCamelKNotInstalled) Error() string {
+	return "Camel K (kamelets.camel.apache.org) does not appear to be installed on this cluster, " +
+		"install Camel K or select another cluster with --context"
+}
+
+func (e *ErrCamelKNotInstalled) Unwrap() error {
+	return e.Err
+}
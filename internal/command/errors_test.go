@@ -0,0 +1,265 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestErrKameletNotFound(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	notFound := apierrors.NewNotFound(schema.GroupResource{Group: "camel.apache.org", Resource: "kamelets"}, "k1")
+	recorder.Get(nil, notFound)
+
+	_, err := runBindCmd(mockClient, "k1", "--sink", "broker:default")
+	assert.Error(t, err, notFound.Error())
+	var notFoundErr *ErrKameletNotFound
+	assert.Assert(t, errors.As(err, &notFoundErr))
+	assert.Equal(t, notFoundErr.Name, "k1")
+	assert.Equal(t, notFoundErr.Namespace, "current")
+	assert.Assert(t, apierrors.IsNotFound(err))
+
+	recorder.Validate()
+}
+
+func TestErrCamelKNotInstalled(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	// the API server answers requests of an API group it does not serve with not found
+	notFound := apierrors.NewNotFound(schema.GroupResource{Group: "camel.apache.org", Resource: "kamelets"}, "")
+	recorder.List(nil, notFound)
+	recorder.List(nil, notFound)
+
+	p := &KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewDiscoveryClient: func() (discovery.ServerResourcesInterface, error) {
+			return &fakeDiscovery{}, nil
+		},
+	}
+	_, err := runListTypesCmdWithParams(p, mockClient)
+	assert.Error(t, err, "Camel K (kamelets.camel.apache.org) does not appear to be installed on this cluster, "+
+		"install Camel K or select another cluster with --context")
+	var notInstalledErr *ErrCamelKNotInstalled
+	assert.Assert(t, errors.As(err, &notInstalledErr))
+	assert.Assert(t, errors.Is(err, notFound))
+
+	// the original error is kept when the Camel K API is served
+	p.NewDiscoveryClient = func() (discovery.ServerResourcesInterface, error) {
+		return newKameletBindingDiscovery(), nil
+	}
+	_, err = runListTypesCmdWithParams(p, mockClient)
+	assert.Error(t, err, notFound.Error())
+	assert.Assert(t, !errors.As(err, &notInstalledErr))
+
+	recorder.Validate()
+}
+
+func TestErrCamelKNotInstalledBind(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	notFound := apierrors.NewNotFound(schema.GroupResource{Group: "camel.apache.org", Resource: "kamelets"}, "k1")
+	recorder.Get(nil, notFound)
+
+	p := &KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewKameletClient: func() (KameletClient, error) {
+			return mockClient, nil
+		},
+		NewKameletBindingClient: mockBindingClient(mockClient),
+		NewDiscoveryClient: func() (discovery.ServerResourcesInterface, error) {
+			return &fakeDiscovery{}, nil
+		},
+	}
+	_, err := runBindCmdWithParams(p, "", "k1", "--sink", "broker:default", "--api", bindingAPIKameletBinding)
+	assert.ErrorContains(t, err, "does not appear to be installed on this cluster")
+	var notFoundErr *ErrKameletNotFound
+	assert.Assert(t, !errors.As(err, &notFoundErr))
+
+	recorder.Validate()
+}
+
+func TestErrCamelKNotInstalledUpdate(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.BindingRecorder()
+
+	notFound := apierrors.NewNotFound(schema.GroupResource{Group: "camel.apache.org", Resource: "kameletbindings"}, "b1")
+	recorder.Get("b1", nil, notFound)
+	recorder.Get("b1", nil, notFound)
+
+	p := &KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewDiscoveryClient: func() (discovery.ServerResourcesInterface, error) {
+			return &fakeDiscovery{}, nil
+		},
+	}
+	_, err := runUpdateCmdWithParams(p, mockClient, "b1", "-p", "message=Hi")
+	assert.ErrorContains(t, err, "does not appear to be installed on this cluster")
+	assert.Equal(t, ExitCode(err), CamelKNotInstalledExitCode)
+
+	// --force does not try to create the binding
+	_, err = runUpdateCmdWithParams(p, mockClient, "b1", "--force", "--kamelet", "k1", "--sink", "broker:default")
+	assert.ErrorContains(t, err, "does not appear to be installed on this cluster")
+
+	recorder.Validate()
+}
+
+func TestErrCamelKNotInstalledDelete(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.BindingRecorder()
+
+	notFound := apierrors.NewNotFound(schema.GroupResource{Group: "camel.apache.org", Resource: "kameletbindings"}, "b1")
+	recorder.Delete("b1", notFound)
+	recorder.Watch(nil, notFound)
+	recorder.List(nil, notFound)
+
+	p := &KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewDiscoveryClient: func() (discovery.ServerResourcesInterface, error) {
+			return &fakeDiscovery{}, nil
+		},
+	}
+	_, err := runDeleteCmdWithParams(p, mockClient, "", "b1")
+	assert.Error(t, err, "Camel K (kamelets.camel.apache.org) does not appear to be installed on this cluster, "+
+		"install Camel K or select another cluster with --context")
+
+	_, err = runDeleteCmdWithParams(p, mockClient, "", "b1", "--wait")
+	assert.ErrorContains(t, err, "does not appear to be installed on this cluster")
+
+	_, err = runDeleteCmdWithParams(p, mockClient, "", "-l", "team=a", "--force")
+	assert.ErrorContains(t, err, "does not appear to be installed on this cluster")
+	assert.Equal(t, ExitCode(err), CamelKNotInstalledExitCode)
+
+	recorder.Validate()
+}
+
+func TestCheckCamelKInstalledRefreshesCache(t *testing.T) {
+	discoveryClient := &fakeCachedDiscovery{fakeDiscovery: &fakeDiscovery{}, refreshed: newKameletBindingDiscovery()}
+	p := &KameletPluginParams{
+		NewDiscoveryClient: func() (discovery.ServerResourcesInterface, error) {
+			return discoveryClient, nil
+		},
+	}
+
+	// Camel K got installed after the discovery has been cached
+	notFound := apierrors.NewNotFound(schema.GroupResource{Group: "camel.apache.org", Resource: "kamelets"}, "k1")
+	assert.Equal(t, p.checkCamelKInstalled(notFound), error(notFound))
+	assert.Assert(t, discoveryClient.invalidated)
+
+	// other errors are not checked
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Group: "camel.apache.org", Resource: "kamelets"}, "k1", errors.New("denied"))
+	p.NewDiscoveryClient = func() (discovery.ServerResourcesInterface, error) {
+		return &fakeDiscovery{}, nil
+	}
+	assert.Equal(t, p.checkCamelKInstalled(forbidden), error(forbidden))
+}
+
+// fakeCachedDiscovery is a stale cached discovery serving the refreshed resources once invalidated
+type fakeCachedDiscovery struct {
+	discovery.CachedDiscoveryInterface
+	*fakeDiscovery
+	refreshed   *fakeDiscovery
+	invalidated bool
+}
+
+func (d *fakeCachedDiscovery) ServerResourcesForGroupVersion(groupVersion string) (*v1.APIResourceList, error) {
+	return d.fakeDiscovery.ServerResourcesForGroupVersion(groupVersion)
+}
+
+func (d *fakeCachedDiscovery) Fresh() bool {
+	return d.invalidated
+}
+
+func (d *fakeCachedDiscovery) Invalidate() {
+	d.fakeDiscovery = d.refreshed
+	d.invalidated = true
+}
+
+func TestErrNotASource(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	kamelet.Labels[kameletTypeLabel] = kameletTypeSink
+	recorder.Get(kamelet, nil)
+
+	_, err := runBindCmd(mockClient, "k1", "--sink", "broker:default")
+	assert.Error(t, err, "Kamelet k1 is a sink, not a source; use --type sink")
+	var notASourceErr *ErrNotASource
+	assert.Assert(t, errors.As(err, &notASourceErr))
+	assert.Equal(t, notASourceErr.Type, kameletTypeSink)
+	assert.Equal(t, notASourceErr.ExpectedType, kameletTypeSource)
+
+	assert.Error(t, &ErrNotASource{Name: "k2", ExpectedType: kameletTypeAction}, "Kamelet k2 has no type, not an action")
+
+	recorder.Validate()
+}
+
+func TestErrPropertyUnknown(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "message", "string", "The message to send", false)
+	recorder.Get(kamelet, nil)
+
+	_, err := runBindCmd(mockClient, "k1", "--sink", "broker:default", "-p", "msg=Hello")
+	assert.Error(t, err, "unknown property 'msg' for Kamelet k1, valid properties are: message")
+	var unknownErr *ErrPropertyUnknown
+	assert.Assert(t, errors.As(err, &unknownErr))
+	assert.Equal(t, unknownErr.Property, "msg")
+	assert.DeepEqual(t, unknownErr.ValidProperties, []string{"message"})
+
+	recorder.Validate()
+}
+
+func TestErrRequiredPropertyMissing(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "message", "string", "The message to send", true)
+	addKameletProperty(kamelet, "period", "integer", "Delay between messages", true)
+	recorder.Get(kamelet, nil)
+
+	_, err := runBindCmd(mockClient, "k1", "--sink", "broker:default")
+	assert.Error(t, err, "missing required properties for Kamelet k1: message, period")
+	var missingErr *ErrRequiredPropertyMissing
+	assert.Assert(t, errors.As(err, &missingErr))
+	assert.Equal(t, missingErr.Kamelet, "k1")
+	assert.DeepEqual(t, missingErr.Properties, []string{"message", "period"})
+
+	recorder.Validate()
+}
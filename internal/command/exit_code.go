@@ -0,0 +1,60 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// ErrorExitCode is the exit code of failed commands without a more specific exit code, e.g. for connectivity errors
+	ErrorExitCode = 1
+	// ForbiddenExitCode is the exit code of commands the cluster rejected as unauthorized or forbidden
+	ForbiddenExitCode = 3
+	// NotFoundExitCode is the exit code of commands failing as a requested resource like a Kamelet does not exist
+	NotFoundExitCode = 4
+	// CamelKNotInstalledExitCode is the exit code of commands failing as the cluster does not serve the Camel K APIs
+	CamelKNotInstalledExitCode = 5
+)
+
+// ExitCodesUsage documents the exit codes of the plugin in the help of the root command
+const ExitCodesUsage = `Exit codes:
+  0    the command succeeded
+  1    the command failed, e.g. as the cluster is not reachable
+  3    the request was rejected as unauthorized or forbidden
+  4    a requested resource like a Kamelet or binding was not found
+  5    Camel K is not installed on the cluster
+  130  the command was interrupted`
+
+// ExitCode returns the process exit code for given error returned by a command, so that scripts can tell a
+// missing resource from other failures
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return 0
+	case IsInterrupted(err):
+		return InterruptExitCode
+	case isCamelKNotInstalled(err):
+		return CamelKNotInstalledExitCode
+	case apierrors.IsNotFound(err):
+		return NotFoundExitCode
+	case apierrors.IsForbidden(err) || apierrors.IsUnauthorized(err):
+		return ForbiddenExitCode
+	default:
+		return ErrorExitCode
+	}
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	kamelets := schema.GroupResource{Group: "camel.apache.org", Resource: "kamelets"}
+	assert.Equal(t, ExitCode(nil), 0)
+	assert.Equal(t, ExitCode(errors.New("boom")), ErrorExitCode)
+	assert.Equal(t, ExitCode(apierrors.NewNotFound(kamelets, "k1")), NotFoundExitCode)
+	assert.Equal(t, ExitCode(fmt.Errorf("lookup failed: %w", apierrors.NewNotFound(kamelets, "k1"))), NotFoundExitCode)
+	assert.Equal(t, ExitCode(apierrors.NewForbidden(kamelets, "k1", errors.New("no access"))), ForbiddenExitCode)
+	assert.Equal(t, ExitCode(apierrors.NewUnauthorized("no token")), ForbiddenExitCode)
+	assert.Equal(t, ExitCode(fmt.Errorf("waiting failed: %w", ErrInterrupted)), InterruptExitCode)
+	assert.Equal(t, ExitCode(&ErrCamelKNotInstalled{Err: apierrors.NewNotFound(kamelets, "k1")}), CamelKNotInstalledExitCode)
+}
+
+func TestDescribeTypeExitCode(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(nil, apierrors.NewNotFound(schema.GroupResource{Group: "camel.apache.org", Resource: "kamelets"}, "k1"))
+	_, err := runDescribeTypeCmd(mockClient, "k1")
+	assert.ErrorContains(t, err, "not found")
+	assert.Equal(t, ExitCode(err), NotFoundExitCode)
+
+	recorder.Get(nil, errors.New("dial tcp 10.0.0.1:6443: connect: no route to host"))
+	_, err = runDescribeTypeCmd(mockClient, "k1")
+	assert.ErrorContains(t, err, "no route to host")
+	assert.Equal(t, ExitCode(err), ErrorExitCode)
+
+	recorder.Validate()
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/client/pkg/util"
+)
+
+// serverManagedFields are the metadata fields set by the API server, which --export strips along with the status
+var serverManagedFields = []string{"resourceVersion", "uid", "managedFields", "creationTimestamp"}
+
+// addExportFlag adds the flag stripping the server managed fields from printed objects
+func addExportFlag(flags *pflag.FlagSet, export *bool) {
+	flags.BoolVar(export, "export", false, "Strip the status and the server managed metadata, i.e. resourceVersion, "+
+		"uid, managedFields and creationTimestamp, from the printed Kamelets so that they can be applied again. "+
+		"Requires --output yaml or json.")
+}
+
+// validateExport checks that --export is combined with an object output format
+func validateExport(export bool, output string) error {
+	if !export {
+		return nil
+	}
+	if output = strings.ToLower(output); output != "yaml" && output != "json" {
+		return errors.New("--export requires --output yaml or json")
+	}
+	return nil
+}
+
+// exportObject returns given object or list as unstructured copy without status and server managed metadata
+func exportObject(obj runtime.Object) (runtime.Object, error) {
+	if meta.IsListType(obj) {
+		// the list is rebuilt from its items, which drops the resource version of the list
+		list, err := util.ToUnstructuredList(obj)
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			stripServerManagedFields(&list.Items[i])
+		}
+		return list, nil
+	}
+	item, err := util.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	stripServerManagedFields(item)
+	return item, nil
+}
+
+// stripServerManagedFields removes the status and the server managed metadata from given object
+func stripServerManagedFields(obj *unstructured.Unstructured) {
+	unstructured.RemoveNestedField(obj.Object, "status")
+	for _, field := range serverManagedFields {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"encoding/json"
+	"testing"
+
+	camelkapis "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/client/pkg/util"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+// createServerKamelet returns a Kamelet holding the fields set by the API server
+func createServerKamelet(name string) *camelkapis.Kamelet {
+	kamelet := createKamelet(name)
+	kamelet.ResourceVersion = "4711"
+	kamelet.UID = "6b3f2e4c-1f0e-4c5a-9d1b-2f7c8e9a0b1c"
+	kamelet.ManagedFields = []v1.ManagedFieldsEntry{{Manager: "kubectl", Operation: v1.ManagedFieldsOperationApply}}
+	return kamelet
+}
+
+func TestValidateExport(t *testing.T) {
+	assert.NilError(t, validateExport(false, ""))
+	assert.NilError(t, validateExport(true, "yaml"))
+	assert.NilError(t, validateExport(true, "JSON"))
+	assert.Error(t, validateExport(true, ""), "--export requires --output yaml or json")
+	assert.Error(t, validateExport(true, "name"), "--export requires --output yaml or json")
+}
+
+func TestDescribeTypeExport(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	recorder.Get(createServerKamelet("k1"), nil)
+	recorder.Get(createServerKamelet("k1"), nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1", "-o", "yaml")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "resourceVersion:", "uid:", "managedFields:", "creationTimestamp:", "status:"))
+
+	output, err = runDescribeTypeCmd(mockClient, "k1", "-o", "yaml", "--export")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "kind: Kamelet\n", "name: k1\n", "namespace: default\n",
+		"camel.apache.org/kamelet.type: source", "title: Kamelet k1"))
+	assert.Assert(t, util.ContainsNone(output, "resourceVersion:", "uid:", "managedFields:", "creationTimestamp:", "status:"))
+
+	_, err = runDescribeTypeCmd(mockClient, "k1", "--export")
+	assert.Error(t, err, "--export requires --output yaml or json")
+
+	recorder.Validate()
+}
+
+func TestListTypesExport(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	kameletList := &camelkapis.KameletList{
+		ListMeta: v1.ListMeta{ResourceVersion: "4712"},
+		Items:    []camelkapis.Kamelet{*createServerKamelet("k1"), *createServerKamelet("k2")},
+	}
+	recorder.List(kameletList, nil)
+
+	output, err := runListTypesCmd(mockClient, "-o", "json", "--export")
+	assert.NilError(t, err)
+	exported := map[string]interface{}{}
+	assert.NilError(t, json.Unmarshal([]byte(output), &exported))
+	assert.Equal(t, exported["kind"], "KameletList")
+	listMetadata, _ := exported["metadata"].(map[string]interface{})
+	_, found := listMetadata["resourceVersion"]
+	assert.Assert(t, !found)
+	items := exported["items"].([]interface{})
+	assert.Equal(t, len(items), 2)
+	for _, item := range items {
+		kamelet := item.(map[string]interface{})
+		_, found := kamelet["status"]
+		assert.Assert(t, !found)
+		metadata := kamelet["metadata"].(map[string]interface{})
+		for _, field := range []string{"resourceVersion", "uid", "managedFields", "creationTimestamp"} {
+			_, found := metadata[field]
+			assert.Assert(t, !found, field)
+		}
+		assert.Equal(t, metadata["namespace"], "default")
+	}
+
+	_, err = runListTypesCmd(mockClient, "--export")
+	assert.Error(t, err, "--export requires --output yaml or json")
+
+	recorder.Validate()
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+// defaultFieldManager is the manager recorded in the managed fields of the objects created or changed by the plugin
+const defaultFieldManager = managedByValue
+
+// fieldManagerMaxLength is the maximum length of a field manager name accepted by the API server
+const fieldManagerMaxLength = 128
+
+// addFieldManagerFlag adds the flag naming the field manager sent with the requests creating or changing objects
+func addFieldManagerFlag(flags *pflag.FlagSet, fieldManager *string) {
+	flags.StringVar(fieldManager, "field-manager", defaultFieldManager, "Name of the manager recorded for the fields "+
+		"set by this command in the managed fields of the created or changed objects.")
+}
+
+// validateFieldManager checks that given field manager name is accepted by the API server
+func validateFieldManager(fieldManager string) error {
+	if fieldManager == "" {
+		return errors.New("--field-manager must not be empty")
+	}
+	if len(fieldManager) > fieldManagerMaxLength {
+		return fmt.Errorf("invalid --field-manager '%s', must be no more than %d characters", fieldManager, fieldManagerMaxLength)
+	}
+	return nil
+}
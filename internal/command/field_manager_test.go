@@ -0,0 +1,112 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/client/pkg/util/mock"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestValidateFieldManager(t *testing.T) {
+	assert.NilError(t, validateFieldManager(defaultFieldManager))
+	assert.NilError(t, validateFieldManager(strings.Repeat("m", fieldManagerMaxLength)))
+	assert.Error(t, validateFieldManager(""), "--field-manager must not be empty")
+	assert.ErrorContains(t, validateFieldManager(strings.Repeat("m", fieldManagerMaxLength+1)), "must be no more than 128 characters")
+}
+
+func TestBindFieldManager(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+	bindingRecorder.CreateWithOptions(mock.Any(), v1.CreateOptions{FieldManager: defaultFieldManager}, nil)
+
+	_, err := runBindCmd(mockClient, "k1", "--sink", "broker:default", "--no-wait")
+	assert.NilError(t, err)
+
+	// the field manager is sent when replacing an existing binding as well
+	alreadyExists := apierrors.NewAlreadyExists(schema.GroupResource{Group: "camel.apache.org", Resource: "kameletbindings"}, "k1-binding")
+	recorder.Get(createKamelet("k1"), nil)
+	bindingRecorder.CreateWithOptions(mock.Any(), v1.CreateOptions{FieldManager: "gitops"}, alreadyExists)
+	bindingRecorder.Get("k1-binding", createKameletBindingFor("k1", "k1-binding"), nil)
+	bindingRecorder.UpdateWithOptions(mock.Any(), v1.UpdateOptions{FieldManager: "gitops"}, nil)
+
+	_, err = runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", "broker:default", "--replace", "--no-wait",
+		"--field-manager", "gitops")
+	assert.NilError(t, err)
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--field-manager", "")
+	assert.Error(t, err, "--field-manager must not be empty")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestUpdateFieldManager(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	binding := createKameletBindingFor("k1", "k1-binding")
+	bindingRecorder.Get("k1-binding", binding, nil)
+	bindingRecorder.PatchWithOptions("k1-binding", types.MergePatchType, mock.Any(),
+		v1.PatchOptions{FieldManager: "gitops"}, binding, nil)
+
+	_, err := runUpdateCmd(mockClient, "k1-binding", "--sink", "broker:default", "--field-manager", "gitops")
+	assert.NilError(t, err)
+
+	// a binding created with --force gets the field manager too
+	recorder.Get(createKamelet("k1"), nil)
+	bindingRecorder.Get("k1-binding", nil, newBindingNotFoundError("k1-binding"))
+	bindingRecorder.CreateWithOptions(mock.Any(), v1.CreateOptions{FieldManager: "gitops"}, nil)
+
+	_, err = runUpdateCmd(mockClient, "k1-binding", "--force", "--kamelet", "k1", "--sink", "broker:default",
+		"--field-manager", "gitops")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestCloneFieldManager(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKameletInNamespace("k1", "camel-k"), nil)
+	recorder.CreateWithOptions(mock.Any(), v1.CreateOptions{FieldManager: defaultFieldManager}, nil)
+
+	_, err := runCloneCmd(mockClient, "k1", "--from-namespace", "camel-k")
+	assert.NilError(t, err)
+
+	recorder.Get(createKameletInNamespace("k1", "camel-k"), nil)
+	recorder.CreateWithOptions(mock.Any(), v1.CreateOptions{FieldManager: "gitops"}, nil)
+
+	_, err = runCloneCmd(mockClient, "k1", "--from-namespace", "camel-k", "--field-manager", "gitops")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+)
+
+const (
+	// kameletTypeLabel marks a Kamelet as source, sink or action
+	kameletTypeLabel = "camel.apache.org/kamelet.type"
+	// kameletProviderAnnotation holds the (organization) name of the Kamelet provider
+	kameletProviderAnnotation = "camel.apache.org/provider"
+	// kameletGroupAnnotation holds the catalog group of the Kamelet, e.g. the name of the connected system
+	kameletGroupAnnotation = "camel.apache.org/kamelet.group"
+	// kameletSupportLevelAnnotation holds the support level of the Kamelet, e.g. Stable or Preview
+	kameletSupportLevelAnnotation = "camel.apache.org/kamelet.support.level"
+	// kameletDeprecatedAnnotation marks a Kamelet as deprecated when set to true
+	kameletDeprecatedAnnotation = "camel.apache.org/kamelet.deprecated"
+	// kameletReplacedByAnnotation names the Kamelet to use instead of a deprecated Kamelet
+	kameletReplacedByAnnotation = "camel.apache.org/kamelet.replaced-by"
+	// kameletSupportLevelDeprecated is the support level of deprecated Kamelets
+	kameletSupportLevelDeprecated = "Deprecated"
+
+	// managedByLabel marks the Kamelet bindings created by this plugin
+	managedByLabel = "app.kubernetes.io/managed-by"
+	managedByValue = "kn-source-kamelet"
+
+	kameletTypeSource = "source"
+	kameletTypeSink   = "sink"
+	kameletTypeAction = "action"
+)
+
+// presentationAnnotation maps an annotation used by Kamelet catalogs for presentation purposes to its label
+type presentationAnnotation struct {
+	annotation string
+	label      string
+}
+
+// presentationAnnotations lists the known presentation annotations in the order they are printed
+var presentationAnnotations = []presentationAnnotation{
+	{v1alpha1.AnnotationIcon, "Icon"},
+	{kameletGroupAnnotation, "Group"},
+	{kameletSupportLevelAnnotation, "Support Level"},
+}
+
+// kameletTypes lists all supported values of the Kamelet type label
+var kameletTypes = []string{kameletTypeSource, kameletTypeSink, kameletTypeAction}
+
+// validateKameletType checks that given type is one of the supported Kamelet types
+func validateKameletType(kameletType string) error {
+	for _, t := range kameletTypes {
+		if t == kameletType {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid Kamelet type '%s', must be one of: %s", kameletType, strings.Join(kameletTypes, ", "))
+}
+
+// goTemplateUsage is appended to the output flag usage to document the Go template output formats
+const goTemplateUsage = " Go templates over the Kamelet objects are given with -o go-template=TEMPLATE, " +
+	"-o go-template-file=FILE or -o go-template together with --template."
+
+// jsonPathUsage is appended to the output flag usage to document the JSONPath output formats
+const jsonPathUsage = " Single values are extracted with JSONPath expressions given with -o jsonpath=EXPRESSION, " +
+	"e.g. -o jsonpath='{.status.phase}'."
+
+// isKameletType returns true if given Kamelet is labeled with given type
+func isKameletType(kamelet *v1alpha1.Kamelet, kameletType string) bool {
+	return kamelet.Labels[kameletTypeLabel] == kameletType
+}
+
+// isKameletDeprecated returns true if given Kamelet is marked deprecated by its support level or annotation
+func isKameletDeprecated(kamelet *v1alpha1.Kamelet) bool {
+	return strings.EqualFold(kamelet.Annotations[kameletSupportLevelAnnotation], kameletSupportLevelDeprecated) ||
+		strings.EqualFold(kamelet.Annotations[kameletDeprecatedAnnotation], "true")
+}
+
+// deprecationWarning returns the warning printed for given deprecated Kamelet, naming its replacement if known
+func deprecationWarning(kamelet *v1alpha1.Kamelet) string {
+	warning := fmt.Sprintf("Warning: Kamelet %s is deprecated and should not be used for new bindings", kamelet.Name)
+	if replacement := kamelet.Annotations[kameletReplacedByAnnotation]; replacement != "" {
+		warning += fmt.Sprintf(", use %s instead", replacement)
+	}
+	return warning + "."
+}
+
+// verifyKameletType returns an error naming the actual type when given Kamelet is not of the expected type
+func verifyKameletType(kamelet *v1alpha1.Kamelet, expectedType string) error {
+	if isKameletType(kamelet, expectedType) {
+		return nil
+	}
+	return &ErrNotASource{Name: kamelet.Name, Type: kamelet.Labels[kameletTypeLabel], ExpectedType: expectedType}
+}
+
+// article returns the indefinite article to use in front of given Kamelet type
+func article(kameletType string) string {
+	if kameletType == kameletTypeAction {
+		return "an"
+	}
+	return "a"
+}
+
+// kameletURL returns the API path of given Kamelet. The path is built from group, version, namespace and name
+// when the deprecated self link is not populated by the API server.
+func kameletURL(kamelet *v1alpha1.Kamelet) string {
+	if kamelet.GetSelfLink() != "" {
+		return kamelet.GetSelfLink()
+	}
+	return fmt.Sprintf("/apis/%s/namespaces/%s/kamelets/%s", v1alpha1.SchemeGroupVersion.String(), kamelet.Namespace, kamelet.Name)
+}
+
+// extractKameletProvider returns the Kamelet provider or empty string if not set
+func extractKameletProvider(kamelet *v1alpha1.Kamelet) string {
+	return kamelet.Annotations[kameletProviderAnnotation]
+}
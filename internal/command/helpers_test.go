@@ -18,14 +18,29 @@ package command
 
 import (
 	"fmt"
+	"testing"
 
+	camelkapisv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	camelkv1alpha1 "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	camelkv1alpha1client "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
 )
 
 // Shared test helpers
 
+// mockBindingClient returns the factory injecting given mock as client of the Kamelet binding API
+func mockBindingClient(c *client.MockKameletClient) func() (camelkv1alpha1client.KameletBindingsGetter, error) {
+	return func() (camelkv1alpha1client.KameletBindingsGetter, error) {
+		return c, nil
+	}
+}
+
 func createKamelet(kameletName string) *camelkv1alpha1.Kamelet {
 	return createKameletInNamespace(kameletName, "default")
 }
@@ -62,3 +77,47 @@ func createKameletInNamespace(kameletName string, namespace string) *camelkv1alp
 		},
 	}
 }
+
+func addKameletProperty(kamelet *camelkv1alpha1.Kamelet, name string, propertyType string, description string, required bool) {
+	if kamelet.Spec.Definition.Properties == nil {
+		kamelet.Spec.Definition.Properties = map[string]camelkv1alpha1.JSONSchemaProps{}
+	}
+	kamelet.Spec.Definition.Properties[name] = camelkv1alpha1.JSONSchemaProps{
+		Type:        propertyType,
+		Description: description,
+	}
+	if required {
+		kamelet.Spec.Definition.Required = append(kamelet.Spec.Definition.Required, name)
+	}
+}
+
+func createKameletBindingFor(kameletName string, bindingName string) *camelkv1alpha1.KameletBinding {
+	binding := camelkv1alpha1.NewKameletBinding("current", bindingName)
+	binding.Labels = map[string]string{managedByLabel: managedByValue}
+	binding.Spec.Source = camelkv1alpha1.Endpoint{
+		Ref: &corev1.ObjectReference{
+			Kind:       camelkv1alpha1.KameletKind,
+			APIVersion: camelkv1alpha1.SchemeGroupVersion.String(),
+			Name:       kameletName,
+			Namespace:  "current",
+		},
+	}
+	return &binding
+}
+
+func setBindingProperties(t *testing.T, binding *camelkv1alpha1.KameletBinding, properties string) {
+	assert.Assert(t, properties != "")
+	binding.Spec.Source.Properties = &camelkv1alpha1.EndpointProperties{
+		RawMessage: camelkapisv1.RawMessage(properties),
+	}
+}
+
+func setKameletPropertyDefault(kamelet *camelkv1alpha1.Kamelet, name string, defaultValue string) {
+	property := kamelet.Spec.Definition.Properties[name]
+	property.Default = &camelkv1alpha1.JSON{RawMessage: []byte(defaultValue)}
+	kamelet.Spec.Definition.Properties[name] = property
+}
+
+func newBindingNotFoundError(name string) error {
+	return apierrors.NewNotFound(schema.GroupResource{Group: camelkv1alpha1.SchemeGroupVersion.Group, Resource: "kameletbindings"}, name)
+}
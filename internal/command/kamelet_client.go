@@ -0,0 +1,84 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// KameletClient is the client of the Kamelets the commands depend on. It only offers the requests the commands send,
+// so that tests can replace it with the recording mock of the internal client package or with the in-memory fake of
+// its testing subpackage. Kamelet bindings are managed with the binding clients of the binding API.
+type KameletClient interface {
+	Get(ctx context.Context, namespace string, name string, opts v1.GetOptions) (*v1alpha1.Kamelet, error)
+	List(ctx context.Context, namespace string, opts v1.ListOptions) (*v1alpha1.KameletList, error)
+	// Create creates given Kamelet in the namespace set on the Kamelet
+	Create(ctx context.Context, kamelet *v1alpha1.Kamelet, opts v1.CreateOptions) (*v1alpha1.Kamelet, error)
+	// Update updates given Kamelet in the namespace set on the Kamelet
+	Update(ctx context.Context, kamelet *v1alpha1.Kamelet, opts v1.UpdateOptions) (*v1alpha1.Kamelet, error)
+	Delete(ctx context.Context, namespace string, name string, opts v1.DeleteOptions) error
+	Watch(ctx context.Context, namespace string, opts v1.ListOptions) (watch.Interface, error)
+}
+
+// rawKameletClient is implemented by the Kamelet clients backed by an API server, which read Kamelets as JSON
+// documents exactly as served
+type rawKameletClient interface {
+	getRaw(ctx context.Context, namespace string, name string) ([]byte, error)
+}
+
+// kameletClient is the KameletClient sending the requests with the generated Camel K client
+type kameletClient struct {
+	client camelkv1alpha1.CamelV1alpha1Interface
+}
+
+// newKameletClientFor returns the KameletClient sending the requests with given generated Camel K client
+func newKameletClientFor(client camelkv1alpha1.CamelV1alpha1Interface) KameletClient {
+	return &kameletClient{client: client}
+}
+
+func (c *kameletClient) Get(ctx context.Context, namespace string, name string, opts v1.GetOptions) (*v1alpha1.Kamelet, error) {
+	return c.client.Kamelets(namespace).Get(ctx, name, opts)
+}
+
+func (c *kameletClient) List(ctx context.Context, namespace string, opts v1.ListOptions) (*v1alpha1.KameletList, error) {
+	return c.client.Kamelets(namespace).List(ctx, opts)
+}
+
+func (c *kameletClient) Create(ctx context.Context, kamelet *v1alpha1.Kamelet, opts v1.CreateOptions) (*v1alpha1.Kamelet, error) {
+	return c.client.Kamelets(kamelet.Namespace).Create(ctx, kamelet, opts)
+}
+
+func (c *kameletClient) Update(ctx context.Context, kamelet *v1alpha1.Kamelet, opts v1.UpdateOptions) (*v1alpha1.Kamelet, error) {
+	return c.client.Kamelets(kamelet.Namespace).Update(ctx, kamelet, opts)
+}
+
+func (c *kameletClient) Delete(ctx context.Context, namespace string, name string, opts v1.DeleteOptions) error {
+	return c.client.Kamelets(namespace).Delete(ctx, name, opts)
+}
+
+func (c *kameletClient) Watch(ctx context.Context, namespace string, opts v1.ListOptions) (watch.Interface, error) {
+	return c.client.Kamelets(namespace).Watch(ctx, opts)
+}
+
+func (c *kameletClient) getRaw(ctx context.Context, namespace string, name string) ([]byte, error) {
+	return c.client.RESTClient().Get().Namespace(namespace).Resource("kamelets").Name(name).DoRaw(ctx)
+}
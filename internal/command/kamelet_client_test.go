@@ -0,0 +1,73 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+	clienttesting "knative.dev/kn-plugin-source-kamelet/internal/client/testing"
+
+	"gotest.tools/v3/assert"
+)
+
+// Ensure that the interface is implemented by the mock and the fake
+var _ KameletClient = &client.MockKameletClient{}
+var _ KameletClient = &clienttesting.FakeKameletClient{}
+
+func TestKameletClient(t *testing.T) {
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"kind":"Kamelet","apiVersion":"camel.apache.org/v1alpha1","metadata":{"name":"k1","namespace":"camel-k"}}`))
+	}))
+	defer server.Close()
+
+	generated, err := camelkv1alpha1.NewForConfig(&rest.Config{Host: server.URL})
+	assert.NilError(t, err)
+	kameletClient := newKameletClientFor(generated)
+
+	// Kamelets are created and updated in the namespace set on them
+	kamelet := createKameletInNamespace("k1", "camel-k")
+	_, err = kameletClient.Create(context.TODO(), kamelet, v1.CreateOptions{})
+	assert.NilError(t, err)
+	_, err = kameletClient.Update(context.TODO(), kamelet, v1.UpdateOptions{})
+	assert.NilError(t, err)
+	_, err = kameletClient.Get(context.TODO(), "current", "k1", v1.GetOptions{})
+	assert.NilError(t, err)
+	assert.NilError(t, kameletClient.Delete(context.TODO(), "current", "k1", v1.DeleteOptions{}))
+	// the client backed by the API server reads the Kamelets as served
+	rawClient, ok := kameletClient.(rawKameletClient)
+	assert.Assert(t, ok)
+	_, err = rawClient.getRaw(context.TODO(), "current", "k1")
+	assert.NilError(t, err)
+
+	assert.DeepEqual(t, requests, []string{
+		"POST /apis/camel.apache.org/v1alpha1/namespaces/camel-k/kamelets",
+		"PUT /apis/camel.apache.org/v1alpha1/namespaces/camel-k/kamelets/k1",
+		"GET /apis/camel.apache.org/v1alpha1/namespaces/current/kamelets/k1",
+		"DELETE /apis/camel.apache.org/v1alpha1/namespaces/current/kamelets/k1",
+		"GET /apis/camel.apache.org/v1alpha1/namespaces/current/kamelets/k1",
+	})
+}
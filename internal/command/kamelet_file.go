@@ -0,0 +1,168 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// kameletFileExtensions are the extensions of the files read when describing the Kamelets of a directory
+var kameletFileExtensions = []string{".yaml", ".yml", ".json"}
+
+// readKameletFiles reads the Kamelet manifests of given file, which may hold several YAML documents, or of all YAML
+// and JSON files in given directory and its subdirectories when recursive is set. Every document of a given file
+// must be a Kamelet, while files and documents of a directory that are no Kamelets are skipped with a warning
+// written to given writer.
+func readKameletFiles(in io.Reader, warnings io.Writer, filename string, recursive bool) ([]*v1alpha1.Kamelet, error) {
+	if filename == "-" {
+		data, err := ioutil.ReadAll(in)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read Kamelet file '%s': %w", filename, err)
+		}
+		return decodeKamelets(data, filename, nil)
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read Kamelet file '%s': %w", filename, err)
+	}
+	if !info.IsDir() {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read Kamelet file '%s': %w", filename, err)
+		}
+		return decodeKamelets(data, filename, nil)
+	}
+	if !recursive {
+		return nil, fmt.Errorf("'%s' is a directory, use --recursive to describe the Kamelets in it", filename)
+	}
+
+	files, err := kameletFiles(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read Kamelet directory '%s': %w", filename, err)
+	}
+	var kamelets []*v1alpha1.Kamelet
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read Kamelet file '%s': %w", file, err)
+		}
+		decoded, err := decodeKamelets(data, file, warnings)
+		if err != nil {
+			return nil, err
+		}
+		kamelets = append(kamelets, decoded...)
+	}
+	if len(kamelets) == 0 {
+		return nil, fmt.Errorf("no Kamelet manifests found in directory '%s'", filename)
+	}
+	return kamelets, nil
+}
+
+// kameletFiles returns the sorted paths of the YAML and JSON files in given directory and its subdirectories
+func kameletFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		extension := strings.ToLower(filepath.Ext(path))
+		for _, kameletExtension := range kameletFileExtensions {
+			if extension == kameletExtension {
+				files = append(files, path)
+				break
+			}
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+// decodeKamelets decodes the YAML documents of given manifest into Kamelets. Documents that are no valid Kamelets
+// are skipped with a warning written to given writer, they fail the decoding if the writer is nil.
+func decodeKamelets(data []byte, filename string, warnings io.Writer) ([]*v1alpha1.Kamelet, error) {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	var kamelets []*v1alpha1.Kamelet
+	for {
+		document, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid Kamelet file '%s', expected YAML or JSON manifest: %w", filename, err)
+		}
+		if len(bytes.TrimSpace(document)) == 0 || string(bytes.TrimSpace(document)) == "---" {
+			continue
+		}
+		kamelet, err := decodeKamelet(document, filename)
+		if err != nil {
+			if warnings == nil {
+				return nil, err
+			}
+			fmt.Fprintf(warnings, "Warning: skipping document: %v\n", err)
+			continue
+		}
+		kamelets = append(kamelets, kamelet)
+	}
+	if len(kamelets) == 0 && warnings == nil {
+		return nil, fmt.Errorf("invalid Kamelet file '%s', the file holds no manifest", filename)
+	}
+	return kamelets, nil
+}
+
+// decodeKamelet decodes given YAML or JSON manifest into a Kamelet, failing if the manifest holds another kind
+func decodeKamelet(data []byte, filename string) (*v1alpha1.Kamelet, error) {
+	typeMeta := v1.TypeMeta{}
+	if err := yaml.Unmarshal(data, &typeMeta); err != nil {
+		return nil, fmt.Errorf("invalid Kamelet file '%s', expected YAML or JSON manifest: %w", filename, err)
+	}
+	if typeMeta.Kind != v1alpha1.KameletKind {
+		if typeMeta.Kind == "" {
+			return nil, fmt.Errorf("invalid Kamelet file '%s', the manifest has no kind, expected %s", filename, v1alpha1.KameletKind)
+		}
+		return nil, fmt.Errorf("invalid Kamelet file '%s', expected kind %s but found %s", filename, v1alpha1.KameletKind, typeMeta.Kind)
+	}
+	groupVersion, err := schema.ParseGroupVersion(typeMeta.APIVersion)
+	if err != nil || groupVersion.Group != v1alpha1.SchemeGroupVersion.Group {
+		return nil, fmt.Errorf("invalid Kamelet file '%s', expected API group %s but found API version '%s'", filename,
+			v1alpha1.SchemeGroupVersion.Group, typeMeta.APIVersion)
+	}
+
+	kamelet := &v1alpha1.Kamelet{}
+	if err := yaml.Unmarshal(data, kamelet); err != nil {
+		return nil, fmt.Errorf("invalid Kamelet file '%s': %w", filename, err)
+	}
+	return kamelet, nil
+}
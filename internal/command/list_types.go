@@ -17,10 +17,19 @@
 package command
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"knative.dev/client/pkg/kn/commands"
 
@@ -31,16 +40,121 @@ import (
 	hprinters "knative.dev/client/pkg/printers"
 )
 
+// defaultListLimit is the default number of Kamelets fetched per list request, set with --chunk-size
+const defaultListLimit = 500
+
+const (
+	kameletSortByName  = "name"
+	kameletSortByPhase = "phase"
+	kameletSortByAge   = "age"
+)
+
+// kameletSortByValues lists all supported sort orders for listed Kamelets
+var kameletSortByValues = []string{kameletSortByName, kameletSortByPhase, kameletSortByAge}
+
 var listExample = `
   # List available Kamelets
   kn-source-kamelet list-types
 
   # List available Kamelets in YAML output format
-  kn-source-kamelet list-types -o yaml`
+  kn-source-kamelet list-types -o yaml
+
+  # Write the available Kamelets in YAML output format to given file
+  kn-source-kamelet list-types -o yaml --output-file kamelets/available.yaml
+
+  # Export the available Kamelets as YAML without status and server managed metadata to apply them to another cluster
+  kn-source-kamelet list-types -o yaml --export --output-file kamelets/export.yaml
+
+  # List available Kamelets in YAML output format converted to API version camel.apache.org/v1alpha1
+  kn-source-kamelet list-types -o yaml --output-version camel.apache.org/v1alpha1
+
+  # List available sink Kamelets
+  kn-source-kamelet list-types --type sink
+
+  # List available sink Kamelets labeled with team=payments
+  kn-source-kamelet list-types -l team=payments --type sink
+
+  # List available Kamelets provided by the Apache Software Foundation
+  kn-source-kamelet list-types --provider "Apache Software Foundation"
+
+  # List available Kamelets mentioning "telegram" in their name, title or description, ignoring case
+  kn-source-kamelet list-types --search telegram
+
+  # List Kamelets that are not ready in all namespaces, i.e. Kamelets in phase Error or without phase
+  kn-source-kamelet list-types -A --phase Error --phase ""
+
+  # List available Kamelets whose provider contains "apache", ignoring case
+  kn-source-kamelet list-types --provider-contains apache
+
+  # List the available Kamelet with given name using a server side field selector
+  kn-source-kamelet list-types --field-selector metadata.name=timer-source
+
+  # List name and phase of available Kamelets
+  kn-source-kamelet list-types -o custom-columns=NAME:.metadata.name,PHASE:.status.phase
+
+  # Print name and phase of available Kamelets using a Go template
+  kn-source-kamelet list-types -o go-template='{{range .items}}{{.metadata.name}}: {{.status.phase}}{{"\n"}}{{end}}'
+
+  # Print the URLs of all available sink Kamelets
+  kn-source-kamelet list-types --type sink -o url
+
+  # List available Kamelets as JSON along with computed fields like the number of required properties and whether
+  # they are deprecated, -o json prints the Kamelets unmodified
+  kn-source-kamelet list-types -o wide-json
+
+  # List available Kamelets fetching at most 100 Kamelets per request
+  kn-source-kamelet list-types --chunk-size 100
+
+  # List available Kamelets created within the last 15 minutes
+  kn-source-kamelet list-types --since 15m
+
+  # List available Kamelets from the local cache
+  kn-source-kamelet list-types --cached
+
+  # Print the number of available sink Kamelets
+  kn-source-kamelet list-types --type sink --count
+
+  # Print the number of available Kamelets per namespace and in total
+  kn-source-kamelet list-types --all-namespaces --count
+
+  # List available Kamelets sorted by age, newest first
+  kn-source-kamelet list-types --sort-by age --reverse
+
+  # List available Kamelets with their title and support level
+  kn-source-kamelet list-types -o wide
+
+  # List available Kamelets with the number of their required and total properties
+  kn-source-kamelet list-types --show-props
+
+  # List name, provider, phase, property counts and age of available Kamelets
+  kn-source-kamelet list-types --columns name,provider,phase,props,age
+
+  # List available Kamelets without the table header, e.g. for piping into other tools
+  kn-source-kamelet list-types --no-headers`
 
 // NewListTypesCommand implements 'kn-source-kamelet list-types' command
 func NewListTypesCommand(p *KameletPluginParams) *cobra.Command {
 	kameletListFlags := flags.NewListPrintFlags(ListHandlers)
+	var kameletType string
+	var selector string
+	var fieldSelector string
+	var noColor bool
+	var limit int64
+	var cached bool
+	var refreshCache bool
+	var since time.Duration
+	var provider string
+	var providerContains string
+	var showProps bool
+	var count bool
+	var outputVersion string
+	var export bool
+	var sortBy string
+	var reverse bool
+	var search string
+	var outputFile string
+	var phases []string
+	var columnNames []string
 
 	cmd := &cobra.Command{
 		Use:     "list-types",
@@ -48,6 +162,96 @@ func NewListTypesCommand(p *KameletPluginParams) *cobra.Command {
 		Aliases: []string{"lst"},
 		Example: listExample,
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if err := validateKameletType(kameletType); err != nil {
+				return err
+			}
+			if _, err := labels.Parse(selector); err != nil {
+				return fmt.Errorf("invalid label selector '%s': %w", selector, err)
+			}
+			if _, err := fields.ParseSelector(fieldSelector); err != nil {
+				return fmt.Errorf("invalid field selector '%s': %w", fieldSelector, err)
+			}
+			if cmd.Flags().Changed("chunk-size") && cmd.Flags().Changed("limit") {
+				return errors.New("--chunk-size and --limit can not be used together")
+			}
+			if limit < 0 {
+				if cmd.Flags().Changed("limit") {
+					return fmt.Errorf("invalid limit %d, must not be negative", limit)
+				}
+				return fmt.Errorf("invalid chunk size %d, must not be negative", limit)
+			}
+			if since < 0 {
+				return fmt.Errorf("invalid duration %s for --since, must not be negative", since)
+			}
+			if err := validateKameletSortBy(sortBy); err != nil {
+				return err
+			}
+			if provider != "" && providerContains != "" {
+				return errors.New("--provider and --provider-contains can not be used together")
+			}
+			useCache := cached || refreshCache
+			if useCache && fieldSelector != "" {
+				return errors.New("--cached and --refresh-cache can not be combined with --field-selector")
+			}
+			if useCache && showProps {
+				return errors.New("--cached and --refresh-cache can not be combined with --show-props as cached Kamelets do not hold their properties")
+			}
+			if useCache && search != "" {
+				return errors.New("--cached and --refresh-cache can not be combined with --search as cached Kamelets do not hold their title and description")
+			}
+			// the wide table is printed by the plugin, the generic print flags do not know about it
+			wide := strings.ToLower(*kameletListFlags.GenericPrintFlags.OutputFormat) == "wide"
+			if useCache && wide {
+				return errors.New("--cached and --refresh-cache can not be combined with -o wide as cached Kamelets do not hold their title")
+			}
+			if wide {
+				*kameletListFlags.GenericPrintFlags.OutputFormat = ""
+				cmd.Flag("output").Changed = false
+			}
+			if showProps || wide {
+				kameletListFlags.PrinterHandler = listHandlers(kameletColumns{props: showProps, wide: wide})
+			}
+			if cmd.Flags().Changed("columns") {
+				if showProps || wide || kameletListFlags.GenericPrintFlags.OutputFlagSpecified() {
+					return errors.New("--columns can not be combined with --show-props or --output, select the columns to print with --columns instead")
+				}
+				selected, err := parseNamedColumns(columnNames)
+				if err != nil {
+					return err
+				}
+				if len(selected) == 0 {
+					return fmt.Errorf("--columns requires at least one column, must be one of: %s", strings.Join(namedColumnNames(), ", "))
+				}
+				for _, column := range selected {
+					if useCache && column.fromSpec {
+						return fmt.Errorf("--cached and --refresh-cache can not be combined with the %s column as cached "+
+							"Kamelets do not hold their spec", column.name)
+					}
+				}
+				kameletListFlags.PrinterHandler = namedColumnsHandlers(selected)
+			}
+			if count && kameletListFlags.GenericPrintFlags.OutputFlagSpecified() {
+				return errors.New("--count can not be combined with --output")
+			}
+			if err := validateOutputVersion(outputVersion, *kameletListFlags.GenericPrintFlags.OutputFormat); err != nil {
+				return err
+			}
+			if err := validateExport(export, *kameletListFlags.GenericPrintFlags.OutputFormat); err != nil {
+				return err
+			}
+
+			if cmd.Flags().Changed("namespace") && cmd.Flags().Changed("all-namespaces") {
+				return errors.New("--namespace and --all-namespaces can not be used together")
+			}
+
+			var columns []customColumn
+			if output := *kameletListFlags.GenericPrintFlags.OutputFormat; isCustomColumnsFormat(output) {
+				columns, err = parseCustomColumnsFormat(output)
+				if err != nil {
+					return err
+				}
+			}
+
 			namespace, err := p.GetNamespace(cmd)
 			if err != nil {
 				return err
@@ -58,21 +262,124 @@ func NewListTypesCommand(p *KameletPluginParams) *cobra.Command {
 				return err
 			}
 
-			kameletList, err := kameletClient.Kamelets(namespace).List(p.Context, v1.ListOptions{})
+			var kameletList *camelkv1alpha1.KameletList
+			if useCache {
+				kameletList, err = p.cachedKamelets(kameletClient, namespace, refreshCache)
+				if err != nil {
+					return err
+				}
+				if labelSelector, err := labels.Parse(selector); err == nil && !labelSelector.Empty() {
+					kameletList = filterKamelets(kameletList, kameletHasLabels(labelSelector))
+				}
+			} else {
+				kameletList, err = listAllKamelets(p, kameletClient, namespace, v1.ListOptions{LabelSelector: selector, FieldSelector: fieldSelector}, limit)
+				if err != nil {
+					if fieldSelector != "" && apierrors.IsBadRequest(err) {
+						return fmt.Errorf("unable to list Kamelets with field selector '%s': %w", fieldSelector, err)
+					}
+					return err
+				}
+			}
+
+			kameletList = filterKamelets(kameletList, kameletOfType(kameletType))
+			if provider != "" {
+				kameletList = filterKamelets(kameletList, kameletOfProvider(provider, false))
+			} else if providerContains != "" {
+				kameletList = filterKamelets(kameletList, kameletOfProvider(providerContains, true))
+			}
+			if search != "" {
+				kameletList = filterKamelets(kameletList, kameletContains(search))
+			}
+			if cmd.Flags().Changed("phase") {
+				kameletList = filterKamelets(kameletList, kameletInPhase(phases))
+			}
+			if since > 0 {
+				kameletList = filterKamelets(kameletList, kameletCreatedAfter(time.Now().Add(-since)))
+			}
+			out, finish, err := openOutput(cmd.OutOrStdout(), outputFile)
 			if err != nil {
 				return err
 			}
+			defer func() { err = finish(err) }()
+			// messages are no output, they are printed to stderr when writing to a file
+			messageOut := cmd.OutOrStdout()
+			if outputFile != "" {
+				messageOut = cmd.ErrOrStderr()
+			}
+
+			if count {
+				return printKameletCount(out, kameletList, namespace == "")
+			}
+			updateKameletListGVK(kameletList)
+			// structured output keeps the order of the server
+			if !kameletListFlags.GenericPrintFlags.OutputFlagSpecified() || columns != nil ||
+				strings.ToLower(*kameletListFlags.GenericPrintFlags.OutputFormat) == "url" {
+				sortKamelets(kameletList, sortBy, reverse)
+			}
 			if len(kameletList.Items) == 0 {
-				fmt.Fprintf(cmd.OutOrStdout(), "No resources found.\n")
+				if namespace == "" {
+					fmt.Fprintf(p.messageWriter(messageOut), "No Kamelets found.\n")
+				} else {
+					fmt.Fprintf(p.messageWriter(messageOut), "No Kamelets found in namespace %s\n", namespace)
+				}
+				return nil
+			}
+
+			if strings.ToLower(*kameletListFlags.GenericPrintFlags.OutputFormat) == "url" {
+				for i := range kameletList.Items {
+					fmt.Fprintf(out, "%s\n", kameletURL(&kameletList.Items[i]))
+				}
 				return nil
 			}
 
+			if strings.ToLower(*kameletListFlags.GenericPrintFlags.OutputFormat) == wideJSONFormat {
+				kamelets := make([]*camelkv1alpha1.Kamelet, 0, len(kameletList.Items))
+				for i := range kameletList.Items {
+					kamelets = append(kamelets, &kameletList.Items[i])
+				}
+				return writeKameletsWideJSON(out, kamelets, true)
+			}
+
+			if columns != nil {
+				objects := make([]runtime.Object, 0, len(kameletList.Items))
+				for i := range kameletList.Items {
+					objects = append(objects, &kameletList.Items[i])
+				}
+				return printCustomColumns(out, columns, objects, kameletListFlags.HumanReadableFlags.NoHeaders)
+			}
+
+			if outputVersion != "" || export {
+				var printed runtime.Object = kameletList
+				if outputVersion != "" {
+					if printed, err = convertKameletListToOutputVersion(kameletList, outputVersion); err != nil {
+						return err
+					}
+				}
+				if export {
+					if printed, err = exportObject(printed); err != nil {
+						return err
+					}
+				}
+				return kameletListFlags.Print(printed, out)
+			}
+
 			// empty namespace indicates all-namespaces flag is specified
 			if namespace == "" {
 				kameletListFlags.EnsureWithNamespace()
 			}
 
-			err = kameletListFlags.Print(kameletList, cmd.OutOrStdout())
+			if !kameletListFlags.GenericPrintFlags.OutputFlagSpecified() && useColor(out, noColor) {
+				noHeaders := kameletListFlags.HumanReadableFlags.NoHeaders
+				kameletListFlags.HumanReadableFlags.NoHeaders = false
+				table := &bytes.Buffer{}
+				if err := kameletListFlags.Print(kameletList, table); err != nil {
+					return err
+				}
+				_, err = fmt.Fprint(out, colorPhaseColumn(table.String(), noHeaders))
+				return err
+			}
+
+			err = kameletListFlags.Print(kameletList, out)
 			if err != nil {
 				return err
 			}
@@ -80,32 +387,289 @@ func NewListTypesCommand(p *KameletPluginParams) *cobra.Command {
 		},
 	}
 	commands.AddNamespaceFlags(cmd.Flags(), true)
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "Selector (label query) to filter on, supports '=', '==', and '!=' (e.g. -l key1=value1,key2=value2).")
+	cmd.Flags().StringVar(&fieldSelector, "field-selector", "", "Selector (field query) to filter on, supports '=', '==', and '!=' "+
+		"(e.g. --field-selector metadata.name=timer-source). The server only supports a limited number of field queries per type.")
+	cmd.Flags().StringVar(&kameletType, "type", kameletTypeSource, fmt.Sprintf("Type of Kamelets to list. One of: %s.", strings.Join(kameletTypes, "|")))
+	cmd.Flags().StringVar(&provider, "provider", "", "Only list Kamelets of given provider. "+
+		"The provider name must match completely, ignoring case.")
+	cmd.Flags().StringVar(&providerContains, "provider-contains", "", "Only list Kamelets whose provider name contains "+
+		"given text, ignoring case. Unlike --provider a part of the provider name is sufficient.")
+	cmd.Flags().StringVar(&search, "search", "", "Only list Kamelets whose name, title or description contains given "+
+		"text, ignoring case. The filter is applied client side and can be combined with --type and --selector.")
+	cmd.Flags().StringArrayVar(&phases, "phase", nil, "Only list Kamelets in given phase, e.g. Ready or Error, ignoring "+
+		"case. Use an empty value for Kamelets without phase. Can be given multiple times to match any of the phases.")
+	cmd.Flags().Int64Var(&limit, "chunk-size", defaultListLimit, "Maximum number of Kamelets fetched per request. "+
+		"All chunks are fetched and aggregated, the chunk size only limits the memory needed per request. "+
+		"Use 0 to fetch all Kamelets with a single request.")
+	cmd.Flags().Int64Var(&limit, "limit", defaultListLimit, "Maximum number of Kamelets fetched per request.")
+	_ = cmd.Flags().MarkDeprecated("limit", "use --chunk-size instead")
+	addOutputFileFlag(cmd.Flags(), &outputFile)
+	cmd.Flags().DurationVar(&since, "since", 0, "Only list Kamelets created within given duration, e.g. 30m. "+
+		"The filter is applied client side, so all Kamelets are still fetched from the cluster.")
+	cmd.Flags().BoolVar(&cached, "cached", false, fmt.Sprintf("List the Kamelets from the local cache, which is refreshed "+
+		"when older than %s. Cached Kamelets do not hold their spec.", kameletCacheTTL))
+	cmd.Flags().BoolVar(&refreshCache, "refresh-cache", false, "Fetch the Kamelets from the cluster and rebuild the local cache.")
+	cmd.Flags().BoolVar(&count, "count", false, "Only print the number of matching Kamelets. "+
+		"With --all-namespaces the number of Kamelets per namespace is printed followed by the total.")
+	cmd.Flags().BoolVar(&showProps, "show-props", false, "Add a PROPS column to the table showing the number of required "+
+		"and the total number of properties of each Kamelet as required/total.")
+	cmd.Flags().StringSliceVar(&columnNames, "columns", nil, fmt.Sprintf("Comma separated list of the columns of the "+
+		"table in the order they are printed, e.g. name,provider,phase,props,age. One of: %s. "+
+		"A lightweight alternative to -o custom-columns.", strings.Join(namedColumnNames(), "|")))
+	cmd.Flags().StringVar(&sortBy, "sort-by", "", fmt.Sprintf("Sort the listed Kamelets. One of: %s. "+
+		"Kamelets are listed in the order of the server when not set, age sorts the oldest Kamelets first. "+
+		"Only applies to the table, custom-columns and url output, yaml, json and template output keep the order of the server.",
+		strings.Join(kameletSortByValues, "|")))
+	cmd.Flags().BoolVar(&reverse, "reverse", false, "Reverse the order of the listed Kamelets, e.g. to list the newest Kamelets "+
+		"first with --sort-by age. Like --sort-by no-op for yaml, json and template output.")
+	addNoColorFlag(cmd.Flags(), &noColor)
 	kameletListFlags.AddFlags(cmd)
+	addOutputVersionFlag(cmd.Flags(), &outputVersion)
+	addExportFlag(cmd.Flags(), &export)
+	outputFlag := cmd.Flags().Lookup("output")
+	outputFlag.Usage = strings.TrimSuffix(outputFlag.Usage, ".") + "|" + customColumnsFormat + "|" + customColumnsFileFormat + "|url|wide|" + wideJSONFormat + "." + goTemplateUsage + jsonPathUsage
 	return cmd
 }
 
+// listAllKamelets lists the Kamelets matching given options page by page following the continue tokens of
+// the API server and returns all items as single list. A limit of zero fetches all Kamelets with a single request.
+func listAllKamelets(p *KameletPluginParams, client KameletClient, namespace string,
+	opts v1.ListOptions, limit int64) (*camelkv1alpha1.KameletList, error) {
+	opts.Limit = limit
+	result := &camelkv1alpha1.KameletList{}
+	for {
+		page, err := p.listKamelets(client, namespace, opts)
+		if err != nil {
+			return nil, err
+		}
+		if opts.Continue == "" {
+			// the aggregated list is a consistent snapshot of the first page's resource version
+			result.TypeMeta = page.TypeMeta
+			result.ResourceVersion = page.ResourceVersion
+		}
+		result.Items = append(result.Items, page.Items...)
+		if page.Continue == "" {
+			return result, nil
+		}
+		opts.Continue = page.Continue
+	}
+}
+
+// printKameletCount prints the number of Kamelets in given list. With all namespaces the number of Kamelets
+// per namespace is printed ordered by namespace, followed by the total.
+func printKameletCount(out io.Writer, kameletList *camelkv1alpha1.KameletList, allNamespaces bool) error {
+	if !allNamespaces {
+		_, err := fmt.Fprintln(out, len(kameletList.Items))
+		return err
+	}
+
+	counts := map[string]int{}
+	for i := range kameletList.Items {
+		counts[kameletList.Items[i].Namespace]++
+	}
+	namespaces := make([]string, 0, len(counts))
+	for namespace := range counts {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	w := hprinters.NewTabWriter(out)
+	fmt.Fprintln(w, "NAMESPACE\tCOUNT")
+	for _, namespace := range namespaces {
+		fmt.Fprintf(w, "%s\t%d\n", namespace, counts[namespace])
+	}
+	fmt.Fprintf(w, "Total\t%d\n", len(kameletList.Items))
+	return w.Flush()
+}
+
+// validateKameletSortBy fails if given sort order of listed Kamelets is not supported, empty keeps the server order
+func validateKameletSortBy(sortBy string) error {
+	if sortBy == "" {
+		return nil
+	}
+	for _, s := range kameletSortByValues {
+		if s == sortBy {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid sort order '%s', must be one of: %s", sortBy, strings.Join(kameletSortByValues, ", "))
+}
+
+// sortKamelets sorts the Kamelets of given list in place by given key, Kamelets with equal keys are ordered by
+// namespace and name. An empty key keeps the server order, which still gets inverted when reverse is set.
+func sortKamelets(kameletList *camelkv1alpha1.KameletList, sortBy string, reverse bool) {
+	items := kameletList.Items
+	if sortBy != "" {
+		sort.SliceStable(items, func(i, j int) bool {
+			switch sortBy {
+			case kameletSortByPhase:
+				if items[i].Status.Phase != items[j].Status.Phase {
+					return items[i].Status.Phase < items[j].Status.Phase
+				}
+			case kameletSortByAge:
+				if !items[i].CreationTimestamp.Equal(&items[j].CreationTimestamp) {
+					return items[i].CreationTimestamp.Before(&items[j].CreationTimestamp)
+				}
+			}
+			if items[i].Namespace != items[j].Namespace {
+				return items[i].Namespace < items[j].Namespace
+			}
+			return items[i].Name < items[j].Name
+		})
+	}
+	if reverse {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+}
+
+// filterKamelets returns a copy of the given list holding only the Kamelets matching given predicate
+func filterKamelets(kameletList *camelkv1alpha1.KameletList, matches func(*camelkv1alpha1.Kamelet) bool) *camelkv1alpha1.KameletList {
+	filtered := &camelkv1alpha1.KameletList{
+		TypeMeta: kameletList.TypeMeta,
+		ListMeta: kameletList.ListMeta,
+		Items:    make([]camelkv1alpha1.Kamelet, 0, len(kameletList.Items)),
+	}
+	for i := range kameletList.Items {
+		if matches(&kameletList.Items[i]) {
+			filtered.Items = append(filtered.Items, kameletList.Items[i])
+		}
+	}
+	return filtered
+}
+
+// kameletHasLabels returns a predicate matching Kamelets with labels matching given label selector
+func kameletHasLabels(selector labels.Selector) func(*camelkv1alpha1.Kamelet) bool {
+	return func(kamelet *camelkv1alpha1.Kamelet) bool {
+		return selector.Matches(labels.Set(kamelet.Labels))
+	}
+}
+
+// kameletCreatedAfter returns a predicate matching Kamelets created after given time
+func kameletCreatedAfter(after time.Time) func(*camelkv1alpha1.Kamelet) bool {
+	return func(kamelet *camelkv1alpha1.Kamelet) bool {
+		return kamelet.CreationTimestamp.Time.After(after)
+	}
+}
+
+// kameletOfProvider returns a predicate matching Kamelets of given provider. Provider names are compared ignoring
+// case, given name only needs to be contained in the provider name if requested.
+func kameletOfProvider(provider string, contains bool) func(*camelkv1alpha1.Kamelet) bool {
+	provider = strings.ToLower(provider)
+	return func(kamelet *camelkv1alpha1.Kamelet) bool {
+		kameletProvider := strings.ToLower(extractKameletProvider(kamelet))
+		return kameletProvider == provider || (contains && strings.Contains(kameletProvider, provider))
+	}
+}
+
+// kameletContains returns a predicate matching Kamelets whose name, title or description contains given text,
+// ignoring case
+func kameletContains(search string) func(*camelkv1alpha1.Kamelet) bool {
+	search = strings.ToLower(search)
+	return func(kamelet *camelkv1alpha1.Kamelet) bool {
+		definition := kameletDefinition(kamelet)
+		for _, text := range []string{kamelet.Name, definition.Title, definition.Description} {
+			if strings.Contains(strings.ToLower(text), search) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// kameletInPhase returns a predicate matching Kamelets in one of given phases, which are compared ignoring case
+func kameletInPhase(phases []string) func(*camelkv1alpha1.Kamelet) bool {
+	return func(kamelet *camelkv1alpha1.Kamelet) bool {
+		for _, phase := range phases {
+			if strings.EqualFold(string(kamelet.Status.Phase), phase) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// kameletOfType returns a predicate matching Kamelets of given type
+func kameletOfType(kameletType string) func(*camelkv1alpha1.Kamelet) bool {
+	return func(kamelet *camelkv1alpha1.Kamelet) bool {
+		return isKameletType(kamelet, kameletType)
+	}
+}
+
+// updateKameletListGVK sets the type meta of given list and its items, which typed clients do not populate
+func updateKameletListGVK(kameletList *camelkv1alpha1.KameletList) {
+	kameletList.SetGroupVersionKind(camelkv1alpha1.SchemeGroupVersion.WithKind("KameletList"))
+	for i := range kameletList.Items {
+		kameletList.Items[i].SetGroupVersionKind(camelkv1alpha1.SchemeGroupVersion.WithKind(camelkv1alpha1.KameletKind))
+	}
+}
+
+// maxTitleWidth is the width at which Kamelet titles are truncated in the wide table
+const maxTitleWidth = 40
+
+// kameletColumns selects the optional columns of the Kamelet table
+type kameletColumns struct {
+	// props adds the property counts
+	props bool
+	// wide adds the title and the support level
+	wide bool
+}
+
 // ListHandlers handles printing human readable table for `kn-source-kamelet list-types` command's output
 func ListHandlers(h hprinters.PrintHandler) {
-	kameletColumnDefinitions := []metav1beta1.TableColumnDefinition{
+	listHandlers(kameletColumns{})(h)
+}
+
+// listHandlers returns the handler printing the human readable Kamelet table with given optional columns
+func listHandlers(columns kameletColumns) func(h hprinters.PrintHandler) {
+	return func(h hprinters.PrintHandler) {
+		columnDefinitions := kameletColumnDefinitions(columns)
+		h.TableHandler(columnDefinitions, func(kamelet *camelkv1alpha1.Kamelet, options hprinters.PrintOptions) ([]metav1beta1.TableRow, error) {
+			return printKamelet(kamelet, options, columns)
+		})
+		h.TableHandler(columnDefinitions, func(kameletList *camelkv1alpha1.KameletList, options hprinters.PrintOptions) ([]metav1beta1.TableRow, error) {
+			return printKameletList(kameletList, options, columns)
+		})
+	}
+}
+
+// kameletColumnDefinitions returns the columns of the Kamelet table including given optional columns
+func kameletColumnDefinitions(columns kameletColumns) []metav1beta1.TableColumnDefinition {
+	columnDefinitions := []metav1beta1.TableColumnDefinition{
 		{Name: "Namespace", Type: "string", Description: "Namespace of the Kamelet instance", Priority: 0},
 		{Name: "Name", Type: "string", Description: "Name of the Kamelet instance", Priority: 1},
-		{Name: "Phase", Type: "string", Description: "Phase of the Kamelet instance", Priority: 1},
-		{Name: "Age", Type: "string", Description: "Age of the Kamelet instance", Priority: 1},
-		{Name: "Conditions", Type: "string", Description: "Ready state conditions", Priority: 1},
-		{Name: "Ready", Type: "string", Description: "Ready state of the Kamelet instance", Priority: 1},
-		{Name: "Reason", Type: "string", Description: "Reason if state is not Ready", Priority: 1},
 	}
-	h.TableHandler(kameletColumnDefinitions, printKamelet)
-	h.TableHandler(kameletColumnDefinitions, printKameletList)
+	if columns.wide {
+		columnDefinitions = append(columnDefinitions,
+			metav1beta1.TableColumnDefinition{Name: "Title", Type: "string", Description: "Title of the Kamelet instance", Priority: 1})
+	}
+	columnDefinitions = append(columnDefinitions,
+		metav1beta1.TableColumnDefinition{Name: "Phase", Type: "string", Description: "Phase of the Kamelet instance", Priority: 1},
+		metav1beta1.TableColumnDefinition{Name: "Provider", Type: "string", Description: "Provider of the Kamelet instance", Priority: 1})
+	if columns.wide {
+		columnDefinitions = append(columnDefinitions,
+			metav1beta1.TableColumnDefinition{Name: "Support Level", Type: "string", Description: "Support level of the Kamelet instance", Priority: 1})
+	}
+	if columns.props {
+		columnDefinitions = append(columnDefinitions,
+			metav1beta1.TableColumnDefinition{Name: "Props", Type: "string", Description: "Required and total properties of the Kamelet instance", Priority: 1})
+	}
+	return append(columnDefinitions,
+		metav1beta1.TableColumnDefinition{Name: "Age", Type: "string", Description: "Age of the Kamelet instance", Priority: 1},
+		metav1beta1.TableColumnDefinition{Name: "Conditions", Type: "string", Description: "Ready state conditions", Priority: 1},
+		metav1beta1.TableColumnDefinition{Name: "Ready", Type: "string", Description: "Ready state of the Kamelet instance", Priority: 1},
+		metav1beta1.TableColumnDefinition{Name: "Reason", Type: "string", Description: "Reason if state is not Ready", Priority: 1})
 }
 
 // printKameletList populates the Kamelet list table rows
-func printKameletList(kameletList *camelkv1alpha1.KameletList, options hprinters.PrintOptions) ([]metav1beta1.TableRow, error) {
+func printKameletList(kameletList *camelkv1alpha1.KameletList, options hprinters.PrintOptions, columns kameletColumns) ([]metav1beta1.TableRow, error) {
 	rows := make([]metav1beta1.TableRow, 0, len(kameletList.Items))
 
 	for i := range kameletList.Items {
 		ksvc := &kameletList.Items[i]
-		r, err := printKamelet(ksvc, options)
+		r, err := printKamelet(ksvc, options, columns)
 		if err != nil {
 			return nil, err
 		}
@@ -115,9 +679,10 @@ func printKameletList(kameletList *camelkv1alpha1.KameletList, options hprinters
 }
 
 // printKamelet populates the Kamelet table rows
-func printKamelet(kamelet *camelkv1alpha1.Kamelet, options hprinters.PrintOptions) ([]metav1beta1.TableRow, error) {
+func printKamelet(kamelet *camelkv1alpha1.Kamelet, options hprinters.PrintOptions, columns kameletColumns) ([]metav1beta1.TableRow, error) {
 	name := kamelet.Name
 	phase := kamelet.Status.Phase
+	provider := extractKameletProvider(kamelet)
 	age := commands.TranslateTimestampSince(kamelet.CreationTimestamp)
 	conditions := conditionsValue(kamelet.Status.Conditions)
 	ready := readyCondition(kamelet.Status.Conditions)
@@ -131,9 +696,20 @@ func printKamelet(kamelet *camelkv1alpha1.Kamelet, options hprinters.PrintOption
 		row.Cells = append(row.Cells, kamelet.Namespace)
 	}
 
+	row.Cells = append(row.Cells, name)
+	if columns.wide {
+		row.Cells = append(row.Cells, kameletTitle(kamelet))
+	}
 	row.Cells = append(row.Cells,
-		name,
 		phase,
+		provider)
+	if columns.wide {
+		row.Cells = append(row.Cells, kamelet.Annotations[kameletSupportLevelAnnotation])
+	}
+	if columns.props {
+		row.Cells = append(row.Cells, propertyCounts(kamelet))
+	}
+	row.Cells = append(row.Cells,
 		age,
 		conditions,
 		ready,
@@ -141,6 +717,38 @@ func printKamelet(kamelet *camelkv1alpha1.Kamelet, options hprinters.PrintOption
 	return []metav1beta1.TableRow{row}, nil
 }
 
+// kameletTitle returns the title of given Kamelet truncated for the wide table
+func kameletTitle(kamelet *camelkv1alpha1.Kamelet) string {
+	if kamelet.Spec.Definition == nil {
+		return ""
+	}
+	return truncate(kamelet.Spec.Definition.Title, maxTitleWidth)
+}
+
+// propertyCounts returns the number of required properties among all properties of given Kamelet
+func propertyCounts(kamelet *camelkv1alpha1.Kamelet) string {
+	definition := kamelet.Spec.Definition
+	if definition == nil {
+		return "0/0"
+	}
+	return fmt.Sprintf("%d/%d", requiredPropertyCount(kamelet), len(definition.Properties))
+}
+
+// requiredPropertyCount returns the number of required properties of given Kamelet
+func requiredPropertyCount(kamelet *camelkv1alpha1.Kamelet) int {
+	definition := kamelet.Spec.Definition
+	if definition == nil {
+		return 0
+	}
+	var required int
+	for propertyName := range definition.Properties {
+		if isRequired(definition, propertyName) {
+			required++
+		}
+	}
+	return required
+}
+
 // conditionsValue returns the True conditions count among total conditions
 func conditionsValue(conditions []camelkv1alpha1.KameletCondition) string {
 	var ok int
@@ -20,12 +20,15 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	camelkapis "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
-	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"knative.dev/client/pkg/kn/commands"
 	"knative.dev/client/pkg/util"
 	"knative.dev/kn-plugin-source-kamelet/internal/client"
+	clienttesting "knative.dev/kn-plugin-source-kamelet/internal/client/testing"
 
 	"gotest.tools/v3/assert"
 )
@@ -64,6 +67,556 @@ func TestListTypesOutput(t *testing.T) {
 	recorder.Validate()
 }
 
+func TestListTypesNoHeaders(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kameletList := &camelkapis.KameletList{Items: []camelkapis.Kamelet{*createKamelet("k1"), *createKamelet("k2")}}
+	recorder.List(kameletList, nil)
+
+	output, err := runListTypesCmd(mockClient, "--no-headers")
+	assert.NilError(t, err)
+
+	outputLines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	assert.Equal(t, len(outputLines), 2)
+	assert.Check(t, util.ContainsNone(output, "NAME", "PHASE", "AGE"))
+	assert.Check(t, util.ContainsAll(outputLines[0], "k1", "Ready", "1 OK / 1", "True"))
+	assert.Check(t, util.ContainsAll(outputLines[1], "k2", "Ready", "1 OK / 1", "True"))
+
+	recorder.Validate()
+}
+
+func TestListTypesNoHeadersYAMLOutput(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kameletList := &camelkapis.KameletList{Items: []camelkapis.Kamelet{*createKamelet("k1")}}
+	recorder.List(kameletList, nil)
+
+	output, err := runListTypesCmd(mockClient, "--no-headers", "-o", "yaml")
+	assert.NilError(t, err)
+	assert.Assert(t, strings.HasPrefix(output, "apiVersion: camel.apache.org/v1alpha1\nitems:\n"))
+
+	recorder.Validate()
+}
+
+func TestListTypesCustomColumns(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet1 := createKamelet("k1")
+	kamelet2 := createKamelet("k2")
+	kamelet2.Status.Phase = ""
+	kameletList := &camelkapis.KameletList{Items: []camelkapis.Kamelet{*kamelet1, *kamelet2}}
+	recorder.List(kameletList, nil)
+
+	output, err := runListTypesCmd(mockClient, "-o", "custom-columns=NAME:.metadata.name,PHASE:.status.phase,TITLE:spec.definition.title")
+	assert.NilError(t, err)
+
+	outputLines := strings.Split(output, "\n")
+	assert.Check(t, util.ContainsAll(outputLines[0], "NAME", "PHASE", "TITLE"))
+	assert.Check(t, util.ContainsNone(outputLines[0], "AGE", "CONDITIONS"))
+	assert.Check(t, util.ContainsAll(outputLines[1], "k1", "Ready", "Kamelet k1"))
+	assert.Check(t, util.ContainsAll(outputLines[2], "k2", "<none>", "Kamelet k2"))
+
+	recorder.Validate()
+}
+
+func TestListTypesCustomColumnsFile(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kameletList := &camelkapis.KameletList{Items: []camelkapis.Kamelet{*createKamelet("k1")}}
+	recorder.List(kameletList, nil)
+
+	output, err := runListTypesCmd(mockClient, "-o", "custom-columns-file=testdata/custom-columns.txt", "--no-headers")
+	assert.NilError(t, err)
+
+	outputLines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	assert.Equal(t, len(outputLines), 1)
+	assert.Check(t, util.ContainsAll(outputLines[0], "k1", "Kamelet k1", "True"))
+
+	recorder.Validate()
+}
+
+func TestListTypesInvalidCustomColumns(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	_, err := runListTypesCmd(mockClient, "-o", "custom-columns=NAME")
+	assert.Error(t, err, "invalid custom column 'NAME', expected format HEADER:JSONPATH")
+
+	_, err = runListTypesCmd(mockClient, "-o", "custom-columns=")
+	assert.Error(t, err, "custom-columns format specified but no custom columns given")
+
+	_, err = runListTypesCmd(mockClient, "-o", "custom-columns-file=testdata/missing.txt")
+	assert.ErrorContains(t, err, "unable to read custom columns file 'testdata/missing.txt'")
+
+	recorder.Validate()
+}
+
+func TestListTypesURL(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet1 := createKamelet("k1")
+	kamelet2 := createKamelet("k2")
+	kamelet2.Labels[kameletTypeLabel] = kameletTypeSink
+	kamelet3 := createKamelet("k3")
+	// modern API servers do not populate the deprecated self link
+	kamelet3.SelfLink = ""
+	kameletList := &camelkapis.KameletList{Items: []camelkapis.Kamelet{*kamelet1, *kamelet2, *kamelet3}}
+	recorder.ListWithOptions(v1.ListOptions{LabelSelector: "team=payments", Limit: defaultListLimit}, kameletList, nil)
+
+	output, err := runListTypesCmd(mockClient, "-o", "url", "-l", "team=payments")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "/apis/camel.apache.org/v1alpha1/namespaces/default/kamelets/k1\n"+
+		"/apis/camel.apache.org/v1alpha1/namespaces/default/kamelets/k3\n")
+
+	recorder.Validate()
+}
+
+func TestListTypesPagination(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	page1 := &camelkapis.KameletList{Items: []camelkapis.Kamelet{*createKamelet("k1"), *createKamelet("k2")}}
+	page1.Continue = "page2"
+	page2 := &camelkapis.KameletList{Items: []camelkapis.Kamelet{*createKamelet("k3"), *createKamelet("k4")}}
+	page2.Continue = "page3"
+	page3 := &camelkapis.KameletList{Items: []camelkapis.Kamelet{*createKamelet("k5")}}
+	for i := 0; i < 3; i++ {
+		recorder.ListWithOptions(v1.ListOptions{Limit: 2}, page1, nil)
+		recorder.ListWithOptions(v1.ListOptions{Limit: 2, Continue: "page2"}, page2, nil)
+		recorder.ListWithOptions(v1.ListOptions{Limit: 2, Continue: "page3"}, page3, nil)
+	}
+
+	output, err := runListTypesCmd(mockClient, "--chunk-size", "2", "-o", "url")
+	assert.NilError(t, err)
+	assert.Equal(t, strings.Count(output, "\n"), 5)
+	assert.Assert(t, util.ContainsAll(output, "kamelets/k1", "kamelets/k3", "kamelets/k5"))
+
+	output, err = runListTypesCmd(mockClient, "--chunk-size", "2", "-o", "yaml")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "name: k1", "name: k2", "name: k3", "name: k4", "name: k5"))
+	assert.Assert(t, util.ContainsNone(output, "continue:", "page2"))
+
+	// the deprecated --limit sets the chunk size as well
+	output, err = runListTypesCmd(mockClient, "--limit", "2", "-o", "url")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Flag --limit has been deprecated, use --chunk-size instead", "kamelets/k5"))
+
+	_, err = runListTypesCmd(mockClient, "--limit", "-1")
+	assert.Error(t, err, "invalid limit -1, must not be negative")
+
+	_, err = runListTypesCmd(mockClient, "--chunk-size", "-1")
+	assert.Error(t, err, "invalid chunk size -1, must not be negative")
+
+	_, err = runListTypesCmd(mockClient, "--chunk-size", "2", "--limit", "2")
+	assert.Error(t, err, "--chunk-size and --limit can not be used together")
+
+	recorder.Validate()
+}
+
+func TestListTypesChunkSizeOne(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	// every chunk holds a single Kamelet, the last one has no continue token
+	names := []string{"k1", "k2", "k3", "k4"}
+	for i, name := range names {
+		page := &camelkapis.KameletList{Items: []camelkapis.Kamelet{*createKamelet(name)}}
+		if i < len(names)-1 {
+			page.Continue = "after-" + name
+		}
+		opts := v1.ListOptions{Limit: 1}
+		if i > 0 {
+			opts.Continue = "after-" + names[i-1]
+		}
+		recorder.ListWithOptions(opts, page, nil)
+	}
+
+	output, err := runListTypesCmd(mockClient, "--chunk-size", "1", "--no-headers")
+	assert.NilError(t, err)
+	assert.Equal(t, strings.Count(output, "\n"), len(names))
+	assert.Assert(t, util.ContainsAll(output, "k1", "k2", "k3", "k4"))
+
+	recorder.Validate()
+}
+
+func TestListTypesGoTemplate(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet2 := createKamelet("k2")
+	kamelet2.Status.Phase = camelkapis.KameletPhaseError
+	kameletList := &camelkapis.KameletList{Items: []camelkapis.Kamelet{*createKamelet("k1"), *kamelet2}}
+	recorder.List(kameletList, nil)
+
+	output, err := runListTypesCmd(mockClient, "-o", `go-template={{range .items}}{{.metadata.name}}: {{.status.phase}}{{"\n"}}{{end}}`)
+	assert.NilError(t, err)
+	assert.Equal(t, output, "k1: Ready\nk2: Error\n")
+
+	recorder.Validate()
+}
+
+func TestListTypesCached(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	p := newCacheTestParams(t)
+
+	kamelet1 := createKamelet("k1")
+	kamelet1.Labels["team"] = "payments"
+	kamelet2 := createKamelet("k2")
+	kamelet2.Labels[kameletTypeLabel] = kameletTypeSink
+	recorder.ListWithOptions(v1.ListOptions{Limit: defaultListLimit},
+		&camelkapis.KameletList{Items: []camelkapis.Kamelet{*kamelet1, *kamelet2, *createKamelet("k3")}}, nil)
+
+	output, err := runListTypesCmdWithParams(p, mockClient, "--cached")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "k1", "k3", "Ready"))
+	assert.Assert(t, util.ContainsNone(output, "k2"))
+
+	output, err = runListTypesCmdWithParams(p, mockClient, "--cached", "--type", "sink", "-o", "name")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "k2"))
+	assert.Assert(t, util.ContainsNone(output, "k1", "k3"))
+
+	output, err = runListTypesCmdWithParams(p, mockClient, "--cached", "-l", "team=payments")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "k1"))
+	assert.Assert(t, util.ContainsNone(output, "k2", "k3"))
+
+	recorder.ListWithOptions(v1.ListOptions{Limit: defaultListLimit},
+		&camelkapis.KameletList{Items: []camelkapis.Kamelet{*createKamelet("k4")}}, nil)
+	output, err = runListTypesCmdWithParams(p, mockClient, "--refresh-cache")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "k4"))
+	assert.Assert(t, util.ContainsNone(output, "k1"))
+
+	_, err = runListTypesCmdWithParams(p, mockClient, "--cached", "--field-selector", "metadata.name=k1")
+	assert.Error(t, err, "--cached and --refresh-cache can not be combined with --field-selector")
+
+	recorder.Validate()
+}
+
+func TestListTypesSince(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet1 := createKamelet("k1")
+	kamelet2 := createKamelet("k2")
+	kamelet2.CreationTimestamp = v1.NewTime(time.Now().Add(-2 * time.Hour))
+	kameletList := &camelkapis.KameletList{Items: []camelkapis.Kamelet{*kamelet1, *kamelet2}}
+	recorder.List(kameletList, nil)
+	recorder.List(kameletList, nil)
+
+	output, err := runListTypesCmd(mockClient, "--since", "30m")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "k1"))
+	assert.Assert(t, util.ContainsNone(output, "k2"))
+
+	output, err = runListTypesCmd(mockClient, "--since", "1m", "--type", "sink")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "No Kamelets found in namespace current\n")
+
+	_, err = runListTypesCmd(mockClient, "--since", "-5m")
+	assert.Error(t, err, "invalid duration -5m0s for --since, must not be negative")
+
+	recorder.Validate()
+}
+
+func TestListTypesSortBy(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet1 := createKamelet("k1")
+	kamelet1.CreationTimestamp = v1.NewTime(time.Now().Add(-1 * time.Hour))
+	kamelet1.Status.Phase = camelkapis.KameletPhaseReady
+	kamelet2 := createKamelet("k2")
+	kamelet2.CreationTimestamp = v1.NewTime(time.Now().Add(-3 * time.Hour))
+	kamelet2.Status.Phase = camelkapis.KameletPhaseError
+	kamelet3 := createKamelet("k3")
+	kamelet3.CreationTimestamp = v1.NewTime(time.Now().Add(-2 * time.Hour))
+	kamelet3.Status.Phase = camelkapis.KameletPhaseReady
+	kamelet4 := createKamelet("k0")
+	kamelet4.CreationTimestamp = v1.NewTime(time.Now())
+	kamelet4.Status.Phase = camelkapis.KameletPhaseError
+	listNames := func(options ...string) string {
+		recorder.List(&camelkapis.KameletList{Items: []camelkapis.Kamelet{*kamelet1, *kamelet2, *kamelet3, *kamelet4}}, nil)
+		output, err := runListTypesCmd(mockClient, append(options, "-o", "custom-columns=NAME:.metadata.name", "--no-headers")...)
+		assert.NilError(t, err)
+		return strings.Join(strings.Fields(output), " ")
+	}
+
+	assert.Equal(t, listNames(), "k1 k2 k3 k0")
+	assert.Equal(t, listNames("--reverse"), "k0 k3 k2 k1")
+	assert.Equal(t, listNames("--sort-by", "name"), "k0 k1 k2 k3")
+	assert.Equal(t, listNames("--sort-by", "name", "--reverse"), "k3 k2 k1 k0")
+	assert.Equal(t, listNames("--sort-by", "phase"), "k0 k2 k1 k3")
+	assert.Equal(t, listNames("--sort-by", "phase", "--reverse"), "k3 k1 k2 k0")
+	assert.Equal(t, listNames("--sort-by", "age"), "k2 k3 k1 k0")
+	assert.Equal(t, listNames("--sort-by", "age", "--reverse"), "k0 k1 k3 k2")
+
+	// the table is sorted as well
+	recorder.List(&camelkapis.KameletList{Items: []camelkapis.Kamelet{*kamelet1, *kamelet2, *kamelet3, *kamelet4}}, nil)
+	output, err := runListTypesCmd(mockClient, "--sort-by", "age", "--reverse", "--no-headers")
+	assert.NilError(t, err)
+	outputLines := strings.Split(output, "\n")
+	assert.Assert(t, strings.HasPrefix(outputLines[0], "k0"))
+	assert.Assert(t, strings.HasPrefix(outputLines[3], "k2"))
+
+	// yaml output keeps the order of the server
+	recorder.List(&camelkapis.KameletList{Items: []camelkapis.Kamelet{*kamelet1, *kamelet2, *kamelet3, *kamelet4}}, nil)
+	output, err = runListTypesCmd(mockClient, "--sort-by", "name", "--reverse", "-o", "yaml")
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Index(output, "name: k1") < strings.Index(output, "name: k0"))
+
+	_, err = runListTypesCmd(mockClient, "--sort-by", "provider")
+	assert.Error(t, err, "invalid sort order 'provider', must be one of: name, phase, age")
+
+	recorder.Validate()
+}
+
+func TestListTypesProvider(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet1 := createKamelet("k1")
+	kamelet1.Annotations = map[string]string{kameletProviderAnnotation: "Apache Software Foundation"}
+	kamelet2 := createKamelet("k2")
+	kamelet2.Annotations = map[string]string{kameletProviderAnnotation: "Apache Custom"}
+	kamelet3 := createKamelet("k3")
+	kameletList := &camelkapis.KameletList{Items: []camelkapis.Kamelet{*kamelet1, *kamelet2, *kamelet3}}
+	recorder.List(kameletList, nil)
+	recorder.List(kameletList, nil)
+	recorder.List(kameletList, nil)
+
+	output, err := runListTypesCmd(mockClient, "--provider", "apache software foundation", "--no-headers")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "k1"))
+	assert.Assert(t, util.ContainsNone(output, "k2", "k3"))
+
+	// a part of the provider name is not sufficient for an exact match
+	output, err = runListTypesCmd(mockClient, "--provider", "apache")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "No Kamelets found in namespace current\n")
+
+	output, err = runListTypesCmd(mockClient, "--provider-contains", "APACHE", "--no-headers")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "k1", "k2"))
+	assert.Assert(t, util.ContainsNone(output, "k3"))
+
+	_, err = runListTypesCmd(mockClient, "--provider", "a", "--provider-contains", "b")
+	assert.Error(t, err, "--provider and --provider-contains can not be used together")
+
+	recorder.Validate()
+}
+
+func TestListTypesPhase(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	ready := createKamelet("ready-source")
+	failed := createKamelet("failed-source")
+	failed.Status.Phase = camelkapis.KameletPhaseError
+	pending := createKamelet("pending-source")
+	pending.Status.Phase = camelkapis.KameletPhaseNone
+	kameletList := &camelkapis.KameletList{Items: []camelkapis.Kamelet{*ready, *failed, *pending}}
+	recorder.List(kameletList, nil)
+	recorder.List(kameletList, nil)
+	recorder.List(kameletList, nil)
+
+	output, err := runListTypesCmd(mockClient, "--phase", "error", "--no-headers")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "failed-source"))
+	assert.Assert(t, util.ContainsNone(output, "ready-source", "pending-source"))
+
+	// an empty phase matches the Kamelets without phase
+	output, err = runListTypesCmd(mockClient, "--phase", "Error", "--phase", "", "--no-headers")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "failed-source", "pending-source"))
+	assert.Assert(t, util.ContainsNone(output, "ready-source"))
+
+	output, err = runListTypesCmd(mockClient, "--phase", "Ready", "--search", "failed")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "No Kamelets found in namespace current\n")
+
+	recorder.Validate()
+}
+
+func TestListTypesSearch(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	telegram := createKamelet("telegram-source")
+	telegram.Spec.Definition.Description = "Receive all messages that people send to your Telegram bot"
+	s3 := createKamelet("aws-s3-source")
+	s3.Spec.Definition.Title = "AWS S3 Source"
+	noDefinition := createKamelet("timer-source")
+	noDefinition.Spec.Definition = nil
+	sink := createKamelet("telegram-sink")
+	sink.Labels[kameletTypeLabel] = kameletTypeSink
+	kameletList := &camelkapis.KameletList{Items: []camelkapis.Kamelet{*telegram, *s3, *noDefinition, *sink}}
+	recorder.List(kameletList, nil)
+	recorder.List(kameletList, nil)
+	recorder.List(kameletList, nil)
+	recorder.List(kameletList, nil)
+
+	// the description matches ignoring case, the sink is filtered by type
+	output, err := runListTypesCmd(mockClient, "--search", "TELEGRAM BOT", "--no-headers")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "telegram-source"))
+	assert.Assert(t, util.ContainsNone(output, "aws-s3-source", "timer-source", "telegram-sink"))
+
+	output, err = runListTypesCmd(mockClient, "--search", "s3 source", "--no-headers")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "aws-s3-source"))
+	assert.Assert(t, util.ContainsNone(output, "telegram-source", "timer-source"))
+
+	output, err = runListTypesCmd(mockClient, "--search", "telegram", "--type", "sink", "-l", "team=payments", "--no-headers")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "telegram-sink"))
+	assert.Assert(t, util.ContainsNone(output, "telegram-source"))
+
+	output, err = runListTypesCmd(mockClient, "--search", "kafka")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "No Kamelets found in namespace current\n")
+
+	_, err = runListTypesCmd(mockClient, "--search", "telegram", "--cached")
+	assert.Error(t, err, "--cached and --refresh-cache can not be combined with --search as cached Kamelets do not hold their title and description")
+
+	recorder.Validate()
+}
+
+func TestListTypesShowProps(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet1 := createKamelet("k1")
+	addKameletProperty(kamelet1, "period", "integer", "The interval", true)
+	addKameletProperty(kamelet1, "message", "string", "The message", false)
+	addKameletProperty(kamelet1, "enabled", "boolean", "Enabled flag", false)
+	kamelet2 := createKamelet("k2")
+	kamelet2.Spec.Definition = nil
+	kameletList := &camelkapis.KameletList{Items: []camelkapis.Kamelet{*kamelet1, *kamelet2}}
+	recorder.List(kameletList, nil)
+	recorder.List(kameletList, nil)
+	recorder.List(kameletList, nil)
+
+	output, err := runListTypesCmd(mockClient)
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsNone(output, "PROPS", "1/3"))
+
+	output, err = runListTypesCmd(mockClient, "--show-props")
+	assert.NilError(t, err)
+	outputLines := strings.Split(output, "\n")
+	assert.Check(t, util.ContainsAll(outputLines[0], "NAME", "PHASE", "PROVIDER", "PROPS", "AGE"))
+	assert.Check(t, util.ContainsAll(outputLines[1], "k1", "Ready", "1/3", "True"))
+	assert.Check(t, util.ContainsAll(outputLines[2], "k2", "Ready", "0/0", "True"))
+
+	output, err = runListTypesCmd(mockClient, "--show-props", "-o", "yaml")
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsNone(output, "PROPS", "1/3"))
+
+	_, err = runListTypesCmd(mockClient, "--show-props", "--cached")
+	assert.ErrorContains(t, err, "can not be combined with --show-props")
+
+	recorder.Validate()
+}
+
+func TestListTypesWide(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet1 := createKamelet("k1")
+	kamelet1.Annotations = map[string]string{kameletSupportLevelAnnotation: "Stable"}
+	kamelet2 := createKamelet("k2")
+	kamelet2.Spec.Definition.Title = "A very long title of a Kamelet that does not fit into the table"
+	kameletList := &camelkapis.KameletList{Items: []camelkapis.Kamelet{*kamelet1, *kamelet2}}
+	recorder.List(kameletList, nil)
+	recorder.List(kameletList, nil)
+	recorder.List(kameletList, nil)
+
+	output, err := runListTypesCmd(mockClient)
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsNone(output, "TITLE", "SUPPORT LEVEL", "Stable"))
+
+	output, err = runListTypesCmd(mockClient, "-o", "wide")
+	assert.NilError(t, err)
+	outputLines := strings.Split(output, "\n")
+	assert.Check(t, util.ContainsAll(outputLines[0], "NAME", "TITLE", "PHASE", "PROVIDER", "SUPPORT LEVEL", "AGE", "CONDITIONS"))
+	assert.Check(t, util.ContainsNone(outputLines[0], "PROPS"))
+	assert.Check(t, util.ContainsAll(outputLines[1], "k1", "Kamelet k1", "Ready", "Stable"))
+	assert.Check(t, util.ContainsAll(outputLines[2], "k2", "A very long title of a Kamelet that", " ...", "Ready"))
+	assert.Check(t, util.ContainsNone(outputLines[2], "does not fit"))
+
+	output, err = runListTypesCmd(mockClient, "-o", "wide", "--show-props")
+	assert.NilError(t, err)
+	outputLines = strings.Split(output, "\n")
+	assert.Check(t, util.ContainsAll(outputLines[0], "TITLE", "SUPPORT LEVEL", "PROPS"))
+	assert.Check(t, util.ContainsAll(outputLines[1], "k1", "Stable", "0/0"))
+
+	_, err = runListTypesCmd(mockClient, "-o", "wide", "--cached")
+	assert.Error(t, err, "--cached and --refresh-cache can not be combined with -o wide as cached Kamelets do not hold their title")
+	_, err = runListTypesCmd(mockClient, "-o", "wide", "--refresh-cache")
+	assert.ErrorContains(t, err, "can not be combined with -o wide")
+
+	recorder.Validate()
+}
+
+func TestListTypesCount(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet3 := createKamelet("k3")
+	kamelet3.Labels[kameletTypeLabel] = kameletTypeSink
+	kameletList := &camelkapis.KameletList{Items: []camelkapis.Kamelet{*createKamelet("k1"), *createKamelet("k2"), *kamelet3}}
+	recorder.List(kameletList, nil)
+	recorder.List(kameletList, nil)
+	recorder.List(&camelkapis.KameletList{}, nil)
+
+	output, err := runListTypesCmd(mockClient, "--count")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "2\n")
+
+	output, err = runListTypesCmd(mockClient, "--count", "--type", "sink")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "1\n")
+
+	output, err = runListTypesCmd(mockClient, "--count")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "0\n")
+
+	_, err = runListTypesCmd(mockClient, "--count", "-o", "yaml")
+	assert.Error(t, err, "--count can not be combined with --output")
+
+	recorder.Validate()
+}
+
+func TestListTypesCountAllNamespaces(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kameletList := &camelkapis.KameletList{Items: []camelkapis.Kamelet{
+		*createKameletInNamespace("k1", "team-b"),
+		*createKameletInNamespace("k2", "team-a"),
+		*createKameletInNamespace("k3", "team-b"),
+	}}
+	recorder.List(kameletList, nil)
+
+	output, err := runListTypesCmd(mockClient, "--count", "--all-namespaces")
+	assert.NilError(t, err)
+	outputLines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	assert.Equal(t, len(outputLines), 4)
+	assert.Check(t, util.ContainsAll(outputLines[0], "NAMESPACE", "COUNT"))
+	assert.Check(t, util.ContainsAll(outputLines[1], "team-a", "1"))
+	assert.Check(t, util.ContainsAll(outputLines[2], "team-b", "2"))
+	assert.Check(t, util.ContainsAll(outputLines[3], "Total", "3"))
+
+	recorder.Validate()
+}
+
 func TestListTypesEmpty(t *testing.T) {
 	mockClient := client.NewMockKameletClient(t)
 	recorder := mockClient.Recorder()
@@ -72,7 +625,107 @@ func TestListTypesEmpty(t *testing.T) {
 	output, err := runListTypesCmd(mockClient)
 	assert.NilError(t, err)
 
-	assert.Assert(t, util.ContainsAll(output, "No", "resources", "found"))
+	assert.Equal(t, output, "No Kamelets found in namespace current\n")
+
+	recorder.Validate()
+}
+
+func TestListTypesFilterByType(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet1 := createKamelet("k1")
+	kamelet2 := createKamelet("k2")
+	kamelet2.Labels[kameletTypeLabel] = kameletTypeSink
+	kamelet2.Annotations = map[string]string{
+		kameletProviderAnnotation: "Apache Software Foundation",
+	}
+	kamelet3 := createKamelet("k3")
+	kameletList := &camelkapis.KameletList{Items: []camelkapis.Kamelet{*kamelet1, *kamelet2, *kamelet3}}
+	recorder.List(kameletList, nil)
+	recorder.List(kameletList, nil)
+
+	output, err := runListTypesCmd(mockClient)
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "k1", "k3"))
+	assert.Assert(t, util.ContainsNone(output, "k2"))
+
+	output, err = runListTypesCmd(mockClient, "--type", "sink")
+	assert.NilError(t, err)
+	outputLines := strings.Split(output, "\n")
+	assert.Check(t, util.ContainsAll(outputLines[0], "NAME", "PHASE", "PROVIDER"))
+	assert.Check(t, util.ContainsAll(outputLines[1], "k2", "Ready", "Apache Software Foundation"))
+	assert.Assert(t, util.ContainsNone(output, "k1", "k3"))
+
+	recorder.Validate()
+}
+
+func TestListTypesFilterByTypeEmpty(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kameletList := &camelkapis.KameletList{Items: []camelkapis.Kamelet{*createKamelet("k1")}}
+	recorder.List(kameletList, nil)
+
+	output, err := runListTypesCmd(mockClient, "--type", "action")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "No Kamelets found in namespace current\n")
+
+	recorder.Validate()
+}
+
+func TestListTypesLabelSelector(t *testing.T) {
+	kamelet1 := createKameletInNamespace("k1", "current")
+	kamelet1.Labels["team"] = "payments"
+	kamelet2 := createKameletInNamespace("k2", "current")
+	kamelet2.Labels["team"] = "payments"
+	kamelet2.Labels[kameletTypeLabel] = kameletTypeSink
+	kamelet3 := createKameletInNamespace("k3", "current")
+	kamelet3.Labels[kameletTypeLabel] = kameletTypeSink
+	fakeClient := clienttesting.NewFakeKameletClient(t, kamelet1, kamelet2, kamelet3)
+
+	output, err := runListTypesCmd(fakeClient, "-l", "team=payments", "--type", "sink")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "k2"))
+	assert.Assert(t, util.ContainsNone(output, "k1", "k3"))
+}
+
+func TestListTypesInvalidLabelSelector(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	_, err := runListTypesCmd(mockClient, "--selector", "team in (payments")
+	assert.ErrorContains(t, err, "invalid label selector 'team in (payments'")
+
+	recorder.Validate()
+}
+
+func TestListTypesFieldSelector(t *testing.T) {
+	kamelet1 := createKameletInNamespace("k1", "current")
+	kamelet1.Labels["team"] = "payments"
+	kamelet2 := createKameletInNamespace("k2", "current")
+	kamelet2.Labels["team"] = "payments"
+	fakeClient := clienttesting.NewFakeKameletClient(t, kamelet1, kamelet2)
+
+	output, err := runListTypesCmd(fakeClient, "-l", "team=payments", "--field-selector", "metadata.name=k1")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "k1"))
+	assert.Assert(t, util.ContainsNone(output, "k2"))
+
+	_, err = runListTypesCmd(fakeClient, "--field-selector", "status.phase=Ready")
+	assert.ErrorContains(t, err, "unable to list Kamelets with field selector 'status.phase=Ready': ")
+	assert.ErrorContains(t, err, "field label not supported: status.phase")
+
+	_, err = runListTypesCmd(fakeClient, "--field-selector", "metadata.name")
+	assert.ErrorContains(t, err, "invalid field selector 'metadata.name'")
+}
+
+func TestListTypesInvalidType(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	_, err := runListTypesCmd(mockClient, "--type", "foo")
+	assert.Error(t, err, "invalid Kamelet type 'foo', must be one of: source, sink, action")
 
 	recorder.Validate()
 }
@@ -128,16 +781,52 @@ func TestListTypesAllNamespace(t *testing.T) {
 	recorder.Validate()
 }
 
-func runListTypesCmd(c *client.MockKameletClient, options ...string) (string, error) {
+func TestListTypesAllNamespaceWithNamespace(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	_, err := runListTypesCmd(mockClient, "--all-namespaces", "--namespace", "default1")
+	assert.Error(t, err, "--namespace and --all-namespaces can not be used together")
+
+	recorder.Validate()
+}
+
+func TestListTypesAllNamespaceYAMLOutput(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet1 := createKameletInNamespace("k1", "default1")
+	kamelet1.TypeMeta = v1.TypeMeta{}
+	kamelet2 := createKameletInNamespace("k2", "default2")
+	kamelet2.TypeMeta = v1.TypeMeta{}
+	kameletList := &camelkapis.KameletList{Items: []camelkapis.Kamelet{*kamelet1, *kamelet2}}
+	recorder.List(kameletList, nil)
+
+	output, err := runListTypesCmd(mockClient, "-A", "-o", "yaml")
+	assert.NilError(t, err)
+
+	assert.Assert(t, strings.HasPrefix(output, "apiVersion: camel.apache.org/v1alpha1\nitems:\n"))
+	assert.Assert(t, strings.HasSuffix(output, "kind: KameletList\n"))
+	assert.Equal(t, strings.Count(output, "kind: Kamelet\n"), 2)
+	assert.Assert(t, util.ContainsAll(output, "namespace: default1", "namespace: default2"))
+
+	recorder.Validate()
+}
+
+func runListTypesCmd(c KameletClient, options ...string) (string, error) {
 	p := KameletPluginParams{
 		KnParams: &commands.KnParams{},
 		Context:  context.TODO(),
-		NewKameletClient: func() (camelkv1alpha1.CamelV1alpha1Interface, error) {
-			return c, nil
-		},
+	}
+	return runListTypesCmdWithParams(&p, c, options...)
+}
+
+func runListTypesCmdWithParams(p *KameletPluginParams, c KameletClient, options ...string) (string, error) {
+	p.NewKameletClient = func() (KameletClient, error) {
+		return c, nil
 	}
 
-	listCmd, _, output := commands.CreateSourcesTestKnCommand(NewListTypesCommand(&p), p.KnParams)
+	listCmd, _, output := commands.CreateSourcesTestKnCommand(NewListTypesCommand(p), p.KnParams)
 
 	args := []string{"list-types"}
 	args = append(args, options...)
@@ -146,3 +835,51 @@ func runListTypesCmd(c *client.MockKameletClient, options ...string) (string, er
 
 	return output.String(), err
 }
+
+func TestFilterKamelets(t *testing.T) {
+	kameletList := &camelkapis.KameletList{Items: []camelkapis.Kamelet{*createKamelet("k1"), *createKamelet("k2"), *createKamelet("k3")}}
+	kameletList.ResourceVersion = "42"
+
+	filtered := filterKamelets(kameletList, func(kamelet *camelkapis.Kamelet) bool { return kamelet.Name != "k2" })
+	assert.Equal(t, len(filtered.Items), 2)
+	assert.Equal(t, filtered.Items[0].Name, "k1")
+	assert.Equal(t, filtered.Items[1].Name, "k3")
+	assert.Equal(t, filtered.ResourceVersion, "42")
+	// the given list is left untouched
+	assert.Equal(t, len(kameletList.Items), 3)
+}
+
+func TestKameletPredicates(t *testing.T) {
+	kamelet := createKamelet("timer")
+	kamelet.Labels["team"] = "a"
+	kamelet.Annotations = map[string]string{kameletProviderAnnotation: "Apache Software Foundation"}
+	kamelet.Spec.Definition.Description = "Produces periodic events"
+	kamelet.CreationTimestamp = v1.NewTime(time.Now().Add(-time.Hour))
+
+	selector, err := labels.Parse("team=a")
+	assert.NilError(t, err)
+	assert.Assert(t, kameletHasLabels(selector)(kamelet))
+	selector, err = labels.Parse("team!=a")
+	assert.NilError(t, err)
+	assert.Assert(t, !kameletHasLabels(selector)(kamelet))
+
+	assert.Assert(t, kameletCreatedAfter(time.Now().Add(-2*time.Hour))(kamelet))
+	assert.Assert(t, !kameletCreatedAfter(time.Now().Add(-time.Minute))(kamelet))
+
+	assert.Assert(t, kameletOfProvider("apache software foundation", false)(kamelet))
+	assert.Assert(t, !kameletOfProvider("apache", false)(kamelet))
+	assert.Assert(t, kameletOfProvider("APACHE", true)(kamelet))
+	assert.Assert(t, !kameletOfProvider("redhat", true)(kamelet))
+
+	assert.Assert(t, kameletContains("TIM")(kamelet))
+	assert.Assert(t, kameletContains("periodic")(kamelet))
+	assert.Assert(t, kameletContains("kamelet timer")(kamelet))
+	assert.Assert(t, !kameletContains("cron")(kamelet))
+
+	assert.Assert(t, kameletInPhase([]string{"error", "ready"})(kamelet))
+	assert.Assert(t, !kameletInPhase([]string{"Error"})(kamelet))
+	assert.Assert(t, !kameletInPhase(nil)(kamelet))
+
+	assert.Assert(t, kameletOfType(kameletTypeSource)(kamelet))
+	assert.Assert(t, !kameletOfType(kameletTypeSink)(kamelet))
+}
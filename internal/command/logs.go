@@ -0,0 +1,116 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// integrationLabel is set by Camel K on the pods of an integration, the integration is named after its binding
+	integrationLabel = "camel.apache.org/integration"
+	// integrationContainer is the name of the container running the integration in its pod
+	integrationContainer = "integration"
+)
+
+// streamIntegrationLogs streams the logs of the pod running the integration of given binding to out until the
+// plugin context is cancelled, e.g. with Ctrl-C, or the pod terminates. The newest running pod is followed when
+// the integration is scaled.
+func streamIntegrationLogs(p *KameletPluginParams, kind string, binding *v1alpha1.KameletBinding, out io.Writer,
+	messageOut io.Writer) error {
+	podClient, err := p.NewPodClient()
+	if err != nil {
+		return err
+	}
+
+	var pods *corev1.PodList
+	err = p.retryOnTransientError(func(ctx context.Context) (err error) {
+		pods, err = podClient.Pods(binding.Namespace).List(ctx, v1.ListOptions{LabelSelector: integrationLabel + "=" + binding.Name})
+		return err
+	})
+	if err != nil {
+		return integrationLogsError(err, kind, binding)
+	}
+	pod := newestRunningPod(pods.Items)
+	if pod == nil {
+		return fmt.Errorf("unable to stream the logs of %s '%s', no running pod found for its integration in namespace '%s'",
+			kind, binding.Name, binding.Namespace)
+	}
+
+	fmt.Fprintf(messageOut, "Streaming the logs of pod '%s', press Ctrl-C to stop.\n", pod.Name)
+	// the stream lasts until interrupted, so it is not bounded by the request timeout
+	stream, err := podClient.Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: logsContainer(pod),
+		Follow:    true,
+	}).Stream(p.Context)
+	if err != nil {
+		if IsInterrupted(err) {
+			return nil
+		}
+		return integrationLogsError(err, kind, binding)
+	}
+	defer stream.Close()
+
+	_, err = io.Copy(out, stream)
+	if err != nil && p.Context.Err() == nil {
+		return fmt.Errorf("streaming the logs of pod '%s' failed: %w", pod.Name, err)
+	}
+	return nil
+}
+
+// integrationLogsError explains given error of a failed pod or log request, which usually means that the log
+// permissions are missing
+func integrationLogsError(err error, kind string, binding *v1alpha1.KameletBinding) error {
+	if apierrors.IsForbidden(err) {
+		return fmt.Errorf("streaming the logs of %s '%s' requires permission to list pods and to get pods/log in "+
+			"namespace '%s': %w", kind, binding.Name, binding.Namespace, err)
+	}
+	return fmt.Errorf("unable to stream the logs of %s '%s': %w", kind, binding.Name, err)
+}
+
+// newestRunningPod returns the most recently created running pod of given pods, nil if none is running
+func newestRunningPod(pods []corev1.Pod) *corev1.Pod {
+	var newest *corev1.Pod
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		if newest == nil || newest.CreationTimestamp.Before(&pod.CreationTimestamp) {
+			newest = pod
+		}
+	}
+	return newest
+}
+
+// logsContainer returns the container of given pod running the integration. No container is selected if there
+// is none of that name, which is fine for pods running a single container.
+func logsContainer(pod *corev1.Pod) string {
+	for _, container := range pod.Spec.Containers {
+		if container.Name == integrationContainer {
+			return container.Name
+		}
+	}
+	return ""
+}
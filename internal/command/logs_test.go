@@ -0,0 +1,183 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	camelkapis "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes/scheme"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/util/mock"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestNewestRunningPod(t *testing.T) {
+	now := time.Now()
+	pods := []corev1.Pod{
+		newIntegrationPod("old", corev1.PodRunning, now.Add(-time.Hour)),
+		newIntegrationPod("new", corev1.PodRunning, now),
+		newIntegrationPod("pending", corev1.PodPending, now.Add(time.Minute)),
+	}
+	assert.Equal(t, newestRunningPod(pods).Name, "new")
+	assert.Assert(t, newestRunningPod(pods[2:]) == nil)
+}
+
+func TestBindLog(t *testing.T) {
+	var logRequest *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/namespaces/current/pods":
+			// the handler runs outside of the test goroutine, so failures must not stop the test
+			assert.Check(t, r.URL.Query().Get("labelSelector") == "camel.apache.org/integration=k1-binding")
+			writePodList(t, w, newIntegrationPod("k1-binding-7d4b9", corev1.PodRunning, time.Now()))
+		case "/api/v1/namespaces/current/pods/k1-binding-7d4b9/log":
+			logRequest = r
+			_, _ = w.Write([]byte("timer fired\ntimer fired\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+	recorder.Get(createKamelet("k1"), nil)
+	bindingRecorder.Create(mock.Any(), nil)
+	bindingRecorder.Watch(newReadyBindingWatcher("k1", "k1-binding"), nil)
+
+	output, err := runBindCmdWithParams(newLogTestParams(mockClient, server.URL), "", "k1", "--name", "k1-binding",
+		"--sink", "broker:default", "-L")
+	assert.NilError(t, err)
+	assert.Assert(t, logRequest != nil)
+	assert.Equal(t, logRequest.URL.Query().Get("follow"), "true")
+	assert.Equal(t, logRequest.URL.Query().Get("container"), integrationContainer)
+	lines := strings.Split(output, "\n")
+	streaming := indexOfLine(lines, "Streaming the logs of pod 'k1-binding-7d4b9', press Ctrl-C to stop.")
+	assert.Assert(t, streaming >= 0, output)
+	assert.DeepEqual(t, lines[streaming+1:], []string{"timer fired", "timer fired", ""})
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindLogForbidden(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Forbidden","code":403,` +
+			`"message":"pods is forbidden: User \"dev\" cannot list resource \"pods\" in the namespace \"current\""}`))
+	}))
+	defer server.Close()
+
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+	recorder.Get(createKamelet("k1"), nil)
+	bindingRecorder.Create(mock.Any(), nil)
+	bindingRecorder.Watch(newReadyBindingWatcher("k1", "k1-binding"), nil)
+
+	_, err := runBindCmdWithParams(newLogTestParams(mockClient, server.URL), "", "k1", "--name", "k1-binding",
+		"--sink", "broker:default", "--log")
+	assert.ErrorContains(t, err, "streaming the logs of KameletBinding 'k1-binding' requires permission to list pods "+
+		"and to get pods/log in namespace 'current': pods is forbidden")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestBindErrorCaseLog(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+
+	_, err := runBindCmd(mockClient, "k1", "k2", "--sink", "broker:default", "--log")
+	assert.Error(t, err, "--log can only be used when binding a single Kamelet")
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--log", "--no-wait")
+	assert.Error(t, err, "--log streams the logs once the binding is ready, it can not be combined with --no-wait")
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--log", "--dry-run=server")
+	assert.Error(t, err, "--log can not be combined with --dry-run as no integration is started")
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--log", "-o", "name")
+	assert.Error(t, err, "--log can not be combined with --output as the logs are printed to stdout")
+
+	mockClient.Recorder().Validate()
+}
+
+// newLogTestParams returns the params of a bind command using the pods served by given test server
+func newLogTestParams(c *client.MockKameletClient, host string) *KameletPluginParams {
+	return &KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewKameletClient: func() (KameletClient, error) {
+			return c, nil
+		},
+		NewKameletBindingClient: mockBindingClient(c),
+		NewDiscoveryClient: func() (discovery.ServerResourcesInterface, error) {
+			return newKameletBindingDiscovery(), nil
+		},
+		NewPodClient: func() (corev1client.PodsGetter, error) {
+			return corev1client.NewForConfig(&rest.Config{Host: host})
+		},
+	}
+}
+
+func newReadyBindingWatcher(kamelet string, name string) watch.Interface {
+	ready := createKameletBindingFor(kamelet, name)
+	ready.Status.Phase = camelkapis.KameletBindingPhaseReady
+	ready.Status.Conditions = []camelkapis.KameletBindingCondition{
+		{Type: camelkapis.KameletBindingConditionReady, Status: corev1.ConditionTrue},
+	}
+	watcher := watch.NewFakeWithChanSize(1, false)
+	watcher.Modify(ready)
+	return watcher
+}
+
+func newIntegrationPod(name string, phase corev1.PodPhase, created time.Time) corev1.Pod {
+	return corev1.Pod{
+		TypeMeta: v1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: v1.ObjectMeta{
+			Name:              name,
+			Namespace:         "current",
+			CreationTimestamp: v1.NewTime(created),
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: integrationContainer}, {Name: "istio-proxy"}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func writePodList(t *testing.T, w http.ResponseWriter, pods ...corev1.Pod) {
+	list := &corev1.PodList{TypeMeta: v1.TypeMeta{APIVersion: "v1", Kind: "PodList"}, Items: pods}
+	data, err := runtime.Encode(scheme.Codecs.LegacyCodec(corev1.SchemeGroupVersion), list)
+	assert.Check(t, err)
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
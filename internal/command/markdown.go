@@ -0,0 +1,58 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	markdownHeading    = regexp.MustCompile(`^#{1,6}\s+`)
+	markdownBullet     = regexp.MustCompile(`^(\s*)[-*+]\s+`)
+	markdownLink       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	markdownBold       = regexp.MustCompile(`(\*\*|__)([^*_]+)(\*\*|__)`)
+	markdownInlineCode = regexp.MustCompile("`([^`]+)`")
+)
+
+// renderMarkdown lightly formats common markdown for terminal output. Headings and emphasis markers are stripped,
+// bullet points are replaced with a bullet sign, links are written as text followed by the URL and code spans
+// lose their backticks. Code fences are dropped while their content is kept as is.
+func renderMarkdown(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	rendered := make([]string, 0, len(lines))
+	inCodeBlock := false
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			rendered = append(rendered, line)
+			continue
+		}
+
+		line = markdownHeading.ReplaceAllString(line, "")
+		line = markdownBullet.ReplaceAllString(line, "${1}• ")
+		line = markdownLink.ReplaceAllString(line, "$1 ($2)")
+		line = markdownBold.ReplaceAllString(line, "$2")
+		line = markdownInlineCode.ReplaceAllString(line, "$1")
+		rendered = append(rendered, line)
+	}
+	return strings.Join(rendered, "\n")
+}
@@ -0,0 +1,39 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	for _, tc := range []struct {
+		markdown string
+		expected string
+	}{
+		{markdown: "Produces periodic events", expected: "Produces periodic events"},
+		{markdown: "Set the `period` in **milliseconds**", expected: "Set the period in milliseconds"},
+		{markdown: "See [the docs](https://camel.apache.org/)", expected: "See the docs (https://camel.apache.org/)"},
+		{markdown: "## Usage\n\nOptions:\n- one\n  * nested\n+ two\n", expected: "Usage\n\nOptions:\n• one\n  • nested\n• two"},
+		{markdown: "Example:\n```\nkey: `value`\n- item\n```\ndone", expected: "Example:\nkey: `value`\n- item\ndone"},
+		{markdown: "2 * 3 - 1", expected: "2 * 3 - 1"},
+	} {
+		assert.Equal(t, renderMarkdown(tc.markdown), tc.expected)
+	}
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// parseLabels parses given key=value pairs into labels, validating keys and values per Kubernetes naming rules.
+// The label marking bindings managed by this plugin can not be given.
+func parseLabels(entries []string) (map[string]string, error) {
+	labels, err := parseMetadataEntries("label", entries)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range labels {
+		if key == managedByLabel {
+			return nil, fmt.Errorf("invalid label '%s', the label is set by %s", key, managedByValue)
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid value '%s' for label '%s': %s", value, key, strings.Join(errs, "; "))
+		}
+	}
+	return labels, nil
+}
+
+// parseAnnotations parses given key=value pairs into annotations, validating keys per Kubernetes naming rules
+func parseAnnotations(entries []string) (map[string]string, error) {
+	return parseMetadataEntries("annotation", entries)
+}
+
+// parseMetadataEntries parses given key=value pairs of given kind, the keys must be qualified names
+func parseMetadataEntries(kind string, entries []string) (map[string]string, error) {
+	result := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid %s '%s', expected format key=value", kind, entry)
+		}
+		if errs := validation.IsQualifiedName(parts[0]); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid %s key '%s': %s", kind, parts[0], strings.Join(errs, "; "))
+		}
+		result[parts[0]] = parts[1]
+	}
+	return result, nil
+}
+
+// mergeMetadata returns given existing labels or annotations with given additions applied
+func mergeMetadata(existing map[string]string, additions map[string]string) map[string]string {
+	if len(additions) == 0 {
+		return existing
+	}
+	merged := make(map[string]string, len(existing)+len(additions))
+	for key, value := range existing {
+		merged[key] = value
+	}
+	for key, value := range additions {
+		merged[key] = value
+	}
+	return merged
+}
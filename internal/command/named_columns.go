@@ -0,0 +1,153 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/client/pkg/kn/commands"
+	hprinters "knative.dev/client/pkg/printers"
+)
+
+// namedColumn is a built-in column of the Kamelet table that can be selected by name with list-types --columns
+type namedColumn struct {
+	name        string
+	header      string
+	description string
+	// fromSpec marks the columns computed from the spec, which cached Kamelets do not hold
+	fromSpec bool
+	value    func(kamelet *camelkv1alpha1.Kamelet) interface{}
+}
+
+// namedColumns is the registry of the columns selectable with list-types --columns, in the order they are documented
+var namedColumns = []namedColumn{
+	{name: "namespace", header: "Namespace", description: "Namespace of the Kamelet instance",
+		value: func(kamelet *camelkv1alpha1.Kamelet) interface{} { return kamelet.Namespace }},
+	{name: "name", header: "Name", description: "Name of the Kamelet instance",
+		value: func(kamelet *camelkv1alpha1.Kamelet) interface{} { return kamelet.Name }},
+	{name: "type", header: "Type", description: "Type of the Kamelet instance",
+		value: func(kamelet *camelkv1alpha1.Kamelet) interface{} { return kamelet.Labels[kameletTypeLabel] }},
+	{name: "title", header: "Title", description: "Title of the Kamelet instance", fromSpec: true,
+		value: func(kamelet *camelkv1alpha1.Kamelet) interface{} { return kameletTitle(kamelet) }},
+	{name: "phase", header: "Phase", description: "Phase of the Kamelet instance",
+		value: func(kamelet *camelkv1alpha1.Kamelet) interface{} { return kamelet.Status.Phase }},
+	{name: "provider", header: "Provider", description: "Provider of the Kamelet instance",
+		value: func(kamelet *camelkv1alpha1.Kamelet) interface{} { return extractKameletProvider(kamelet) }},
+	{name: "support-level", header: "Support Level", description: "Support level of the Kamelet instance",
+		value: func(kamelet *camelkv1alpha1.Kamelet) interface{} {
+			return kamelet.Annotations[kameletSupportLevelAnnotation]
+		}},
+	{name: "props", header: "Props", description: "Required and total properties of the Kamelet instance", fromSpec: true,
+		value: func(kamelet *camelkv1alpha1.Kamelet) interface{} { return propertyCounts(kamelet) }},
+	{name: "age", header: "Age", description: "Age of the Kamelet instance",
+		value: func(kamelet *camelkv1alpha1.Kamelet) interface{} {
+			return commands.TranslateTimestampSince(kamelet.CreationTimestamp)
+		}},
+	{name: "conditions", header: "Conditions", description: "Ready state conditions",
+		value: func(kamelet *camelkv1alpha1.Kamelet) interface{} { return conditionsValue(kamelet.Status.Conditions) }},
+	{name: "ready", header: "Ready", description: "Ready state of the Kamelet instance",
+		value: func(kamelet *camelkv1alpha1.Kamelet) interface{} { return readyCondition(kamelet.Status.Conditions) }},
+	{name: "reason", header: "Reason", description: "Reason if state is not Ready",
+		value: func(kamelet *camelkv1alpha1.Kamelet) interface{} {
+			return nonReadyConditionReason(kamelet.Status.Conditions)
+		}},
+}
+
+// namedColumnNames returns the names of the columns selectable with list-types --columns
+func namedColumnNames() []string {
+	names := make([]string, 0, len(namedColumns))
+	for _, column := range namedColumns {
+		names = append(names, column.name)
+	}
+	return names
+}
+
+// parseNamedColumns looks up the columns of given names in the order given, names are matched ignoring case
+func parseNamedColumns(names []string) ([]namedColumn, error) {
+	columns := make([]namedColumn, 0, len(names))
+	selected := map[string]bool{}
+	for _, name := range names {
+		column, ok := lookupNamedColumn(strings.ToLower(strings.TrimSpace(name)))
+		if !ok {
+			return nil, fmt.Errorf("invalid column '%s', must be one of: %s", name, strings.Join(namedColumnNames(), ", "))
+		}
+		if selected[column.name] {
+			return nil, fmt.Errorf("column '%s' given more than once", column.name)
+		}
+		selected[column.name] = true
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// lookupNamedColumn returns the column of given name from the registry
+func lookupNamedColumn(name string) (namedColumn, bool) {
+	for _, column := range namedColumns {
+		if column.name == name {
+			return column, true
+		}
+	}
+	return namedColumn{}, false
+}
+
+// namedColumnsHandlers returns the handler printing the Kamelet table with given columns. Like the default table
+// the namespace is added as first column with --all-namespaces unless selected explicitly.
+func namedColumnsHandlers(columns []namedColumn) func(h hprinters.PrintHandler) {
+	columnDefinitions := make([]metav1beta1.TableColumnDefinition, 0, len(columns)+1)
+	withNamespace := false
+	for _, column := range columns {
+		withNamespace = withNamespace || column.name == "namespace"
+	}
+	if !withNamespace {
+		columnDefinitions = append(columnDefinitions, metav1beta1.TableColumnDefinition{Name: "Namespace",
+			Type: "string", Description: "Namespace of the Kamelet instance", Priority: 0})
+	}
+	for _, column := range columns {
+		columnDefinitions = append(columnDefinitions, metav1beta1.TableColumnDefinition{Name: column.header,
+			Type: "string", Description: column.description, Priority: 1})
+	}
+
+	printRow := func(kamelet *camelkv1alpha1.Kamelet, options hprinters.PrintOptions) metav1beta1.TableRow {
+		row := metav1beta1.TableRow{
+			Object: runtime.RawExtension{Object: kamelet},
+		}
+		if options.AllNamespaces && !withNamespace {
+			row.Cells = append(row.Cells, kamelet.Namespace)
+		}
+		for _, column := range columns {
+			row.Cells = append(row.Cells, column.value(kamelet))
+		}
+		return row
+	}
+
+	return func(h hprinters.PrintHandler) {
+		h.TableHandler(columnDefinitions, func(kamelet *camelkv1alpha1.Kamelet, options hprinters.PrintOptions) ([]metav1beta1.TableRow, error) {
+			return []metav1beta1.TableRow{printRow(kamelet, options)}, nil
+		})
+		h.TableHandler(columnDefinitions, func(kameletList *camelkv1alpha1.KameletList, options hprinters.PrintOptions) ([]metav1beta1.TableRow, error) {
+			rows := make([]metav1beta1.TableRow, 0, len(kameletList.Items))
+			for i := range kameletList.Items {
+				rows = append(rows, printRow(&kameletList.Items[i], options))
+			}
+			return rows, nil
+		})
+	}
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"strings"
+	"testing"
+
+	camelkapis "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"knative.dev/client/pkg/util"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseNamedColumns(t *testing.T) {
+	columns, err := parseNamedColumns([]string{"Provider", " name", "props"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(columns), 3)
+	assert.Equal(t, columns[0].name, "provider")
+	assert.Equal(t, columns[1].name, "name")
+	assert.Equal(t, columns[2].name, "props")
+
+	_, err = parseNamedColumns([]string{"name", "owner"})
+	assert.Error(t, err, "invalid column 'owner', must be one of: namespace, name, type, title, phase, provider, "+
+		"support-level, props, age, conditions, ready, reason")
+
+	_, err = parseNamedColumns([]string{"name", "NAME"})
+	assert.Error(t, err, "column 'name' given more than once")
+}
+
+func TestListTypesColumns(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet1 := createKamelet("k1")
+	kamelet1.Annotations = map[string]string{kameletProviderAnnotation: "Apache Software Foundation"}
+	addKameletProperty(kamelet1, "period", "integer", "The interval", true)
+	addKameletProperty(kamelet1, "message", "string", "The message", false)
+	kamelet2 := createKamelet("k2")
+	kameletList := &camelkapis.KameletList{Items: []camelkapis.Kamelet{*kamelet1, *kamelet2}}
+	recorder.List(kameletList, nil)
+	recorder.List(kameletList, nil)
+	recorder.List(kameletList, nil)
+
+	output, err := runListTypesCmd(mockClient, "--columns", "provider,name,props")
+	assert.NilError(t, err)
+	lines := strings.Split(output, "\n")
+	assert.Equal(t, strings.Join(strings.Fields(lines[0]), " "), "PROVIDER NAME PROPS")
+	assert.Equal(t, strings.Join(strings.Fields(lines[1]), " "), "Apache Software Foundation k1 1/2")
+	assert.Equal(t, strings.Join(strings.Fields(lines[2]), " "), "k2 0/0")
+
+	// the namespace is added with --all-namespaces like for the default table
+	output, err = runListTypesCmd(mockClient, "--columns", "name", "--columns", "phase", "--all-namespaces")
+	assert.NilError(t, err)
+	lines = strings.Split(output, "\n")
+	assert.Equal(t, strings.Join(strings.Fields(lines[0]), " "), "NAMESPACE NAME PHASE")
+	assert.Equal(t, strings.Join(strings.Fields(lines[1]), " "), "default k1 Ready")
+
+	output, err = runListTypesCmd(mockClient, "--columns", "name,ready", "--no-headers")
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsNone(output, "NAME", "READY"))
+	assert.Equal(t, strings.Join(strings.Fields(output), " "), "k1 True k2 True")
+
+	recorder.Validate()
+}
+
+func TestListTypesErrorCaseColumns(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+
+	_, err := runListTypesCmd(mockClient, "--columns", "name,owner")
+	assert.ErrorContains(t, err, "invalid column 'owner', must be one of: namespace, name, type")
+
+	_, err = runListTypesCmd(mockClient, "--columns", "")
+	assert.ErrorContains(t, err, "--columns requires at least one column")
+
+	_, err = runListTypesCmd(mockClient, "--columns", "name", "-o", "wide")
+	assert.ErrorContains(t, err, "--columns can not be combined with --show-props or --output")
+
+	_, err = runListTypesCmd(mockClient, "--columns", "name", "--show-props")
+	assert.ErrorContains(t, err, "--columns can not be combined with --show-props or --output")
+
+	_, err = runListTypesCmd(mockClient, "--columns", "name,props", "--cached")
+	assert.Error(t, err, "--cached and --refresh-cache can not be combined with the props column as cached Kamelets do not hold their spec")
+
+	mockClient.Recorder().Validate()
+}
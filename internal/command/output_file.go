@@ -0,0 +1,92 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/pflag"
+)
+
+// outputFileMode is the mode of the files written with --output-file
+const outputFileMode = 0644
+
+// addOutputFileFlag adds the flag writing the command output to a file instead of stdout
+func addOutputFileFlag(flags *pflag.FlagSet, outputFile *string) {
+	flags.StringVar(outputFile, "output-file", "", "Write the output to given file instead of stdout, parent "+
+		"directories are created as needed. The file is only written once the command succeeded.")
+}
+
+// openOutput returns the writer the command output goes to, which is given writer unless an output file is set.
+// The returned finish function must be called with the outcome of the command: output files are moved into place
+// when it succeeded and removed otherwise, so that no partial file is left behind.
+func openOutput(out io.Writer, outputFile string) (io.Writer, func(error) error, error) {
+	if outputFile == "" {
+		return out, func(err error) error { return err }, nil
+	}
+	file, err := createOutputFile(outputFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, file.finish, nil
+}
+
+// outputFile writes to a temporary file next to the target path, which is renamed to the target when finished
+type outputFile struct {
+	path string
+	file *os.File
+}
+
+// createOutputFile creates the temporary file for given target path including missing parent directories
+func createOutputFile(path string) (*outputFile, error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("unable to create directory of output file '%s': %w", path, err)
+	}
+	file, err := ioutil.TempFile(dir, "."+filepath.Base(path)+".tmp-")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create output file '%s': %w", path, err)
+	}
+	return &outputFile{path: path, file: file}, nil
+}
+
+func (f *outputFile) Write(data []byte) (int, error) {
+	return f.file.Write(data)
+}
+
+// finish renames the temporary file to the target path if given error is nil and removes it otherwise
+func (f *outputFile) finish(err error) error {
+	closeErr := f.file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(f.file.Name(), outputFileMode)
+	}
+	if err == nil {
+		if err = os.Rename(f.file.Name(), f.path); err == nil {
+			return nil
+		}
+		err = fmt.Errorf("unable to write output file '%s': %w", f.path, err)
+	}
+	_ = os.Remove(f.file.Name())
+	return err
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	camelkapis "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+	"knative.dev/client/pkg/util"
+)
+
+func TestOpenOutput(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	out, finish, err := openOutput(stdout, "")
+	assert.NilError(t, err)
+	fmt.Fprint(out, "to stdout")
+	assert.NilError(t, finish(nil))
+	assert.Equal(t, stdout.String(), "to stdout")
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nested", "dir", "out.yaml")
+	out, finish, err = openOutput(stdout, path)
+	assert.NilError(t, err)
+	fmt.Fprint(out, "to file")
+	// nothing is visible at the target path before the output is finished
+	_, err = os.Stat(path)
+	assert.Assert(t, os.IsNotExist(err))
+	assert.NilError(t, finish(nil))
+	data, err := ioutil.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "to file")
+	assertOnlyFiles(t, filepath.Dir(path), "out.yaml")
+}
+
+func TestOpenOutputError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.yaml")
+	assert.NilError(t, ioutil.WriteFile(path, []byte("previous"), 0644))
+
+	out, finish, err := openOutput(&bytes.Buffer{}, path)
+	assert.NilError(t, err)
+	fmt.Fprint(out, "partial")
+	assert.Error(t, finish(errors.New("failed")), "failed")
+
+	// the previous file is kept and the temporary file is removed
+	data, err := ioutil.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "previous")
+	assertOnlyFiles(t, dir, "out.yaml")
+}
+
+func TestDescribeTypeOutputFile(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	recorder.Get(createKamelet("k1"), nil)
+
+	path := filepath.Join(t.TempDir(), "kamelets", "k1.yaml")
+	output, err := runDescribeTypeCmd(mockClient, "k1", "-o", "yaml", "--output-file", path)
+	assert.NilError(t, err)
+	assert.Equal(t, output, "")
+	data, err := ioutil.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsAll(string(data), "kind: Kamelet", "name: k1"))
+
+	_, err = runDescribeTypeCmd(mockClient, "k1", "--watch", "--output-file", path)
+	assert.Error(t, err, "--output-file can not be combined with --watch")
+
+	recorder.Validate()
+}
+
+func TestListTypesOutputFile(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	recorder.List(&camelkapis.KameletList{Items: []camelkapis.Kamelet{*createKamelet("k1")}}, nil)
+	recorder.List(&camelkapis.KameletList{}, nil)
+
+	path := filepath.Join(t.TempDir(), "kamelets.yaml")
+	output, err := runListTypesCmd(mockClient, "-o", "yaml", "--output-file", path)
+	assert.NilError(t, err)
+	assert.Equal(t, output, "")
+	data, err := ioutil.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsAll(string(data), "kind: KameletList", "name: k1"))
+
+	// the message about no Kamelets found goes to stderr
+	output, err = runListTypesCmd(mockClient, "--output-file", path)
+	assert.NilError(t, err)
+	assert.Equal(t, output, "")
+	data, err = ioutil.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "")
+
+	recorder.Validate()
+}
+
+func TestBindOutputFile(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+	recorder.Get(createKamelet("k1"), nil)
+
+	path := filepath.Join(t.TempDir(), "bindings", "k1.yaml")
+	output, err := runBindCmd(mockClient, "k1", "--name", "k1-binding", "--sink", "broker:default",
+		"--dry-run=client", "--output-file", path)
+	assert.NilError(t, err)
+	assert.Equal(t, output, "")
+	data, err := ioutil.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsAll(string(data), "kind: KameletBinding", "name: k1-binding"))
+
+	_, err = runBindCmd(mockClient, "k1", "--sink", "broker:default", "--output-file", path)
+	assert.Error(t, err, "--output-file requires --output yaml, json or json-summary, or --dry-run=client")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+// assertOnlyFiles checks that given directory holds exactly the files with given names
+func assertOnlyFiles(t *testing.T, dir string, names ...string) {
+	entries, err := ioutil.ReadDir(dir)
+	assert.NilError(t, err)
+	found := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		found = append(found, entry.Name())
+	}
+	assert.DeepEqual(t, found, names)
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"github.com/apache/camel-k/pkg/client/camel/clientset/versioned/scheme"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/client/pkg/util"
+)
+
+// outputScheme holds the API versions and conversions available to --output-version
+var outputScheme = scheme.Scheme
+
+// addOutputVersionFlag adds the flag selecting the API version of printed objects
+func addOutputVersionFlag(flags *pflag.FlagSet, outputVersion *string) {
+	flags.StringVar(outputVersion, "output-version", "", "API version the printed Kamelets are converted to, "+
+		"e.g. camel.apache.org/v1alpha1. Requires --output yaml or json and fails if no conversion to the version is available.")
+}
+
+// validateOutputVersion checks that given output version is a valid group version combined with an object output format
+func validateOutputVersion(outputVersion string, output string) error {
+	if outputVersion == "" {
+		return nil
+	}
+	if output = strings.ToLower(output); output != "yaml" && output != "json" {
+		return errors.New("--output-version requires --output yaml or json")
+	}
+	gv, err := schema.ParseGroupVersion(outputVersion)
+	if err != nil || gv.Group == "" || gv.Version == "" {
+		return fmt.Errorf("invalid output version '%s', must be given as group/version, e.g. %s", outputVersion,
+			v1alpha1.SchemeGroupVersion)
+	}
+	return nil
+}
+
+// convertToOutputVersion converts given object to given API version
+func convertToOutputVersion(obj runtime.Object, outputVersion string) (runtime.Object, error) {
+	gv, err := schema.ParseGroupVersion(outputVersion)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := outputScheme.ConvertToVersion(obj, gv)
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert %s to API version '%s': %w", kindOf(obj), outputVersion, err)
+	}
+	return converted, nil
+}
+
+// convertKameletListToOutputVersion converts the items of given Kamelet list to given API version, the result is
+// returned as unstructured list as the converted items may not fit into a Kamelet list
+func convertKameletListToOutputVersion(kameletList *v1alpha1.KameletList, outputVersion string) (*unstructured.UnstructuredList, error) {
+	result := &unstructured.UnstructuredList{}
+	result.SetGroupVersionKind(schema.FromAPIVersionAndKind(outputVersion, "KameletList"))
+	result.SetResourceVersion(kameletList.ResourceVersion)
+	for i := range kameletList.Items {
+		converted, err := convertToOutputVersion(&kameletList.Items[i], outputVersion)
+		if err != nil {
+			return nil, err
+		}
+		item, err := util.ToUnstructured(converted)
+		if err != nil {
+			return nil, err
+		}
+		result.Items = append(result.Items, *item)
+	}
+	return result, nil
+}
+
+// kindOf returns the kind of given object as known to the output scheme
+func kindOf(obj runtime.Object) string {
+	if kinds, _, err := outputScheme.ObjectKinds(obj); err == nil && len(kinds) > 0 {
+		return kinds[0].Kind
+	}
+	return fmt.Sprintf("%T", obj)
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"testing"
+
+	camelkapis "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/client/pkg/util"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+// withKameletV1Conversion registers Kamelets for camel.apache.org/v1 in the output scheme for the duration of the test
+func withKameletV1Conversion(t *testing.T) {
+	s := runtime.NewScheme()
+	assert.NilError(t, camelkapis.AddToScheme(s))
+	v1 := schema.GroupVersion{Group: camelkapis.SchemeGroupVersion.Group, Version: "v1"}
+	s.AddKnownTypeWithName(v1.WithKind(camelkapis.KameletKind), &camelkapis.Kamelet{})
+
+	original := outputScheme
+	outputScheme = s
+	t.Cleanup(func() {
+		outputScheme = original
+	})
+}
+
+func TestValidateOutputVersion(t *testing.T) {
+	assert.NilError(t, validateOutputVersion("", ""))
+	assert.NilError(t, validateOutputVersion("camel.apache.org/v1", "yaml"))
+	assert.NilError(t, validateOutputVersion("camel.apache.org/v1", "JSON"))
+	assert.Error(t, validateOutputVersion("camel.apache.org/v1", ""), "--output-version requires --output yaml or json")
+	assert.Error(t, validateOutputVersion("v1", "yaml"),
+		"invalid output version 'v1', must be given as group/version, e.g. camel.apache.org/v1alpha1")
+}
+
+func TestDescribeTypeOutputVersion(t *testing.T) {
+	withKameletV1Conversion(t)
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1", "-o", "yaml", "--output-version", "camel.apache.org/v1")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "apiVersion: camel.apache.org/v1\n", "kind: Kamelet\n", "name: k1\n"))
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeErrorCaseOutputVersion(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+
+	_, err := runDescribeTypeCmd(mockClient, "k1", "-o", "yaml", "--output-version", "camel.apache.org/v2")
+	assert.ErrorContains(t, err, "unable to convert Kamelet to API version 'camel.apache.org/v2'")
+
+	_, err = runDescribeTypeCmd(mockClient, "k1", "--output-version", "camel.apache.org/v1")
+	assert.ErrorContains(t, err, "--output-version requires --output yaml or json")
+
+	recorder.Validate()
+}
+
+func TestListTypesOutputVersion(t *testing.T) {
+	withKameletV1Conversion(t)
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kameletList := &camelkapis.KameletList{Items: []camelkapis.Kamelet{*createKamelet("k1"), *createKamelet("k2")}}
+	recorder.List(kameletList, nil)
+
+	output, err := runListTypesCmd(mockClient, "-o", "json", "--output-version", "camel.apache.org/v1")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, `"apiVersion": "camel.apache.org/v1"`, `"kind": "KameletList"`, `"name": "k1"`, `"name": "k2"`))
+	assert.Assert(t, util.ContainsNone(output, `"apiVersion": "camel.apache.org/v1alpha1"`))
+
+	recorder.Validate()
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+)
+
+// isTerminalInput returns true if given reader is an interactive terminal, tests replace it to simulate a terminal
+var isTerminalInput = func(in io.Reader) bool {
+	return isCharDevice(in)
+}
+
+// promptRequiredProperties asks for the value of each required Kamelet property that is not given yet. The property
+// default is offered as answer taken on empty input. Entered values are checked against the property type and
+// asked for again when invalid.
+func promptRequiredProperties(in io.Reader, out io.Writer, kamelet *v1alpha1.Kamelet, properties map[string]string) error {
+	if kamelet.Spec.Definition == nil {
+		return nil
+	}
+	definition := kamelet.Spec.Definition
+
+	reader := bufio.NewReader(in)
+	for _, propertyName := range sortedPropertyNames(definition, propertySortByName) {
+		if _, ok := properties[propertyName]; ok || !isRequired(definition, propertyName) {
+			continue
+		}
+		property := definition.Properties[propertyName]
+		defaultValue, hasDefault := propertyExampleValue(property)
+
+		for {
+			fmt.Fprint(out, propertyPrompt(propertyName, property, defaultValue, hasDefault))
+			line, err := reader.ReadString('\n')
+			if err != nil && (err != io.EOF || line == "") {
+				return fmt.Errorf("unable to read value for property '%s': %w", propertyName, err)
+			}
+
+			value := strings.TrimSpace(line)
+			if value == "" && hasDefault {
+				value = defaultValue
+			}
+			if value == "" {
+				fmt.Fprintf(out, "A value is required for property '%s'.\n", propertyName)
+				continue
+			}
+			if _, err := convertPropertyValue(property, value); err != nil {
+				fmt.Fprintf(out, "Invalid value '%s', expected type %s.\n", value, property.Type)
+				continue
+			}
+			properties[propertyName] = value
+			break
+		}
+	}
+	return nil
+}
+
+// confirm asks given yes/no question and returns true if it is answered with y or yes, ignoring case. Any other
+// answer including an empty one declines.
+func confirm(in io.Reader, out io.Writer, question string) (bool, error) {
+	fmt.Fprintf(out, "%s [y/N]: ", question)
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return false, fmt.Errorf("unable to read confirmation: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// propertyPrompt returns the prompt asking for the value of given property with its type, description and default
+func propertyPrompt(propertyName string, property v1alpha1.JSONSchemaProps, defaultValue string, hasDefault bool) string {
+	prompt := propertyName
+	if property.Type != "" {
+		prompt += " (" + property.Type + ")"
+	}
+	if property.Description != "" {
+		prompt += " - " + property.Description
+	}
+	if hasDefault {
+		prompt += " [" + defaultValue + "]"
+	}
+	return prompt + ": "
+}
@@ -0,0 +1,334 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"sigs.k8s.io/yaml"
+)
+
+// collectProperties merges the properties read from given properties file with the inline key=value pairs,
+// where inline properties win on conflict. The file is read from given reader when set to '-'.
+func collectProperties(in io.Reader, propertiesFile string, inline []string) (map[string]string, error) {
+	properties := map[string]string{}
+	if propertiesFile != "" {
+		fileProperties, err := readPropertiesFile(in, propertiesFile)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range fileProperties {
+			properties[key] = value
+		}
+	}
+
+	inlineProperties, err := parseProperties(inline)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range inlineProperties {
+		properties[key] = value
+	}
+	return properties, nil
+}
+
+// parsePropertiesFromEnv parses given property=ENV_VAR pairs and reads the property values from the named
+// environment variables, failing for variables that are not set
+func parsePropertiesFromEnv(entries []string) (map[string]string, error) {
+	properties := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid property from environment '%s', expected format property=ENV_VAR", entry)
+		}
+		value, ok := os.LookupEnv(parts[1])
+		if !ok {
+			return nil, fmt.Errorf("environment variable '%s' of property '%s' is not set", parts[1], parts[0])
+		}
+		properties[parts[0]] = value
+	}
+	return properties, nil
+}
+
+// readPropertiesFile reads the top level keys of given YAML or JSON file as properties
+func readPropertiesFile(in io.Reader, propertiesFile string) (map[string]string, error) {
+	var data []byte
+	var err error
+	if propertiesFile == "-" {
+		data, err = ioutil.ReadAll(in)
+	} else {
+		data, err = ioutil.ReadFile(propertiesFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read properties file '%s': %w", propertiesFile, err)
+	}
+
+	var values map[string]interface{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("invalid properties file '%s', expected YAML or JSON object: %w", propertiesFile, err)
+	}
+
+	properties := make(map[string]string, len(values))
+	for key, value := range values {
+		switch v := value.(type) {
+		case string:
+			properties[key] = v
+		case nil:
+			properties[key] = ""
+		default:
+			raw, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			properties[key] = string(raw)
+		}
+	}
+	return properties, nil
+}
+
+// validateProperties checks given property values against the Kamelet definition and
+// converts them to the declared property types. In strict mode the enum, minimum, maximum, length and
+// pattern constraints of the properties are enforced as well. The values of given secret properties are
+// secret placeholders and are neither converted nor checked against the constraints.
+func validateProperties(kamelet *v1alpha1.Kamelet, properties map[string]string, secretProperties map[string]bool,
+	strict bool) (map[string]interface{}, error) {
+	typed, err := convertProperties(kamelet, properties, secretProperties)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyRequiredProperties(kamelet, properties); err != nil {
+		return nil, err
+	}
+
+	if strict {
+		if err := verifyPropertyConstraints(kamelet, typed, secretProperties); err != nil {
+			return nil, err
+		}
+	}
+
+	return typed, nil
+}
+
+// verifyPropertyConstraints fails if given typed property values violate the constraints declared in the
+// Kamelet definition, all violations are reported at once. Given secret properties are skipped.
+func verifyPropertyConstraints(kamelet *v1alpha1.Kamelet, typed map[string]interface{}, secretProperties map[string]bool) error {
+	if kamelet.Spec.Definition == nil {
+		return nil
+	}
+
+	propertyNames := make([]string, 0, len(typed))
+	for propertyName := range typed {
+		propertyNames = append(propertyNames, propertyName)
+	}
+	sort.Strings(propertyNames)
+
+	var violations []string
+	for _, propertyName := range propertyNames {
+		if secretProperties[propertyName] {
+			continue
+		}
+		property := kamelet.Spec.Definition.Properties[propertyName]
+		for _, violation := range propertyConstraintViolations(property, typed[propertyName]) {
+			violations = append(violations, fmt.Sprintf("property '%s' %s", propertyName, violation))
+		}
+	}
+	if len(violations) > 0 {
+		return &ErrPropertyConstraintViolation{Kamelet: kamelet.Name, Violations: violations}
+	}
+	return nil
+}
+
+// propertyConstraintViolations returns the constraints of given property the value does not satisfy
+func propertyConstraintViolations(property v1alpha1.JSONSchemaProps, value interface{}) []string {
+	var violations []string
+
+	if len(property.Enum) > 0 && !enumContains(property.Enum, value) {
+		allowed := make([]string, 0, len(property.Enum))
+		for _, enumValue := range property.Enum {
+			if enumValue != nil {
+				allowed = append(allowed, string(enumValue.RawMessage))
+			}
+		}
+		violations = append(violations, fmt.Sprintf("must be one of: %s", strings.Join(allowed, ", ")))
+	}
+
+	if number, ok := numericValue(value); ok {
+		if property.Minimum != nil {
+			if minimum, err := property.Minimum.Float64(); err == nil {
+				if property.ExclusiveMinimum && number <= minimum {
+					violations = append(violations, fmt.Sprintf("must be greater than %s", property.Minimum))
+				} else if number < minimum {
+					violations = append(violations, fmt.Sprintf("must be greater than or equal to %s", property.Minimum))
+				}
+			}
+		}
+		if property.Maximum != nil {
+			if maximum, err := property.Maximum.Float64(); err == nil {
+				if property.ExclusiveMaximum && number >= maximum {
+					violations = append(violations, fmt.Sprintf("must be less than %s", property.Maximum))
+				} else if number > maximum {
+					violations = append(violations, fmt.Sprintf("must be less than or equal to %s", property.Maximum))
+				}
+			}
+		}
+	}
+
+	if text, ok := value.(string); ok {
+		length := int64(utf8.RuneCountInString(text))
+		if property.MinLength != nil && length < *property.MinLength {
+			violations = append(violations, fmt.Sprintf("must be at least %d characters long", *property.MinLength))
+		}
+		if property.MaxLength != nil && length > *property.MaxLength {
+			violations = append(violations, fmt.Sprintf("must be at most %d characters long", *property.MaxLength))
+		}
+		if property.Pattern != "" {
+			pattern, err := regexp.Compile(property.Pattern)
+			if err != nil {
+				violations = append(violations, fmt.Sprintf("declares the invalid pattern '%s'", property.Pattern))
+			} else if !pattern.MatchString(text) {
+				violations = append(violations, fmt.Sprintf("must match the pattern '%s'", property.Pattern))
+			}
+		}
+	}
+
+	return violations
+}
+
+// enumContains returns true if given typed value equals one of the enum values, numbers are compared by value
+func enumContains(enum []*v1alpha1.JSON, value interface{}) bool {
+	number, isNumber := numericValue(value)
+	for _, enumValue := range enum {
+		if enumValue == nil {
+			continue
+		}
+		var allowed interface{}
+		if err := json.Unmarshal(enumValue.RawMessage, &allowed); err != nil {
+			continue
+		}
+		if allowedNumber, ok := allowed.(float64); ok && isNumber {
+			if allowedNumber == number {
+				return true
+			}
+			continue
+		}
+		if allowed == value {
+			return true
+		}
+	}
+	return false
+}
+
+// numericValue returns given typed property value as float if it is a number
+func numericValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// convertProperties converts given property values to the types declared in the Kamelet definition,
+// failing for properties unknown to the Kamelet. The values of given secret properties are kept as they are.
+func convertProperties(kamelet *v1alpha1.Kamelet, properties map[string]string, secretProperties map[string]bool) (map[string]interface{}, error) {
+	definition := kamelet.Spec.Definition
+	if definition == nil {
+		definition = &v1alpha1.JSONSchemaProps{}
+	}
+
+	propertyNames := make([]string, 0, len(properties))
+	for propertyName := range properties {
+		propertyNames = append(propertyNames, propertyName)
+	}
+	sort.Strings(propertyNames)
+
+	typed := make(map[string]interface{}, len(properties))
+	for _, propertyName := range propertyNames {
+		property, ok := definition.Properties[propertyName]
+		if !ok {
+			return nil, &ErrPropertyUnknown{Kamelet: kamelet.Name, Property: propertyName,
+				ValidProperties: sortedPropertyNames(definition, propertySortByName)}
+		}
+
+		// secret references are resolved when the binding runs, their values can not be checked up front
+		if secretProperties[propertyName] {
+			typed[propertyName] = properties[propertyName]
+			continue
+		}
+
+		value, err := convertPropertyValue(property, properties[propertyName])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value '%s' for property '%s', expected type %s", properties[propertyName], propertyName, property.Type)
+		}
+		typed[propertyName] = value
+	}
+	return typed, nil
+}
+
+// verifyRequiredProperties fails if required properties of the Kamelet are missing in given properties
+func verifyRequiredProperties(kamelet *v1alpha1.Kamelet, properties map[string]string) error {
+	if kamelet.Spec.Definition == nil {
+		return nil
+	}
+	if missing := missingRequiredProperties(kamelet.Spec.Definition, properties); len(missing) > 0 {
+		return &ErrRequiredPropertyMissing{Kamelet: kamelet.Name, Properties: missing}
+	}
+	return nil
+}
+
+// convertPropertyValue parses given value according to the property type
+func convertPropertyValue(property v1alpha1.JSONSchemaProps, value string) (interface{}, error) {
+	switch property.Type {
+	case "integer":
+		return strconv.ParseInt(value, 10, 64)
+	case "number":
+		return strconv.ParseFloat(value, 64)
+	case "boolean":
+		return strconv.ParseBool(value)
+	default:
+		return value, nil
+	}
+}
+
+// missingRequiredProperties returns the sorted names of required properties that are neither given nor have a default
+func missingRequiredProperties(definition *v1alpha1.JSONSchemaProps, properties map[string]string) []string {
+	var missing []string
+	for _, required := range definition.Required {
+		if _, ok := properties[required]; ok {
+			continue
+		}
+		if propertyDefault(definition.Properties[required]) != "" {
+			continue
+		}
+		missing = append(missing, required)
+	}
+	sort.Strings(missing)
+	return missing
+}
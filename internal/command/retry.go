@@ -0,0 +1,186 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/util/retry"
+)
+
+// DefaultRequestTimeout is the default time limit of a single API request
+const DefaultRequestTimeout = 30 * time.Second
+
+// DefaultRetryBackoff is the exponential backoff used when retrying API requests that failed with a transient error
+var DefaultRetryBackoff = wait.Backoff{
+	Steps:    5,
+	Duration: 200 * time.Millisecond,
+	Factor:   2.0,
+	Jitter:   0.1,
+}
+
+// retryOnTransientError runs given API request and retries it with exponential backoff as long as it fails with
+// a transient error. Every attempt gets its own context bounded by the request timeout.
+func (params *KameletPluginParams) retryOnTransientError(request func(ctx context.Context) error) error {
+	backoff := params.RetryBackoff
+	if backoff.Steps < 1 {
+		backoff.Steps = 1
+	}
+	retriable := func(err error) bool {
+		// no point in retrying when the plugin context itself is done
+		return (params.Context == nil || params.Context.Err() == nil) && isTransientError(err)
+	}
+	return retry.OnError(backoff, retriable, func() error {
+		ctx, cancel := params.requestContext()
+		defer cancel()
+		return request(ctx)
+	})
+}
+
+// getKamelet fetches the Kamelet with given name, retrying on transient errors. A missing Kamelet is reported
+// as ErrKameletNotFound.
+func (params *KameletPluginParams) getKamelet(client KameletClient, namespace string, name string) (*v1alpha1.Kamelet, error) {
+	var kamelet *v1alpha1.Kamelet
+	err := params.retryOnTransientError(func(ctx context.Context) (err error) {
+		kamelet, err = client.Get(ctx, namespace, name, v1.GetOptions{})
+		return err
+	})
+	err = params.checkCamelKInstalled(err)
+	if apierrors.IsNotFound(err) && !isCamelKNotInstalled(err) {
+		return nil, &ErrKameletNotFound{Name: name, Namespace: namespace, Err: err}
+	}
+	return kamelet, err
+}
+
+// getKameletRaw fetches the Kamelet with given name as JSON document exactly as returned by the API server,
+// retrying on transient errors. A missing Kamelet is reported as ErrKameletNotFound.
+func (params *KameletPluginParams) getKameletRaw(client KameletClient, namespace string, name string) ([]byte, error) {
+	rawClient, ok := client.(rawKameletClient)
+	if !ok {
+		return nil, errors.New("the Kamelet client is not backed by an API server, Kamelets can not be read as served")
+	}
+	var data []byte
+	err := params.retryOnTransientError(func(ctx context.Context) (err error) {
+		data, err = rawClient.getRaw(ctx, namespace, name)
+		return err
+	})
+	err = params.checkCamelKInstalled(err)
+	if apierrors.IsNotFound(err) && !isCamelKNotInstalled(err) {
+		return nil, &ErrKameletNotFound{Name: name, Namespace: namespace, Err: err}
+	}
+	return data, err
+}
+
+// isCamelKNotInstalled returns true if given error reports that Camel K is not installed on the cluster
+func isCamelKNotInstalled(err error) bool {
+	var notInstalled *ErrCamelKNotInstalled
+	return errors.As(err, &notInstalled)
+}
+
+// checkCamelKInstalled returns ErrCamelKNotInstalled for given error of a failed API request if the cluster serves
+// none of the Camel K API versions, otherwise given error. Only errors of a missing resource or kind are checked
+// against the discovery. An outdated cached discovery is refreshed, a failing discovery keeps the original error.
+func (params *KameletPluginParams) checkCamelKInstalled(err error) error {
+	if !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return err
+	}
+	if params.NewDiscoveryClient == nil {
+		return err
+	}
+	discoveryClient, discoveryErr := params.NewDiscoveryClient()
+	if discoveryErr != nil {
+		return err
+	}
+	served, discoveryErr := servedGroupVersions(discoveryClient, v1alpha1.SchemeGroupVersion.Group, camelKAPIVersions)
+	if cached, ok := discoveryClient.(discovery.CachedDiscoveryInterface); ok && discoveryErr == nil && len(served) == 0 && !cached.Fresh() {
+		cached.Invalidate()
+		served, discoveryErr = servedGroupVersions(discoveryClient, v1alpha1.SchemeGroupVersion.Group, camelKAPIVersions)
+	}
+	if discoveryErr != nil || len(served) > 0 {
+		return err
+	}
+	return &ErrCamelKNotInstalled{Err: err}
+}
+
+// listKamelets lists the Kamelets matching given options, retrying on transient errors
+func (params *KameletPluginParams) listKamelets(client KameletClient, namespace string, opts v1.ListOptions) (*v1alpha1.KameletList, error) {
+	var kameletList *v1alpha1.KameletList
+	err := params.retryOnTransientError(func(ctx context.Context) (err error) {
+		kameletList, err = client.List(ctx, namespace, opts)
+		return err
+	})
+	return kameletList, params.checkCamelKInstalled(err)
+}
+
+// getKameletBinding fetches the Kamelet binding or pipe with given name, retrying on transient errors
+func (params *KameletPluginParams) getKameletBinding(client bindingClient, namespace string, name string) (*v1alpha1.KameletBinding, error) {
+	var binding *v1alpha1.KameletBinding
+	err := params.retryOnTransientError(func(ctx context.Context) (err error) {
+		binding, err = client.get(ctx, namespace, name)
+		return err
+	})
+	return binding, params.checkCamelKInstalled(err)
+}
+
+// listKameletBindings lists the Kamelet bindings or pipes matching given options, retrying on transient errors
+func (params *KameletPluginParams) listKameletBindings(client bindingClient, namespace string, opts v1.ListOptions) (*v1alpha1.KameletBindingList, error) {
+	var bindingList *v1alpha1.KameletBindingList
+	err := params.retryOnTransientError(func(ctx context.Context) (err error) {
+		bindingList, err = client.list(ctx, namespace, opts)
+		return err
+	})
+	return bindingList, params.checkCamelKInstalled(err)
+}
+
+// requestContext derives the context of a single API request from the plugin context
+func (params *KameletPluginParams) requestContext() (context.Context, context.CancelFunc) {
+	ctx := params.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if params.RequestTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, params.RequestTimeout)
+}
+
+// isTransientError returns true for errors that are likely to go away when retrying the request,
+// e.g. refused connections of a cluster that is still starting or timeouts
+func isTransientError(err error) bool {
+	if apierrors.IsTimeout(err) || apierrors.IsServerTimeout(err) || apierrors.IsTooManyRequests(err) ||
+		apierrors.IsServiceUnavailable(err) {
+		return true
+	}
+	if utilnet.IsConnectionRefused(err) || utilnet.IsConnectionReset(err) || utilnet.IsProbableEOF(err) {
+		return true
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
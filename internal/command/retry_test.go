@@ -0,0 +1,104 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRetryOnTransientError(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	refused := fmt.Errorf("dial tcp 127.0.0.1:6443: connect: %w", syscall.ECONNREFUSED)
+	recorder.Get(nil, refused)
+	recorder.Get(nil, context.DeadlineExceeded)
+	recorder.Get(createKamelet("k1"), nil)
+
+	p := newRetryTestParams(3)
+	kamelet, err := p.getKamelet(mockClient, "default", "k1")
+	assert.NilError(t, err)
+	assert.Equal(t, kamelet.Name, "k1")
+
+	recorder.Validate()
+}
+
+func TestRetryOnTransientErrorGivesUp(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	refused := fmt.Errorf("dial tcp 127.0.0.1:6443: connect: %w", syscall.ECONNREFUSED)
+	recorder.Get(nil, refused)
+	recorder.Get(nil, refused)
+
+	p := newRetryTestParams(2)
+	_, err := p.getKamelet(mockClient, "default", "k1")
+	assert.ErrorContains(t, err, "connection refused")
+
+	recorder.Validate()
+}
+
+func TestRetryOnTransientErrorNoRetryOnPermanentError(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	recorder.Get(nil, errors.New("not found"))
+	bindingRecorder.Get("k1-binding", nil, newBindingNotFoundError("k1-binding"))
+
+	p := newRetryTestParams(3)
+	_, err := p.getKamelet(mockClient, "default", "k1")
+	assert.Error(t, err, "not found")
+
+	_, err = p.getKameletBinding(&kameletBindingClient{client: mockClient}, "default", "k1-binding")
+	assert.ErrorContains(t, err, "not found")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestRequestContext(t *testing.T) {
+	p := newRetryTestParams(1)
+	ctx, cancel := p.requestContext()
+	defer cancel()
+	_, hasDeadline := ctx.Deadline()
+	assert.Assert(t, !hasDeadline)
+
+	p.RequestTimeout = time.Minute
+	ctx, cancel = p.requestContext()
+	defer cancel()
+	deadline, hasDeadline := ctx.Deadline()
+	assert.Assert(t, hasDeadline)
+	assert.Assert(t, time.Until(deadline) <= time.Minute)
+}
+
+func newRetryTestParams(steps int) *KameletPluginParams {
+	return &KameletPluginParams{
+		Context:      context.TODO(),
+		RetryBackoff: wait.Backoff{Steps: steps, Duration: time.Millisecond, Factor: 1.0},
+	}
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"knative.dev/client/pkg/printers"
+)
+
+const (
+	// passwordFormat is the property format of credentials
+	passwordFormat = "password"
+	// passwordDescriptor is the descriptor marking properties to be rendered as password field
+	passwordDescriptor = "urn:alm:descriptor:com.tectonic.ui:password"
+	// credentialsDescriptor is the descriptor grouping the credential properties of a Kamelet
+	credentialsDescriptor = "urn:camel:group:credentials"
+
+	// secretReferencePrefix starts the Camel property placeholder resolving a property from a Kubernetes secret
+	secretReferencePrefix = "{{secret:"
+)
+
+// isSecretProperty returns true if given property holds credentials that should be read from a secret
+func isSecretProperty(property v1alpha1.JSONSchemaProps) bool {
+	if property.Format == passwordFormat {
+		return true
+	}
+	for _, descriptor := range property.XDescriptors {
+		if descriptor == passwordDescriptor || descriptor == credentialsDescriptor {
+			return true
+		}
+	}
+	return false
+}
+
+// secretPropertyNames returns the sorted names of the properties of given Kamelet holding credentials
+func secretPropertyNames(kamelet *v1alpha1.Kamelet) []string {
+	if kamelet.Spec.Definition == nil {
+		return nil
+	}
+	var names []string
+	for name, property := range kamelet.Spec.Definition.Properties {
+		if isSecretProperty(property) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writeKameletSecrets prints a hint to pass the credential properties of given Kamelet from a secret
+func writeKameletSecrets(dw printers.PrefixWriter, kamelet *v1alpha1.Kamelet) {
+	names := secretPropertyNames(kamelet)
+	if len(names) == 0 {
+		return
+	}
+	section := dw.WriteAttribute("Secrets", strings.Join(names, ", "))
+	section.Writef("These properties hold credentials, pass them from a Kubernetes secret with\n")
+	section.Writef("--secret PROPERTY=SECRET[/KEY] or --property-from-secret PROPERTY=SECRET:KEY\n")
+	section.Writef("instead of giving their plain values with --property.\n")
+}
+
+// parseSecretReferences parses given property=secret[/key] pairs into the Camel property placeholders resolving the
+// properties from the secrets, the key defaults to the property name
+func parseSecretReferences(entries []string) (map[string]string, error) {
+	references := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid secret reference '%s', expected format property=secret[/key]", entry)
+		}
+		property := parts[0]
+		secret, key := parts[1], property
+		if i := strings.Index(secret, "/"); i >= 0 {
+			secret, key = secret[:i], secret[i+1:]
+		}
+		reference, err := secretReference(property, secret, key)
+		if err != nil {
+			return nil, err
+		}
+		references[property] = reference
+	}
+	return references, nil
+}
+
+// parsePropertiesFromSecrets parses given property=secret:key pairs into the Camel property placeholders resolving
+// the properties from the secrets
+func parsePropertiesFromSecrets(entries []string) (map[string]string, error) {
+	references := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || !strings.Contains(parts[1], ":") {
+			return nil, fmt.Errorf("invalid property from secret '%s', expected format property=secret:key", entry)
+		}
+		secretKey := strings.SplitN(parts[1], ":", 2)
+		reference, err := secretReference(parts[0], secretKey[0], secretKey[1])
+		if err != nil {
+			return nil, err
+		}
+		references[parts[0]] = reference
+	}
+	return references, nil
+}
+
+// secretReference returns the Camel property placeholder resolving given key of given secret, failing if the secret
+// name or key are not valid
+func secretReference(property string, secret string, key string) (string, error) {
+	if errs := validation.IsDNS1123Subdomain(secret); len(errs) > 0 {
+		return "", fmt.Errorf("invalid secret name '%s' for property '%s': %s", secret, property, strings.Join(errs, "; "))
+	}
+	if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+		return "", fmt.Errorf("invalid secret key '%s' for property '%s': %s", key, property, strings.Join(errs, "; "))
+	}
+	return fmt.Sprintf("%s%s/%s}}", secretReferencePrefix, secret, key), nil
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// InterruptExitCode is the exit code of interrupted commands, following the shell convention of 128 + SIGINT
+const InterruptExitCode = 130
+
+// ErrInterrupted is returned when a command waiting for a Kamelet or binding gets interrupted, e.g. with Ctrl-C
+var ErrInterrupted = errors.New("interrupted")
+
+// IsInterrupted returns true if given error has been caused by cancelling the plugin context
+func IsInterrupted(err error) bool {
+	return errors.Is(err, ErrInterrupted) || errors.Is(err, context.Canceled)
+}
+
+// CancelOnInterrupt cancels the plugin context when the process receives an interrupt or termination signal, so that
+// running watches and requests end cleanly. The signal handling is reset after the first signal, a second signal
+// terminates the process right away. The returned function stops the signal handling.
+func (params *KameletPluginParams) CancelOnInterrupt() (stop func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-signals:
+			signal.Stop(signals)
+			params.ContextCancel()
+		case <-done:
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
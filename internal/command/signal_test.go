@@ -0,0 +1,116 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestIsInterrupted(t *testing.T) {
+	assert.Assert(t, IsInterrupted(fmt.Errorf("%w while waiting", ErrInterrupted)))
+	assert.Assert(t, IsInterrupted(fmt.Errorf("request failed: %w", context.Canceled)))
+	assert.Assert(t, !IsInterrupted(errors.New("timeout")))
+	assert.Assert(t, !IsInterrupted(context.DeadlineExceeded))
+}
+
+func TestCancelOnInterrupt(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sending interrupts is not supported on Windows")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p := &KameletPluginParams{Context: ctx, ContextCancel: cancel}
+	stop := p.CancelOnInterrupt()
+	defer stop()
+
+	process, err := os.FindProcess(os.Getpid())
+	assert.NilError(t, err)
+	assert.NilError(t, process.Signal(os.Interrupt))
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context has not been cancelled on interrupt")
+	}
+}
+
+func TestWaitUntilReadyInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	kamelet := createKamelet("k1")
+	kamelet.Status.Conditions[0].Status = corev1.ConditionFalse
+	watcher := watch.NewFake()
+	go func() {
+		// the fake watcher is unbuffered, the event has been consumed by the wait when Modify returns
+		watcher.Modify(kamelet)
+		cancel()
+	}()
+
+	start := time.Now()
+	err := waitUntilReady(ctx, watcher, "Kamelet", "k1", time.Minute, kameletReadiness, nil)
+	assert.Assert(t, errors.Is(err, ErrInterrupted))
+	assert.Error(t, err, "interrupted while waiting for Kamelet k1 to become ready")
+	assert.Assert(t, time.Since(start) < 5*time.Second)
+	assert.Assert(t, watcher.IsStopped())
+}
+
+func TestDescribeTypeWatchInterrupted(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	kamelet.Status.Conditions[0].Status = corev1.ConditionFalse
+	kamelet.Status.Conditions[0].Reason = "Initializing"
+	recorder.Get(kamelet, nil)
+	watcher := watch.NewFake()
+	recorder.Watch(watcher, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &KameletPluginParams{
+		KnParams:      &commands.KnParams{},
+		Context:       ctx,
+		ContextCancel: cancel,
+		NewKameletClient: func() (KameletClient, error) {
+			return mockClient, nil
+		},
+		NewKameletBindingClient: mockBindingClient(mockClient),
+	}
+	go func() {
+		watcher.Modify(kamelet)
+		cancel()
+	}()
+
+	describeCmd, _, _ := commands.CreateSourcesTestKnCommand(NewDescribeTypeCommand(p), p.KnParams)
+	describeCmd.SetArgs([]string{"describe-type", "k1", "--watch", "--timeout", "1m"})
+	err := describeCmd.Execute()
+	assert.Assert(t, IsInterrupted(err))
+	assert.Assert(t, watcher.IsStopped())
+
+	recorder.Validate()
+}
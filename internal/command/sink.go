@@ -0,0 +1,94 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+)
+
+// sinkUsage is the help text of the sink flags
+const sinkUsage = "Addressable sink for events. " +
+	"You can specify a broker, channel, Knative service or URI. " +
+	"Examples: '--sink broker:nest' for a broker 'nest', " +
+	"'--sink channel:pipe' for a channel 'pipe', " +
+	"'--sink ksvc:mysvc:mynamespace' for a Knative service 'mysvc' in another namespace 'mynamespace', " +
+	"'--sink https://event.receiver.uri' for an URI with an 'http://' or 'https://' schema, " +
+	"'--sink ksvc:receiver' or simply '--sink receiver' for a Knative service 'receiver' in the current namespace."
+
+// sinkMappings maps the supported sink prefixes to the kind of the referenced resource
+var sinkMappings = map[string]schema.GroupVersionKind{
+	"broker":  {Group: "eventing.knative.dev", Version: "v1", Kind: "Broker"},
+	"channel": {Group: "messaging.knative.dev", Version: "v1", Kind: "Channel"},
+	"ksvc":    {Group: "serving.knative.dev", Version: "v1", Kind: "Service"},
+}
+
+// parseSink converts given sink expression into the endpoint of a Kamelet binding. The sink is either an URI,
+// a prefix:name[:namespace] reference or just a name referring to a Knative service in given namespace.
+func parseSink(sink string, namespace string) (v1alpha1.Endpoint, error) {
+	if strings.HasPrefix(sink, "http://") || strings.HasPrefix(sink, "https://") {
+		if _, err := apis.ParseURL(sink); err != nil {
+			return v1alpha1.Endpoint{}, fmt.Errorf("invalid sink URI '%s': %w", sink, err)
+		}
+		return v1alpha1.Endpoint{URI: &sink}, nil
+	}
+
+	parts := strings.SplitN(sink, ":", 3)
+	if len(parts) == 1 {
+		parts = []string{"ksvc", parts[0]}
+	}
+	if parts[0] == "" || parts[1] == "" || (len(parts) == 3 && parts[2] == "") {
+		return v1alpha1.Endpoint{}, fmt.Errorf("invalid sink '%s', expected format prefix:name[:namespace] or URI", sink)
+	}
+
+	gvk, ok := sinkMappings[parts[0]]
+	if !ok {
+		if parts[0] == "svc" || parts[0] == "service" {
+			return v1alpha1.Endpoint{}, fmt.Errorf("unsupported sink prefix '%s', please use prefix 'ksvc' for a Knative service", parts[0])
+		}
+		return v1alpha1.Endpoint{}, fmt.Errorf("unsupported sink prefix '%s', supported prefixes are: %s or an URI with 'http://' or 'https://' schema",
+			parts[0], strings.Join(sinkPrefixes(), ", "))
+	}
+	if len(parts) == 3 {
+		namespace = parts[2]
+	}
+
+	return v1alpha1.Endpoint{
+		Ref: &corev1.ObjectReference{
+			Kind:       gvk.Kind,
+			APIVersion: gvk.GroupVersion().String(),
+			Name:       parts[1],
+			Namespace:  namespace,
+		},
+	}, nil
+}
+
+// sinkPrefixes returns the sorted list of supported sink prefixes
+func sinkPrefixes() []string {
+	prefixes := make([]string, 0, len(sinkMappings))
+	for prefix := range sinkMappings {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	return prefixes
+}
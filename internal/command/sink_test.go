@@ -0,0 +1,76 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"testing"
+
+	camelkapis "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseSink(t *testing.T) {
+	uri := "https://event.receiver.uri/path"
+	for _, tc := range []struct {
+		sink     string
+		expected camelkapis.Endpoint
+	}{
+		{"ksvc:my-service", sinkRef("Service", "serving.knative.dev/v1", "my-service", "current")},
+		{"ksvc:my-service:other", sinkRef("Service", "serving.knative.dev/v1", "my-service", "other")},
+		{"my-service", sinkRef("Service", "serving.knative.dev/v1", "my-service", "current")},
+		{"channel:my-channel", sinkRef("Channel", "messaging.knative.dev/v1", "my-channel", "current")},
+		{"broker:default", sinkRef("Broker", "eventing.knative.dev/v1", "default", "current")},
+		{uri, camelkapis.Endpoint{URI: &uri}},
+	} {
+		t.Run(tc.sink, func(t *testing.T) {
+			endpoint, err := parseSink(tc.sink, "current")
+			assert.NilError(t, err)
+			assert.DeepEqual(t, endpoint, tc.expected)
+		})
+	}
+}
+
+func TestParseSinkErrorCase(t *testing.T) {
+	for _, tc := range []struct {
+		sink     string
+		expected string
+	}{
+		{"foo:bar", "unsupported sink prefix 'foo', supported prefixes are: broker, channel, ksvc or an URI with 'http://' or 'https://' schema"},
+		{"svc:bar", "unsupported sink prefix 'svc', please use prefix 'ksvc' for a Knative service"},
+		{"ksvc:", "invalid sink 'ksvc:', expected format prefix:name[:namespace] or URI"},
+		{":bar", "invalid sink ':bar', expected format prefix:name[:namespace] or URI"},
+		{"ksvc:bar:", "invalid sink 'ksvc:bar:', expected format prefix:name[:namespace] or URI"},
+	} {
+		t.Run(tc.sink, func(t *testing.T) {
+			_, err := parseSink(tc.sink, "current")
+			assert.Error(t, err, tc.expected)
+		})
+	}
+}
+
+func sinkRef(kind string, apiVersion string, name string, namespace string) camelkapis.Endpoint {
+	return camelkapis.Endpoint{
+		Ref: &corev1.ObjectReference{
+			Kind:       kind,
+			APIVersion: apiVersion,
+			Name:       name,
+			Namespace:  namespace,
+		},
+	}
+}
@@ -0,0 +1,163 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"knative.dev/client/pkg/util"
+)
+
+// templateFileExtension is the extension of the user template files overriding built-in templates
+const templateFileExtension = ".tmpl"
+
+// namedTemplateUsage is appended to the output flag usage of describe-type to document the named templates
+var namedTemplateUsage = fmt.Sprintf(" Built-in templates are selected by name with -o template=NAME, one of: %s. "+
+	"A file NAME%s in the kn-source-kamelet/templates directory of the user configuration directory overrides the "+
+	"built-in template of that name or adds a new one, custom templates are also given with -o go-template-file=FILE.",
+	strings.Join(builtinTemplateNames(), "|"), templateFileExtension)
+
+// builtinTemplates are the named templates selectable with -o template=NAME
+var builtinTemplates = map[string]string{
+	"summary": `{{.metadata.name}}
+  Title:     {{or title "-"}}
+  Type:      {{or kameletType "-"}}
+  Provider:  {{or provider "-"}}
+  Phase:     {{or phase "-"}}
+  Required:  {{or (join requiredProperties ", ") "-"}}
+`,
+	"bind-example": `{{bindExample}}
+`,
+}
+
+// templateName matches the names of named templates, inline templates are told apart by their actions
+var templateName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// namedTemplateArgument returns the template name given with -o template=NAME or -o go-template=NAME, false if the
+// output format is no template or an inline template like -o template='{{.metadata.name}}'
+func namedTemplateArgument(output string) (string, bool) {
+	for _, format := range []string{"template=", "go-template="} {
+		if strings.HasPrefix(strings.ToLower(output), format) {
+			name := output[len(format):]
+			return name, !strings.Contains(name, "{{")
+		}
+	}
+	return "", false
+}
+
+// templatesDir returns the directory of the user template files, which override built-in templates of the same name
+func (params *KameletPluginParams) templatesDir() (string, error) {
+	if params.templateDir != "" {
+		return params.templateDir, nil
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "kn-source-kamelet", "templates"), nil
+}
+
+// loadNamedTemplate returns the template of given name, a template file NAME.tmpl of the templates directory takes
+// precedence over the built-in template
+func (params *KameletPluginParams) loadNamedTemplate(name string) (string, error) {
+	if !templateName.MatchString(name) {
+		return "", fmt.Errorf("invalid template name '%s', built-in templates are: %s", name, strings.Join(builtinTemplateNames(), ", "))
+	}
+	dir, err := params.templatesDir()
+	if err == nil {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name+templateFileExtension))
+		if err == nil {
+			return string(data), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", fmt.Errorf("unable to read template '%s': %w", name, err)
+		}
+	}
+	if text, ok := builtinTemplates[name]; ok {
+		return text, nil
+	}
+	return "", fmt.Errorf("unknown template '%s', built-in templates are: %s", name, strings.Join(builtinTemplateNames(), ", "))
+}
+
+// builtinTemplateNames returns the names of the built-in templates in alphabetical order
+func builtinTemplateNames() []string {
+	names := make([]string, 0, len(builtinTemplates))
+	for name := range builtinTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writeKameletsWithTemplate executes given named template for each of given Kamelets. The template is evaluated over
+// the Kamelet object like with -o go-template, functions provide the values the plugin derives from the Kamelet.
+func writeKameletsWithTemplate(out io.Writer, name string, text string, kamelets []*v1alpha1.Kamelet) error {
+	for _, kamelet := range kamelets {
+		tmpl, err := template.New(name).Funcs(kameletTemplateFuncs(kamelet)).Parse(text)
+		if err != nil {
+			return fmt.Errorf("invalid template '%s': %w", name, err)
+		}
+		kamelet = kamelet.DeepCopy()
+		kamelet.SetGroupVersionKind(v1alpha1.SchemeGroupVersion.WithKind(v1alpha1.KameletKind))
+		obj, err := util.ToUnstructured(kamelet)
+		if err != nil {
+			return err
+		}
+		if err := tmpl.Execute(out, obj.Object); err != nil {
+			return fmt.Errorf("unable to execute template '%s' for Kamelet '%s': %w", name, kamelet.Name, err)
+		}
+	}
+	return nil
+}
+
+// kameletTemplateFuncs returns the template functions deriving values from given Kamelet
+func kameletTemplateFuncs(kamelet *v1alpha1.Kamelet) template.FuncMap {
+	return template.FuncMap{
+		"bindExample": func() string { return bindCommandExample(kamelet) },
+		"kameletType": func() string { return kamelet.Labels[kameletTypeLabel] },
+		"provider":    func() string { return extractKameletProvider(kamelet) },
+		"phase":       func() string { return string(kamelet.Status.Phase) },
+		"title": func() string {
+			if kamelet.Spec.Definition == nil {
+				return ""
+			}
+			return kamelet.Spec.Definition.Title
+		},
+		"requiredProperties": func() []string {
+			if kamelet.Spec.Definition == nil {
+				return nil
+			}
+			var required []string
+			for _, propertyName := range sortedPropertyNames(kamelet.Spec.Definition, propertySortByName) {
+				if isRequired(kamelet.Spec.Definition, propertyName) {
+					required = append(required, propertyName)
+				}
+			}
+			return required
+		},
+		"join": strings.Join,
+	}
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestNamedTemplateArgument(t *testing.T) {
+	for output, expected := range map[string]string{
+		"template=summary":         "summary",
+		"go-template=bind-example": "bind-example",
+	} {
+		name, ok := namedTemplateArgument(output)
+		assert.Assert(t, ok, output)
+		assert.Equal(t, name, expected)
+	}
+	for _, output := range []string{"", "yaml", "template={{.metadata.name}}", "go-template-file=summary.tmpl"} {
+		_, ok := namedTemplateArgument(output)
+		assert.Assert(t, !ok, output)
+	}
+}
+
+func TestDescribeTypeBuiltinTemplates(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	kamelet.Annotations = map[string]string{kameletProviderAnnotation: "Apache Software Foundation"}
+	addKameletProperty(kamelet, "message", "string", "The message to send", true)
+	addKameletProperty(kamelet, "period", "integer", "The interval", false)
+	setKameletPropertyDefault(kamelet, "period", "1000")
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+	recorder.Get(createKamelet("k2"), nil)
+
+	output, err := runDescribeTypeCmdWithTemplates(mockClient, t.TempDir(), "k1", "-o", "template=summary")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "k1\n"+
+		"  Title:     Kamelet k1\n"+
+		"  Type:      source\n"+
+		"  Provider:  Apache Software Foundation\n"+
+		"  Phase:     Ready\n"+
+		"  Required:  message\n")
+
+	// the bind-example template reproduces the --example line
+	example, err := runDescribeTypeCmdWithTemplates(mockClient, t.TempDir(), "k1", "--example")
+	assert.NilError(t, err)
+	output, err = runDescribeTypeCmdWithTemplates(mockClient, t.TempDir(), "k1", "k2", "-o", "template=bind-example")
+	assert.NilError(t, err)
+	assert.Equal(t, output, example+"kn-source-kamelet bind k2 --sink '<SINK>'\n")
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeUserTemplates(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	recorder.Get(createKamelet("k1"), nil)
+	recorder.Get(createKamelet("k1"), nil)
+	recorder.Get(createKamelet("k1"), nil)
+
+	dir := t.TempDir()
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, "summary.tmpl"), []byte("{{.metadata.name}} by {{or provider \"nobody\"}}\n"), 0644))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, "phase.tmpl"), []byte("{{.metadata.name}}: {{phase}}\n"), 0644))
+
+	// user templates override built-in templates and add new ones
+	output, err := runDescribeTypeCmdWithTemplates(mockClient, dir, "k1", "-o", "template=summary")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "k1 by nobody\n")
+	output, err = runDescribeTypeCmdWithTemplates(mockClient, dir, "k1", "-o", "template=phase")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "k1: Ready\n")
+
+	// inline templates are still handled by the print flags
+	output, err = runDescribeTypeCmdWithTemplates(mockClient, dir, "k1", "-o", "template={{.metadata.name}}")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "k1")
+
+	_, err = runDescribeTypeCmdWithTemplates(mockClient, dir, "k1", "-o", "template=overview")
+	assert.Error(t, err, "unknown template 'overview', built-in templates are: bind-example, summary")
+
+	_, err = runDescribeTypeCmdWithTemplates(mockClient, dir, "k1", "-o", "template=../summary")
+	assert.Error(t, err, "invalid template name '../summary', built-in templates are: bind-example, summary")
+
+	recorder.Validate()
+}
+
+func runDescribeTypeCmdWithTemplates(c *client.MockKameletClient, templateDir string, options ...string) (string, error) {
+	p := KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewKameletClient: func() (KameletClient, error) {
+			return c, nil
+		},
+		NewKameletBindingClient: mockBindingClient(c),
+		templateDir:             templateDir,
+	}
+
+	describeCmd, _, output := commands.CreateSourcesTestKnCommand(NewDescribeTypeCommand(&p), p.KnParams)
+	describeCmd.SetArgs(append([]string{"describe-type"}, options...))
+	err := describeCmd.Execute()
+	return output.String(), err
+}
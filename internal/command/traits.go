@@ -0,0 +1,102 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	camelkapisv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+// traitAnnotationPrefix is the prefix of the annotations configuring the traits of a Pipe
+const traitAnnotationPrefix = "trait.camel.apache.org/"
+
+// traitKey matches the trait.property keys of trait configurations, e.g. container.limit-memory
+var traitKey = regexp.MustCompile(`^([a-z][a-z0-9-]*)\.([a-zA-Z][a-zA-Z0-9-]*)$`)
+
+// parseTraits parses given trait.property=value pairs into the trait configurations of an integration, the
+// properties are grouped by trait. A property given several times holds the list of its values. Traits are not
+// checked against the traits known to Camel K so that newer traits can be configured as well.
+func parseTraits(entries []string) (map[string]camelkapisv1.TraitSpec, error) {
+	configurations := map[string]map[string]interface{}{}
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid trait '%s', expected format trait.property=value", entry)
+		}
+		key := traitKey.FindStringSubmatch(parts[0])
+		if key == nil {
+			return nil, fmt.Errorf("invalid trait '%s', the key must be given as trait.property, e.g. container.limit-memory", entry)
+		}
+		trait, property := key[1], key[2]
+		if configurations[trait] == nil {
+			configurations[trait] = map[string]interface{}{}
+		}
+		switch existing := configurations[trait][property].(type) {
+		case nil:
+			configurations[trait][property] = parts[1]
+		case string:
+			configurations[trait][property] = []string{existing, parts[1]}
+		case []string:
+			configurations[trait][property] = append(existing, parts[1])
+		}
+	}
+
+	traits := make(map[string]camelkapisv1.TraitSpec, len(configurations))
+	for trait, configuration := range configurations {
+		data, err := json.Marshal(configuration)
+		if err != nil {
+			return nil, err
+		}
+		traits[trait] = camelkapisv1.TraitSpec{
+			Configuration: camelkapisv1.TraitConfiguration{RawMessage: camelkapisv1.RawMessage(data)},
+		}
+	}
+	return traits, nil
+}
+
+// traitAnnotations converts given trait configurations into the annotations used to configure the traits of a Pipe,
+// as the v1 API holds the traits in a typed form. List values are given as JSON array.
+func traitAnnotations(traits map[string]interface{}) (map[string]string, error) {
+	annotations := map[string]string{}
+	for trait, spec := range traits {
+		fields, ok := spec.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid trait %s, expected an object but got %T", trait, spec)
+		}
+		configuration, ok := fields["configuration"].(map[string]interface{})
+		if !ok && fields["configuration"] != nil {
+			return nil, fmt.Errorf("invalid configuration of trait %s, expected an object but got %T", trait, fields["configuration"])
+		}
+		for property, value := range configuration {
+			annotation := traitAnnotationPrefix + trait + "." + property
+			if text, ok := value.(string); ok {
+				annotations[annotation] = text
+				continue
+			}
+			data, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			annotations[annotation] = string(data)
+		}
+	}
+	return annotations, nil
+}
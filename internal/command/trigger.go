@@ -0,0 +1,151 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	knerrors "knative.dev/client/pkg/errors"
+	eventingv1client "knative.dev/client/pkg/eventing/v1"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+// brokerSinkKind is the kind of the sink a Trigger can be created for
+const brokerSinkKind = "Broker"
+
+// isBrokerSink returns true if given endpoint refers to a Knative broker
+func isBrokerSink(sink v1alpha1.Endpoint) bool {
+	return sink.Ref != nil && sink.Ref.Kind == brokerSinkKind &&
+		sink.Ref.APIVersion == sinkMappings["broker"].GroupVersion().String()
+}
+
+// parseTriggerFilters converts given attribute=value pairs into the attribute filter of a Trigger
+func parseTriggerFilters(entries []string) (map[string]string, error) {
+	filters := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid Trigger filter '%s', expected format attribute=value", entry)
+		}
+		if !cloudEventAttributeName.MatchString(parts[0]) {
+			return nil, fmt.Errorf("invalid Trigger filter '%s', the CloudEvent attribute name must consist of "+
+				"lower-case letters and digits", entry)
+		}
+		filters[parts[0]] = parts[1]
+	}
+	return filters, nil
+}
+
+// parseTriggerSubscriber converts given sink expression into the subscriber of a Trigger
+func parseTriggerSubscriber(subscriber string, namespace string) (*duckv1.Destination, error) {
+	endpoint, err := parseSink(subscriber, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Trigger subscriber: %w", err)
+	}
+	if endpoint.URI != nil {
+		uri, err := apis.ParseURL(*endpoint.URI)
+		if err != nil {
+			return nil, err
+		}
+		return &duckv1.Destination{URI: uri}, nil
+	}
+	return &duckv1.Destination{
+		Ref: &duckv1.KReference{
+			Kind:       endpoint.Ref.Kind,
+			APIVersion: endpoint.Ref.APIVersion,
+			Name:       endpoint.Ref.Name,
+			Namespace:  endpoint.Ref.Namespace,
+		},
+	}, nil
+}
+
+// createTrigger builds the Trigger delivering the events of the broker given as sink to given subscriber, the
+// Trigger lives in the namespace of the broker
+func createTrigger(name string, sink v1alpha1.Endpoint, subscriber *duckv1.Destination, filters map[string]string) *eventingv1.Trigger {
+	trigger := eventingv1client.NewTriggerBuilder(name).
+		Namespace(sink.Ref.Namespace).
+		Broker(sink.Ref.Name).
+		Subscriber(subscriber).
+		Filters(filters).
+		Build()
+	trigger.Labels = map[string]string{managedByLabel: managedByValue}
+	return trigger
+}
+
+// lookupBindingTrigger fetches the existing Trigger named after the first of given bindings, nil if there is none.
+// An existing Trigger is only reused if it is managed by the plugin, so that other Triggers are not overwritten. The
+// lookup runs before the bindings are created or replaced, which are left untouched if it fails. Bindings without
+// name get a name generated by the API server, which can not collide with an existing Trigger.
+func lookupBindingTrigger(p *KameletPluginParams, bindings []*v1alpha1.KameletBinding) (*eventingv1.Trigger, error) {
+	name, namespace := bindings[0].Name, bindings[0].Spec.Sink.Ref.Namespace
+	if name == "" {
+		return nil, nil
+	}
+	eventingClient, err := p.NewEventingClient(namespace)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := eventingClient.GetTrigger(p.Context, name)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to look up Trigger '%s' in namespace '%s': %w", name, namespace, knerrors.GetError(err))
+	}
+	if existing.Labels[managedByLabel] != managedByValue {
+		return nil, fmt.Errorf("Trigger '%s' already exists in namespace '%s' and is not managed by %s, "+
+			"delete it or choose another binding name with --name", name, namespace, managedByValue)
+	}
+	return existing, nil
+}
+
+// createBindingTrigger creates the Trigger requested for the broker sink of given created bindings. It is named after
+// the first binding as returned by the API server, including a generated name, an existing Trigger found by lookupBindingTrigger is updated instead. When this fails, the bindings
+// are rolled back so that no binding is left without the Trigger filtering its events.
+func createBindingTrigger(p *KameletPluginParams, client bindingClient, bindings []*v1alpha1.KameletBinding,
+	replaced map[string]bool, existing *eventingv1.Trigger, subscriber *duckv1.Destination,
+	filters map[string]string) (*eventingv1.Trigger, error) {
+	sink := bindings[0].Spec.Sink
+	trigger := createTrigger(bindings[0].Name, sink, subscriber, filters)
+	action := "create"
+	if existing != nil {
+		action = "update"
+		updated := existing.DeepCopy()
+		updated.Labels = mergeMetadata(updated.Labels, trigger.Labels)
+		updated.Spec = trigger.Spec
+		trigger = updated
+	}
+
+	eventingClient, err := p.NewEventingClient(trigger.Namespace)
+	if err == nil {
+		if existing != nil {
+			err = eventingClient.UpdateTrigger(p.Context, trigger)
+		} else {
+			err = eventingClient.CreateTrigger(p.Context, trigger)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to %s Trigger '%s' in namespace '%s': %v; rolled back the bindings: %s", action,
+			trigger.Name, trigger.Namespace, knerrors.GetError(err), rollbackKameletBindings(p, client, bindings, replaced))
+	}
+	return trigger, nil
+}
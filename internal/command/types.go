@@ -18,37 +18,200 @@ package command
 
 import (
 	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"time"
 
 	camelk "github.com/apache/camel-k/pkg/client/camel/clientset/versioned"
 	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clienteventingv1 "knative.dev/client/pkg/eventing/v1"
 	"knative.dev/client/pkg/kn/commands"
+	eventingv1 "knative.dev/eventing/pkg/client/clientset/versioned/typed/eventing/v1"
 )
 
 // KnParams for creating commands. Useful for inserting mocks for testing.
 type KameletPluginParams struct {
 	*commands.KnParams
-	Context          context.Context
-	ContextCancel    context.CancelFunc
-	NewKameletClient func() (camelkv1alpha1.CamelV1alpha1Interface, error)
+	Context       context.Context
+	ContextCancel context.CancelFunc
+	// NewKameletClient returns the client of the Kamelets, tests inject the recording mock of the internal client
+	// package or the fake of its testing subpackage so that the commands run without a cluster
+	NewKameletClient func() (KameletClient, error)
+	// NewKameletBindingClient returns the client used for the camel.apache.org/v1alpha1 Kamelet binding API
+	NewKameletBindingClient func() (camelkv1alpha1.KameletBindingsGetter, error)
+	// NewPipeClient returns the dynamic client used for the camel.apache.org/v1 Pipe API
+	NewPipeClient func() (dynamic.Interface, error)
+	// NewPodClient returns the client of the pods running the integrations, used to stream their logs
+	NewPodClient func() (corev1client.PodsGetter, error)
+	// NewDiscoveryClient returns the client detecting the APIs served by the cluster
+	NewDiscoveryClient func() (discovery.ServerResourcesInterface, error)
+	// RequestTimeout limits the duration of a single API request, no limit applies when zero
+	RequestTimeout time.Duration
+	// RetryBackoff configures the retries of API requests that failed with a transient error
+	RetryBackoff wait.Backoff
+	// Impersonate is the user to impersonate for the API requests
+	Impersonate string
+	// ImpersonateGroups are the groups to impersonate for the API requests
+	ImpersonateGroups []string
+	// CacheDir is the directory of the local Kamelet and API discovery caches. When empty the Kamelets are cached in
+	// the user cache directory and the discovery in the kubectl cache directory.
+	CacheDir string
+	// Quiet suppresses informational and progress messages, errors, warnings and requested output are still printed
+	Quiet bool
+
+	// wrapTransport wraps the HTTP transport of the API clients, it allows tests to inspect the requests sent
+	wrapTransport func(http.RoundTripper) http.RoundTripper
+	// templateDir overrides the directory of the user templates, it allows tests to provide their own templates
+	templateDir string
 }
 
 func (params *KameletPluginParams) Initialize() {
 	if params.KnParams == nil {
-		params.KnParams = &commands.KnParams{}
+		params.KnParams = &commands.KnParams{
+			NewEventingClient: params.newEventingClient,
+		}
 		params.KnParams.Initialize()
 	}
 
+	if params.NewEventingClient == nil {
+		params.NewEventingClient = params.newEventingClient
+	}
+
 	if params.NewKameletClient == nil {
 		params.NewKameletClient = params.newKameletClient
 	}
+
+	if params.NewKameletBindingClient == nil {
+		params.NewKameletBindingClient = params.newKameletBindingClient
+	}
+
+	if params.NewPipeClient == nil {
+		params.NewPipeClient = params.newPipeClient
+	}
+
+	if params.NewPodClient == nil {
+		params.NewPodClient = params.newPodClient
+	}
+
+	if params.NewDiscoveryClient == nil {
+		params.NewDiscoveryClient = params.newDiscoveryClient
+	}
+
+	if params.RetryBackoff.Steps == 0 {
+		params.RetryBackoff = DefaultRetryBackoff
+	}
+}
+
+// AddKubeConfigFlags adds the flags selecting and overriding the kubeconfig used to connect to the cluster
+func (params *KameletPluginParams) AddKubeConfigFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&params.KubeCfgPath, "kubeconfig", "", "kubectl configuration file (default: ~/.kube/config). "+
+		"Several files are merged when given as list like with the KUBECONFIG environment variable.")
+	flags.StringVar(&params.KubeContext, "context", "", "Name of the kubeconfig context to use")
+	flags.StringVar(&params.KubeCluster, "cluster", "", "Name of the kubeconfig cluster to use")
+	flags.StringVar(&params.Impersonate, "as", "", "Username to impersonate for the operation")
+	flags.StringArrayVar(&params.ImpersonateGroups, "as-group", []string{}, "Group to impersonate for the operation, "+
+		"this flag can be repeated to specify multiple groups")
+}
+
+// GetNamespace returns the namespace given with --namespace or the namespace of the current kubeconfig context
+func (params *KameletPluginParams) GetNamespace(cmd *cobra.Command) (string, error) {
+	if err := params.ensureClientConfig(); err != nil {
+		return "", err
+	}
+	return params.KnParams.GetNamespace(cmd)
 }
 
-func (params *KameletPluginParams) newKameletClient() (camelkv1alpha1.CamelV1alpha1Interface, error) {
+// messageWriter returns given writer for informational and progress messages, the messages are discarded in quiet mode
+func (params *KameletPluginParams) messageWriter(out io.Writer) io.Writer {
+	if params.Quiet {
+		return ioutil.Discard
+	}
+	return out
+}
+
+// ensureClientConfig sets up the kubeconfig loading unless already done. The clientcmd loading rules are used so
+// that a KUBECONFIG holding a list of files is merged the same way kubectl does it, the --kubeconfig flag accepts
+// such a list as well.
+func (params *KameletPluginParams) ensureClientConfig() error {
+	if params.ClientConfig != nil {
+		return nil
+	}
+
+	paths := filepath.SplitList(params.KubeCfgPath)
+	if len(paths) <= 1 {
+		clientConfig, err := params.GetClientConfig()
+		if err != nil {
+			return err
+		}
+		params.ClientConfig = clientConfig
+		return nil
+	}
+
+	overrides := &clientcmd.ConfigOverrides{
+		CurrentContext: params.KubeContext,
+	}
+	overrides.Context.Cluster = params.KubeCluster
+	params.ClientConfig = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(&clientcmd.ClientConfigLoadingRules{Precedence: paths}, overrides)
+	return nil
+}
+
+// restConfig returns the REST config built from the kubeconfig and the overrides given as flags
+func (params *KameletPluginParams) restConfig() (*rest.Config, error) {
+	if err := params.ensureClientConfig(); err != nil {
+		return nil, err
+	}
+
 	restConfig, err := params.RestConfig()
 	if err != nil {
 		return nil, err
 	}
 
+	if params.Impersonate == "" && len(params.ImpersonateGroups) > 0 {
+		return nil, errors.New("impersonating groups requires a user, use --as together with --as-group")
+	}
+	if params.Impersonate != "" {
+		restConfig.Impersonate = rest.ImpersonationConfig{
+			UserName: params.Impersonate,
+			Groups:   params.ImpersonateGroups,
+		}
+	}
+	if params.wrapTransport != nil {
+		restConfig.Wrap(params.wrapTransport)
+	}
+	return restConfig, nil
+}
+
+func (params *KameletPluginParams) newKameletClient() (KameletClient, error) {
+	restConfig, err := params.restConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := camelk.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return newKameletClientFor(client.CamelV1alpha1()), nil
+}
+
+func (params *KameletPluginParams) newKameletBindingClient() (camelkv1alpha1.KameletBindingsGetter, error) {
+	restConfig, err := params.restConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	client, err := camelk.NewForConfig(restConfig)
 	if err != nil {
 		return nil, err
@@ -56,3 +219,44 @@ func (params *KameletPluginParams) newKameletClient() (camelkv1alpha1.CamelV1alp
 
 	return client.CamelV1alpha1(), nil
 }
+
+func (params *KameletPluginParams) newPipeClient() (dynamic.Interface, error) {
+	restConfig, err := params.restConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return dynamic.NewForConfig(restConfig)
+}
+
+func (params *KameletPluginParams) newPodClient() (corev1client.PodsGetter, error) {
+	restConfig, err := params.restConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return corev1client.NewForConfig(restConfig)
+}
+
+// newEventingClient replaces the eventing client of kn, so that the same REST config is used for all API requests
+func (params *KameletPluginParams) newEventingClient(namespace string) (clienteventingv1.KnEventingClient, error) {
+	restConfig, err := params.restConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := eventingv1.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	return clienteventingv1.NewKnEventingClient(client, namespace), nil
+}
+
+func (params *KameletPluginParams) newDiscoveryClient() (discovery.ServerResourcesInterface, error) {
+	restConfig, err := params.restConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return params.newCachedDiscoveryClient(restConfig)
+}
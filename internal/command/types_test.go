@@ -0,0 +1,285 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	camelkapis "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+const testKubeConfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: dev
+  cluster:
+    server: https://dev.example.com
+- name: prod
+  cluster:
+    server: https://prod.example.com
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    user: developer
+- name: prod
+  context:
+    cluster: prod
+    user: developer
+current-context: dev
+users:
+- name: developer
+  user:
+    token: secret
+`
+
+func TestKubeConfigFlags(t *testing.T) {
+	kubeConfig := writeTestKubeConfig(t)
+
+	for _, tc := range []struct {
+		args []string
+		host string
+	}{
+		{args: []string{}, host: "https://dev.example.com"},
+		{args: []string{"--context", "prod"}, host: "https://prod.example.com"},
+		{args: []string{"--cluster", "prod"}, host: "https://prod.example.com"},
+	} {
+		restConfig := parseKubeConfigFlags(t, append([]string{"--kubeconfig", kubeConfig}, tc.args...)...)
+		assert.Equal(t, restConfig.Host, tc.host)
+		assert.Equal(t, restConfig.BearerToken, "secret")
+	}
+}
+
+func TestKubeConfigFlagsImpersonation(t *testing.T) {
+	kubeConfig := writeTestKubeConfig(t)
+
+	restConfig := parseKubeConfigFlags(t, "--kubeconfig", kubeConfig)
+	assert.Equal(t, restConfig.Impersonate.UserName, "")
+
+	restConfig = parseKubeConfigFlags(t, "--kubeconfig", kubeConfig, "--as", "jane", "--as-group", "dev", "--as-group", "ops")
+	assert.Equal(t, restConfig.Impersonate.UserName, "jane")
+	assert.DeepEqual(t, restConfig.Impersonate.Groups, []string{"dev", "ops"})
+}
+
+func TestKubeConfigFlagsImpersonationRequiresUser(t *testing.T) {
+	p := newKubeConfigTestParams(t, "--kubeconfig", writeTestKubeConfig(t), "--as-group", "dev")
+	_, err := p.restConfig()
+	assert.Error(t, err, "impersonating groups requires a user, use --as together with --as-group")
+}
+
+func TestImpersonationHeaders(t *testing.T) {
+	kubeConfig := writeTestKubeConfig(t)
+
+	headers := requestHeaders(t, "--kubeconfig", kubeConfig)
+	for _, header := range headers {
+		assert.Equal(t, header.Get("Impersonate-User"), "")
+		assert.Assert(t, len(header.Values("Impersonate-Group")) == 0)
+	}
+
+	headers = requestHeaders(t, "--kubeconfig", kubeConfig, "--as", "jane", "--as-group", "dev", "--as-group", "ops")
+	for _, header := range headers {
+		assert.Equal(t, header.Get("Impersonate-User"), "jane")
+		assert.DeepEqual(t, header.Values("Impersonate-Group"), []string{"dev", "ops"})
+	}
+}
+
+// fakeRoundTripper records the headers of the requests and answers them with not found
+type fakeRoundTripper struct {
+	headers []http.Header
+}
+
+func (f *fakeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.headers = append(f.headers, req.Header.Clone())
+	return &http.Response{
+		StatusCode: http.StatusNotFound,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)),
+		Request:    req,
+	}, nil
+}
+
+// requestHeaders sends a request with each of the API clients configured by given flags and returns the headers sent
+func requestHeaders(t *testing.T, args ...string) []http.Header {
+	p := newKubeConfigTestParams(t, args...)
+	fake := &fakeRoundTripper{}
+	p.wrapTransport = func(http.RoundTripper) http.RoundTripper {
+		return fake
+	}
+
+	kameletClient, err := p.NewKameletClient()
+	assert.NilError(t, err)
+	_, err = kameletClient.Get(p.Context, "default", "timer-source", v1.GetOptions{})
+	assert.Assert(t, err != nil)
+
+	bindingClient, err := p.NewKameletBindingClient()
+	assert.NilError(t, err)
+	_, err = bindingClient.KameletBindings("default").Get(p.Context, "timer-binding", v1.GetOptions{})
+	assert.Assert(t, err != nil)
+
+	pipeClient, err := p.NewPipeClient()
+	assert.NilError(t, err)
+	_, err = pipeClient.Resource(schema.GroupVersionResource{Group: "camel.apache.org", Version: "v1", Resource: "pipes"}).
+		Namespace("default").Get(p.Context, "timer-pipe", v1.GetOptions{})
+	assert.Assert(t, err != nil)
+
+	discoveryClient, err := p.NewDiscoveryClient()
+	assert.NilError(t, err)
+	_, err = discoveryClient.ServerResourcesForGroupVersion("camel.apache.org/v1")
+	assert.Assert(t, err != nil)
+
+	eventingClient, err := p.NewEventingClient("default")
+	assert.NilError(t, err)
+	_, err = eventingClient.GetTrigger(p.Context, "timer-trigger")
+	assert.Assert(t, err != nil)
+
+	assert.Equal(t, len(fake.headers), 5)
+	return fake.headers
+}
+
+// testKubeConfigOverlay selects the prod context and its namespace, it is merged on top of testKubeConfig
+const testKubeConfigOverlay = `apiVersion: v1
+kind: Config
+contexts:
+- name: prod
+  context:
+    cluster: prod
+    user: developer
+    namespace: production
+current-context: prod
+`
+
+func TestKubeConfigMergeList(t *testing.T) {
+	kubeConfig := writeTestKubeConfig(t)
+	overlay := filepath.Join(t.TempDir(), "overlay")
+	assert.NilError(t, ioutil.WriteFile(overlay, []byte(testKubeConfigOverlay), 0600))
+	mergeList := strings.Join([]string{overlay, kubeConfig}, string(filepath.ListSeparator))
+
+	original, set := os.LookupEnv("KUBECONFIG")
+	defer func() {
+		if set {
+			os.Setenv("KUBECONFIG", original)
+		} else {
+			os.Unsetenv("KUBECONFIG")
+		}
+	}()
+	assert.NilError(t, os.Setenv("KUBECONFIG", mergeList))
+
+	// the first file setting the current context wins, the clusters and users come from the second file
+	restConfig := parseKubeConfigFlags(t)
+	assert.Equal(t, restConfig.Host, "https://prod.example.com")
+	assert.Equal(t, restConfig.BearerToken, "secret")
+
+	restConfig = parseKubeConfigFlags(t, "--context", "dev")
+	assert.Equal(t, restConfig.Host, "https://dev.example.com")
+
+	assert.NilError(t, os.Unsetenv("KUBECONFIG"))
+	restConfig = parseKubeConfigFlags(t, "--kubeconfig", mergeList)
+	assert.Equal(t, restConfig.Host, "https://prod.example.com")
+}
+
+func TestKubeConfigMergeListNamespace(t *testing.T) {
+	kubeConfig := writeTestKubeConfig(t)
+	overlay := filepath.Join(t.TempDir(), "overlay")
+	assert.NilError(t, ioutil.WriteFile(overlay, []byte(testKubeConfigOverlay), 0600))
+
+	p := &KameletPluginParams{
+		Context: context.TODO(),
+	}
+	p.Initialize()
+	p.KubeCfgPath = strings.Join([]string{overlay, kubeConfig}, string(filepath.ListSeparator))
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("namespace", "", "")
+	namespace, err := p.GetNamespace(cmd)
+	assert.NilError(t, err)
+	assert.Equal(t, namespace, "production")
+}
+
+func TestKubeConfigContextNamespace(t *testing.T) {
+	kubeConfig := filepath.Join(t.TempDir(), "config")
+	withNamespace := strings.Replace(testKubeConfig, "    cluster: dev\n", "    cluster: dev\n    namespace: team-a\n", 1)
+	assert.NilError(t, ioutil.WriteFile(kubeConfig, []byte(withNamespace), 0600))
+
+	runListTypes := func(args ...string) string {
+		mockClient := client.NewMockKameletClient(t)
+		mockClient.Recorder().List(&camelkapis.KameletList{}, nil)
+		p := &KameletPluginParams{
+			Context: context.TODO(),
+			NewKameletClient: func() (KameletClient, error) {
+				return mockClient, nil
+			},
+			NewKameletBindingClient: mockBindingClient(mockClient),
+		}
+		p.Initialize()
+		p.KubeCfgPath = kubeConfig
+
+		cmd := NewListTypesCommand(p)
+		output := &bytes.Buffer{}
+		cmd.SetOut(output)
+		cmd.SetArgs(args)
+		assert.NilError(t, cmd.Execute())
+		mockClient.Recorder().Validate()
+		return output.String()
+	}
+
+	// the namespace of the current context applies unless overridden with --namespace or --all-namespaces
+	assert.Equal(t, runListTypes(), "No Kamelets found in namespace team-a\n")
+	assert.Equal(t, runListTypes("--namespace", "team-b"), "No Kamelets found in namespace team-b\n")
+	assert.Equal(t, runListTypes("--all-namespaces"), "No Kamelets found.\n")
+}
+
+// writeTestKubeConfig writes a kubeconfig file with the clusters dev and prod and returns its path
+func writeTestKubeConfig(t *testing.T) string {
+	kubeConfig := filepath.Join(t.TempDir(), "config")
+	assert.NilError(t, ioutil.WriteFile(kubeConfig, []byte(testKubeConfig), 0600))
+	return kubeConfig
+}
+
+func parseKubeConfigFlags(t *testing.T, args ...string) *rest.Config {
+	restConfig, err := newKubeConfigTestParams(t, args...).restConfig()
+	assert.NilError(t, err)
+	return restConfig
+}
+
+// newKubeConfigTestParams returns the params configured by given kubeconfig flags
+func newKubeConfigTestParams(t *testing.T, args ...string) *KameletPluginParams {
+	p := &KameletPluginParams{
+		Context:  context.TODO(),
+		CacheDir: t.TempDir(),
+	}
+	p.Initialize()
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	p.AddKubeConfigFlags(flags)
+	assert.NilError(t, flags.Parse(args))
+	return p
+}
@@ -0,0 +1,343 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	knerrors "knative.dev/client/pkg/errors"
+	"knative.dev/client/pkg/kn/commands"
+)
+
+var updateExample = `
+  # Update the message property of an existing Kamelet binding
+  kn-source-kamelet update timer-binding -p message=Hi
+
+  # Point an existing Kamelet binding to another sink
+  kn-source-kamelet update timer-binding --sink broker:default
+
+  # Update the Kamelet binding or create it from given Kamelet source if it does not exist
+  kn-source-kamelet update timer-binding --force --kamelet timer-source --sink ksvc:my-service -p message=Hi
+
+  # Add a label to an existing Kamelet binding
+  kn-source-kamelet update timer-binding --label team=payments
+
+  # Print the updated Kamelet binding as YAML without changing it in the cluster
+  kn-source-kamelet update timer-binding -p message=Hi --dry-run=client`
+
+// updateOptions holds the flag values of the update command
+type updateOptions struct {
+	kamelet      string
+	sink         string
+	properties   []string
+	force        bool
+	dryRun       string
+	fieldManager string
+	labels       []string
+	annotations  []string
+	api          string
+}
+
+// NewUpdateCommand implements 'kn-source-kamelet update' command
+func NewUpdateCommand(p *KameletPluginParams) *cobra.Command {
+	options := &updateOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "update",
+		Short:   "Update an existing Kamelet binding",
+		Example: updateExample,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if len(args) != 1 {
+				return errors.New("'kn-source-kamelet update' requires the KameletBinding name given as single argument")
+			}
+			name := args[0]
+
+			if options.sink == "" && len(options.properties) == 0 && len(options.labels) == 0 && len(options.annotations) == 0 {
+				return errors.New("'kn-source-kamelet update' requires at least one change given with --sink, --property, --label or --annotation")
+			}
+
+			if err := validateDryRun(options.dryRun); err != nil {
+				return err
+			}
+			if err := validateFieldManager(options.fieldManager); err != nil {
+				return err
+			}
+
+			properties, err := parseProperties(options.properties)
+			if err != nil {
+				return err
+			}
+			if _, err := parseLabels(options.labels); err != nil {
+				return err
+			}
+			if _, err := parseAnnotations(options.annotations); err != nil {
+				return err
+			}
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := p.NewKameletClient()
+			if err != nil {
+				return err
+			}
+
+			api, err := p.resolveBindingAPI(options.api, false)
+			if err != nil {
+				return err
+			}
+			bindingClient := p.newBindingClient(api)
+
+			binding, err := p.getKameletBinding(bindingClient, namespace, name)
+			if isCamelKNotInstalled(err) {
+				return err
+			}
+			if apierrors.IsNotFound(err) {
+				if !options.force {
+					return fmt.Errorf("%s '%s' not found in namespace '%s', use --force to create it", bindingClient.kind(), name, namespace)
+				}
+				return createBindingOnUpdate(cmd, p, client, bindingClient, namespace, name, properties, options)
+			}
+			if err != nil {
+				return knerrors.GetError(err)
+			}
+
+			if options.dryRun == dryRunClient {
+				patch, err := createKameletBindingPatch(p, client, bindingClient.kind(), namespace, binding, properties, options)
+				if err != nil {
+					return err
+				}
+				updated, err := applyKameletBindingPatch(bindingClient.kind(), binding, patch)
+				if err != nil {
+					return err
+				}
+				return writeBindingObjects(cmd.OutOrStdout(), "yaml", bindingClient, []*v1alpha1.KameletBinding{updated})
+			}
+
+			// the patch holds the resource version of the binding it has been built from, so it conflicts with
+			// concurrent changes. The changes are then applied again to the binding fetched anew.
+			attempts := 0
+			err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+				if attempts > 0 {
+					binding, err = p.getKameletBinding(bindingClient, namespace, name)
+					if err != nil {
+						return err
+					}
+				}
+				attempts++
+				patch, err := createKameletBindingPatch(p, client, bindingClient.kind(), namespace, binding, properties, options)
+				if err != nil {
+					return err
+				}
+				_, err = bindingClient.patch(p.Context, namespace, name, types.MergePatchType, patch,
+					v1.PatchOptions{DryRun: dryRunOptions(options.dryRun), FieldManager: options.fieldManager})
+				return p.checkCamelKInstalled(err)
+			})
+			if apierrors.IsConflict(err) {
+				return fmt.Errorf("unable to update %s '%s' in namespace '%s', it has been changed concurrently "+
+					"during %d attempts: %w", bindingClient.kind(), name, namespace, attempts, err)
+			}
+			if isCamelKNotInstalled(err) {
+				return err
+			}
+			if err != nil {
+				return knerrors.GetError(err)
+			}
+
+			fmt.Fprintf(p.messageWriter(cmd.OutOrStdout()), "%s '%s' updated in namespace '%s'%s.\n", bindingClient.kind(), name, namespace,
+				dryRunSuffix(options.dryRun))
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	commands.AddNamespaceFlags(flags, false)
+	flags.StringVarP(&options.sink, "sink", "s", "", "Replaces the current sink. "+sinkUsage)
+	flags.StringArrayVarP(&options.properties, "property", "p", nil, "Kamelet property given as key=value pair. "+
+		"Can be given multiple times. Properties not given are preserved.")
+	flags.StringArrayVar(&options.labels, "label", nil, "Label given as key=value pair added to the Kamelet binding. "+
+		"Can be given multiple times. Existing labels not given are preserved.")
+	flags.StringArrayVar(&options.annotations, "annotation", nil, "Annotation given as key=value pair added to the Kamelet binding. "+
+		"Can be given multiple times. Existing annotations not given are preserved.")
+	flags.BoolVar(&options.force, "force", false, "Create the Kamelet binding if it does not exist. Requires --kamelet and --sink.")
+	flags.StringVar(&options.kamelet, "kamelet", "", "Name of the Kamelet source used when the binding gets created with --force.")
+	addDryRunFlag(flags, &options.dryRun)
+	addFieldManagerFlag(flags, &options.fieldManager)
+	addExistingBindingAPIFlag(flags, &options.api)
+	return cmd
+}
+
+// createBindingOnUpdate creates the Kamelet binding that has not been found when updating with --force
+func createBindingOnUpdate(cmd *cobra.Command, p *KameletPluginParams, client KameletClient,
+	bindingClient bindingClient, namespace string, name string, properties map[string]string, options *updateOptions) error {
+	if options.kamelet == "" || options.sink == "" {
+		return fmt.Errorf("%s '%s' not found in namespace '%s', creating it requires --kamelet and --sink", bindingClient.kind(),
+			name, namespace)
+	}
+
+	kamelet, err := p.getKamelet(client, namespace, options.kamelet)
+	if err != nil {
+		return knerrors.GetError(err)
+	}
+
+	if err := verifyKameletType(kamelet, kameletTypeSource); err != nil {
+		return err
+	}
+
+	binding, err := createKameletBinding(namespace, kamelet, properties, &bindOptions{name: name, sink: options.sink,
+		labels: options.labels, annotations: options.annotations})
+	if err != nil {
+		return err
+	}
+
+	if options.dryRun == dryRunClient {
+		return writeBindingObjects(cmd.OutOrStdout(), "yaml", bindingClient, []*v1alpha1.KameletBinding{binding})
+	}
+
+	binding, err = bindingClient.create(p.Context, binding, v1.CreateOptions{DryRun: dryRunOptions(options.dryRun),
+		FieldManager: options.fieldManager})
+	if err = p.checkCamelKInstalled(err); isCamelKNotInstalled(err) {
+		return err
+	}
+	if err != nil {
+		return knerrors.GetError(err)
+	}
+
+	fmt.Fprintf(p.messageWriter(cmd.OutOrStdout()), "%s '%s' created in namespace '%s'%s.\n", bindingClient.kind(), binding.Name,
+		namespace, dryRunSuffix(options.dryRun))
+	return nil
+}
+
+// applyKameletBindingPatch applies given JSON merge patch to a copy of given binding the same way the API server would,
+// errors name the binding by given kind
+func applyKameletBindingPatch(kind string, binding *v1alpha1.KameletBinding, patch []byte) (*v1alpha1.KameletBinding, error) {
+	original, err := json.Marshal(binding)
+	if err != nil {
+		return nil, err
+	}
+	data, err := jsonpatch.MergePatch(original, patch)
+	if err != nil {
+		return nil, fmt.Errorf("unable to apply changes to %s '%s': %w", kind, binding.Name, err)
+	}
+	updated := &v1alpha1.KameletBinding{}
+	if err := json.Unmarshal(data, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// createKameletBindingPatch builds the JSON merge patch applying the property, sink, label and annotation changes to
+// given binding. Properties, labels and annotations not mentioned in the changes are left untouched by the merge patch.
+// The resource version of the binding is part of the patch, so that it fails with a conflict if the binding has been
+// changed in the meantime. Errors name the binding by given kind.
+func createKameletBindingPatch(p *KameletPluginParams, client KameletClient, kind string, namespace string,
+	binding *v1alpha1.KameletBinding, properties map[string]string, options *updateOptions) ([]byte, error) {
+	spec := map[string]interface{}{}
+
+	if len(properties) > 0 {
+		source := binding.Spec.Source.Ref
+		if source == nil || source.Kind != v1alpha1.KameletKind {
+			return nil, fmt.Errorf("%s '%s' has no Kamelet source, properties can not be updated", kind, binding.Name)
+		}
+
+		kamelet, err := p.getKamelet(client, namespace, source.Name)
+		if err != nil {
+			return nil, knerrors.GetError(err)
+		}
+
+		typed, err := convertProperties(kamelet, properties, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		current, err := endpointPropertyValues(binding.Spec.Source.Properties)
+		if err != nil {
+			return nil, err
+		}
+		for propertyName, value := range properties {
+			current[propertyName] = value
+		}
+		if err := verifyRequiredProperties(kamelet, current); err != nil {
+			return nil, err
+		}
+
+		spec["source"] = map[string]interface{}{"properties": typed}
+	}
+
+	if options.sink != "" {
+		sink, err := parseSink(options.sink, namespace)
+		if err != nil {
+			return nil, err
+		}
+		// explicitly reset the other sink kind as merge patches keep fields not mentioned
+		spec["sink"] = map[string]interface{}{"ref": sink.Ref, "uri": sink.URI}
+	}
+
+	patch := map[string]interface{}{}
+	if len(spec) > 0 {
+		patch["spec"] = spec
+	}
+	labels, err := parseLabels(options.labels)
+	if err != nil {
+		return nil, err
+	}
+	annotations, err := parseAnnotations(options.annotations)
+	if err != nil {
+		return nil, err
+	}
+	metadata := map[string]interface{}{}
+	if len(labels) > 0 {
+		metadata["labels"] = labels
+	}
+	if len(annotations) > 0 {
+		metadata["annotations"] = annotations
+	}
+	if binding.ResourceVersion != "" {
+		metadata["resourceVersion"] = binding.ResourceVersion
+	}
+	if len(metadata) > 0 {
+		patch["metadata"] = metadata
+	}
+	return json.Marshal(patch)
+}
+
+// endpointPropertyValues returns the properties set on an endpoint mapped to their raw JSON values
+func endpointPropertyValues(properties *v1alpha1.EndpointProperties) (map[string]string, error) {
+	names := map[string]string{}
+	if properties == nil || len(properties.RawMessage) == 0 {
+		return names, nil
+	}
+	values := map[string]json.RawMessage{}
+	if err := json.Unmarshal(properties.RawMessage, &values); err != nil {
+		return nil, fmt.Errorf("unable to read current endpoint properties: %w", err)
+	}
+	for name, value := range values {
+		names[name] = string(value)
+	}
+	return names, nil
+}
@@ -0,0 +1,401 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	camelkapis "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/util"
+	"knative.dev/client/pkg/util/mock"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestUpdateSetup(t *testing.T) {
+	p := KameletPluginParams{
+		Context: context.TODO(),
+	}
+
+	updateCmd := NewUpdateCommand(&p)
+	assert.Equal(t, updateCmd.Use, "update")
+	assert.Equal(t, updateCmd.Short, "Update an existing Kamelet binding")
+	assert.Assert(t, updateCmd.RunE != nil)
+}
+
+func TestUpdateErrorCaseMissingArgument(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+
+	_, err := runUpdateCmd(mockClient, "-p", "message=Hi")
+	assert.Error(t, err, "'kn-source-kamelet update' requires the KameletBinding name given as single argument")
+
+	_, err = runUpdateCmd(mockClient, "k1-binding")
+	assert.Error(t, err, "'kn-source-kamelet update' requires at least one change given with --sink, --property, --label or --annotation")
+	mockClient.Recorder().Validate()
+}
+
+func TestUpdateErrorCaseNotFound(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	bindingRecorder := mockClient.BindingRecorder()
+
+	bindingRecorder.Get("k1-binding", nil, newBindingNotFoundError("k1-binding"))
+
+	_, err := runUpdateCmd(mockClient, "k1-binding", "-p", "message=Hi")
+	assert.Error(t, err, "KameletBinding 'k1-binding' not found in namespace 'current', use --force to create it")
+	bindingRecorder.Validate()
+}
+
+func TestUpdateProperties(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "message", "string", "The message to send", true)
+	addKameletProperty(kamelet, "period", "integer", "Delay between messages", false)
+	recorder.Get(kamelet, nil)
+
+	binding := createKameletBindingFor("k1", "k1-binding")
+	setBindingProperties(t, binding, `{"message":"Hello"}`)
+	bindingRecorder.Get("k1-binding", binding, nil)
+	bindingRecorder.Patch("k1-binding", types.MergePatchType, `{"spec":{"source":{"properties":{"period":5000}}}}`, binding, nil)
+
+	output, err := runUpdateCmd(mockClient, "k1-binding", "-p", "period=5000")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "KameletBinding", "k1-binding", "updated", "namespace", "current"))
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestUpdateRetryOnConflict(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "message", "string", "The message to send", true)
+	addKameletProperty(kamelet, "period", "integer", "Delay between messages", false)
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+
+	binding := createKameletBindingFor("k1", "k1-binding")
+	binding.ResourceVersion = "1"
+	setBindingProperties(t, binding, `{"message":"Hello"}`)
+	changed := binding.DeepCopy()
+	changed.ResourceVersion = "2"
+	conflict := apierrors.NewConflict(schema.GroupResource{Group: "camel.apache.org", Resource: "kameletbindings"}, "k1-binding",
+		errors.New("the object has been modified"))
+
+	bindingRecorder.Get("k1-binding", binding, nil)
+	bindingRecorder.Patch("k1-binding", types.MergePatchType,
+		`{"metadata":{"resourceVersion":"1"},"spec":{"source":{"properties":{"period":5000}}}}`, nil, conflict)
+	bindingRecorder.Get("k1-binding", changed, nil)
+	bindingRecorder.Patch("k1-binding", types.MergePatchType,
+		`{"metadata":{"resourceVersion":"2"},"spec":{"source":{"properties":{"period":5000}}}}`, changed, nil)
+
+	output, err := runUpdateCmd(mockClient, "k1-binding", "-p", "period=5000")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "KameletBinding 'k1-binding' updated in namespace 'current'.\n")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestUpdateErrorCaseConflict(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	bindingRecorder := mockClient.BindingRecorder()
+
+	binding := createKameletBindingFor("k1", "k1-binding")
+	binding.ResourceVersion = "1"
+	conflict := apierrors.NewConflict(schema.GroupResource{Group: "camel.apache.org", Resource: "kameletbindings"}, "k1-binding",
+		errors.New("the object has been modified"))
+	bindingRecorder.Get("k1-binding", binding, nil)
+	for i := 0; i < retry.DefaultRetry.Steps; i++ {
+		if i > 0 {
+			bindingRecorder.Get("k1-binding", binding, nil)
+		}
+		bindingRecorder.Patch("k1-binding", types.MergePatchType, mock.Any(), nil, conflict)
+	}
+
+	_, err := runUpdateCmd(mockClient, "k1-binding", "--label", "team=payments")
+	assert.ErrorContains(t, err, "unable to update KameletBinding 'k1-binding' in namespace 'current', "+
+		"it has been changed concurrently during 5 attempts: ")
+	assert.Assert(t, apierrors.IsConflict(err))
+
+	bindingRecorder.Validate()
+}
+
+func TestUpdateDryRunClient(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "message", "string", "The message to send", true)
+	addKameletProperty(kamelet, "period", "integer", "Delay between messages", false)
+	recorder.Get(kamelet, nil)
+
+	binding := createKameletBindingFor("k1", "k1-binding")
+	setBindingProperties(t, binding, `{"message":"Hello"}`)
+	bindingRecorder.Get("k1-binding", binding, nil)
+
+	output, err := runUpdateCmd(mockClient, "k1-binding", "-p", "period=5000", "--sink", "broker:default", "--dry-run=client")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "kind: KameletBinding", "name: k1-binding", "message: Hello", "period: 5000", "kind: Broker"))
+	assert.Assert(t, util.ContainsNone(output, "updated"))
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestUpdateDryRunServer(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	bindingRecorder := mockClient.BindingRecorder()
+
+	binding := createKameletBindingFor("k1", "k1-binding")
+	bindingRecorder.Get("k1-binding", binding, nil)
+	bindingRecorder.PatchWithOptions("k1-binding", types.MergePatchType, mock.Any(),
+		v1.PatchOptions{DryRun: []string{v1.DryRunAll}, FieldManager: defaultFieldManager}, binding, nil)
+
+	output, err := runUpdateCmd(mockClient, "k1-binding", "--sink", "broker:default", "--dry-run=server")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "KameletBinding 'k1-binding' updated in namespace 'current' (server dry run).\n")
+
+	bindingRecorder.Validate()
+}
+
+func TestUpdateErrorCaseProperties(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "message", "string", "The message to send", true)
+	addKameletProperty(kamelet, "period", "integer", "Delay between messages", false)
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+
+	binding := createKameletBindingFor("k1", "k1-binding")
+	bindingRecorder.Get("k1-binding", binding, nil)
+	bindingRecorder.Get("k1-binding", binding, nil)
+
+	_, err := runUpdateCmd(mockClient, "k1-binding", "-p", "period=soon")
+	assert.Error(t, err, "invalid value 'soon' for property 'period', expected type integer")
+
+	_, err = runUpdateCmd(mockClient, "k1-binding", "-p", "period=1000")
+	assert.Error(t, err, "missing required properties for Kamelet k1: message")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestUpdateSink(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	bindingRecorder := mockClient.BindingRecorder()
+
+	binding := createKameletBindingFor("k1", "k1-binding")
+	bindingRecorder.Get("k1-binding", binding, nil)
+	bindingRecorder.Patch("k1-binding", types.MergePatchType,
+		`{"spec":{"sink":{"ref":null,"uri":"https://event.receiver.uri"}}}`, binding, nil)
+	bindingRecorder.Get("k1-binding", binding, nil)
+	bindingRecorder.Patch("k1-binding", types.MergePatchType,
+		`{"spec":{"sink":{"ref":{"kind":"Broker","namespace":"current","name":"default","apiVersion":"eventing.knative.dev/v1"},"uri":null}}}`, binding, nil)
+
+	_, err := runUpdateCmd(mockClient, "k1-binding", "--sink", "https://event.receiver.uri")
+	assert.NilError(t, err)
+
+	_, err = runUpdateCmd(mockClient, "k1-binding", "--sink", "broker:default")
+	assert.NilError(t, err)
+
+	bindingRecorder.Validate()
+}
+
+func TestUpdateForceCreate(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "message", "string", "The message to send", true)
+	recorder.Get(kamelet, nil)
+
+	expected := createKameletBindingFor("k1", "k1-binding")
+	uri := "https://event.receiver.uri"
+	expected.Spec.Sink = camelkapis.Endpoint{URI: &uri}
+	setBindingProperties(t, expected, `{"message":"Hi"}`)
+	bindingRecorder.Get("k1-binding", nil, newBindingNotFoundError("k1-binding"))
+	bindingRecorder.Get("k1-binding", nil, newBindingNotFoundError("k1-binding"))
+	bindingRecorder.Create(expected, nil)
+
+	_, err := runUpdateCmd(mockClient, "k1-binding", "--force", "-p", "message=Hi")
+	assert.Error(t, err, "KameletBinding 'k1-binding' not found in namespace 'current', creating it requires --kamelet and --sink")
+
+	output, err := runUpdateCmd(mockClient, "k1-binding", "--force", "--kamelet", "k1", "--sink", uri, "-p", "message=Hi")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "KameletBinding", "k1-binding", "created", "namespace", "current"))
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestUpdateLabels(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	bindingRecorder := mockClient.BindingRecorder()
+
+	binding := createKameletBindingFor("k1", "k1-binding")
+	bindingRecorder.Get("k1-binding", binding, nil)
+	bindingRecorder.Patch("k1-binding", types.MergePatchType,
+		`{"metadata":{"annotations":{"owner":"jane"},"labels":{"team":"payments"}}}`, binding, nil)
+
+	output, err := runUpdateCmd(mockClient, "k1-binding", "--label", "team=payments", "--annotation", "owner=jane")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "KameletBinding 'k1-binding' updated in namespace 'current'.\n")
+
+	_, err = runUpdateCmd(mockClient, "k1-binding", "--label", "team")
+	assert.Error(t, err, "invalid label 'team', expected format key=value")
+
+	bindingRecorder.Validate()
+}
+
+func TestUpdateForceCreateLabels(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+	bindingRecorder := mockClient.BindingRecorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+
+	expected := createKameletBindingFor("k1", "k1-binding")
+	uri := "https://event.receiver.uri"
+	expected.Spec.Sink = camelkapis.Endpoint{URI: &uri}
+	expected.Labels["team"] = "payments"
+	expected.Annotations = map[string]string{"owner": "jane"}
+	bindingRecorder.Get("k1-binding", nil, newBindingNotFoundError("k1-binding"))
+	bindingRecorder.Create(expected, nil)
+
+	output, err := runUpdateCmd(mockClient, "k1-binding", "--force", "--kamelet", "k1", "--sink", uri,
+		"--label", "team=payments", "--annotation", "owner=jane")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "KameletBinding 'k1-binding' created in namespace 'current'.\n")
+
+	recorder.Validate()
+	bindingRecorder.Validate()
+}
+
+func TestUpdateErrorCasePatch(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	bindingRecorder := mockClient.BindingRecorder()
+
+	binding := createKameletBindingFor("k1", "k1-binding")
+	bindingRecorder.Get("k1-binding", binding, nil)
+	bindingRecorder.Patch("k1-binding", types.MergePatchType, mock.Any(), nil, errors.New("forbidden"))
+
+	_, err := runUpdateCmd(mockClient, "k1-binding", "--sink", "broker:default")
+	assert.Error(t, err, "forbidden")
+
+	bindingRecorder.Validate()
+}
+
+func runUpdateCmd(c *client.MockKameletClient, options ...string) (string, error) {
+	p := &KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+	}
+	return runUpdateCmdWithParams(p, c, options...)
+}
+
+func runUpdateCmdWithParams(p *KameletPluginParams, c *client.MockKameletClient, options ...string) (string, error) {
+	p.NewKameletClient = func() (KameletClient, error) {
+		return c, nil
+	}
+	p.NewKameletBindingClient = mockBindingClient(c)
+	if p.NewDiscoveryClient == nil {
+		p.NewDiscoveryClient = func() (discovery.ServerResourcesInterface, error) {
+			return newKameletBindingDiscovery(), nil
+		}
+	}
+
+	updateCmd, _, output := commands.CreateSourcesTestKnCommand(NewUpdateCommand(p), p.KnParams)
+
+	args := []string{"update"}
+	args = append(args, options...)
+	updateCmd.SetArgs(args)
+	err := updateCmd.Execute()
+
+	return output.String(), err
+}
+
+func TestUpdatePipe(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "message", "string", "The message to send", true)
+	recorder.Get(kamelet, nil)
+
+	binding := createKameletBindingFor("k1", "k1-binding")
+	setBindingProperties(t, binding, `{"message":"Hello"}`)
+	pipe, err := asPipe(binding)
+	assert.NilError(t, err)
+	uriBinding := createKameletBindingFor("k1", "uri-binding")
+	uri := "https://example.com/events"
+	uriBinding.Spec.Source = camelkapis.Endpoint{URI: &uri}
+	uriPipe, err := asPipe(uriBinding)
+	assert.NilError(t, err)
+	pipeClient := newFakePipeClient(pipe, uriPipe)
+	p := &KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewPipeClient: func() (dynamic.Interface, error) {
+			return pipeClient, nil
+		},
+		NewDiscoveryClient: func() (discovery.ServerResourcesInterface, error) {
+			return newPipeDiscovery(), nil
+		},
+	}
+
+	// the Pipe created by bind with --api auto is updated, not a Kamelet binding
+	output, err := runUpdateCmdWithParams(p, mockClient, "k1-binding", "-p", "message=Hi")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "Pipe 'k1-binding' updated in namespace 'current'.\n")
+
+	updated, err := pipeClient.Resource(pipeResource).Namespace("current").Get(context.TODO(), "k1-binding", v1.GetOptions{})
+	assert.NilError(t, err)
+	message, _, _ := unstructured.NestedString(updated.Object, "spec", "source", "properties", "message")
+	assert.Equal(t, message, "Hi")
+
+	_, err = runUpdateCmdWithParams(p, mockClient, "other-binding", "-p", "message=Hi")
+	assert.Error(t, err, "Pipe 'other-binding' not found in namespace 'current', use --force to create it")
+
+	// errors name the Pipe as well
+	_, err = runUpdateCmdWithParams(p, mockClient, "uri-binding", "-p", "message=Hi")
+	assert.Error(t, err, "Pipe 'uri-binding' has no Kamelet source, properties can not be updated")
+
+	recorder.Validate()
+}
@@ -0,0 +1,181 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"github.com/spf13/cobra"
+
+	knerrors "knative.dev/client/pkg/errors"
+	"knative.dev/client/pkg/kn/commands"
+)
+
+var verifyExample = `
+  # Verify that a Kamelet source can be bound to a Knative broker with given properties
+  kn-source-kamelet verify timer-source --sink broker:default -p message=Hello
+
+  # Verify a binding reading the properties from a YAML file
+  kn-source-kamelet verify timer-source --sink ksvc:my-service --properties-file timer.yaml
+
+  # Verify a binding warning if the Kamelet does not produce JSON
+  kn-source-kamelet verify timer-source --sink broker:default --expected-type application/json`
+
+// verifyCheck is the result of a single pre-flight check of the verify command
+type verifyCheck struct {
+	description string
+	err         error
+}
+
+// NewVerifyCommand implements 'kn-source-kamelet verify' command
+func NewVerifyCommand(p *KameletPluginParams) *cobra.Command {
+	var sink string
+	var properties []string
+	var propertiesFile string
+	var expectedType string
+
+	cmd := &cobra.Command{
+		Use:               "verify",
+		Short:             "Verify that a Kamelet source can be bound to given sink",
+		Example:           verifyExample,
+		ValidArgsFunction: completeKameletNames(p, kameletTypeSource, 1),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if len(args) != 1 {
+				return errors.New("'kn-source-kamelet verify' requires the Kamelet name given as single argument")
+			}
+			kameletName := args[0]
+
+			if sink == "" {
+				return errors.New("'kn-source-kamelet verify' requires the sink to be specified with --sink")
+			}
+			if expectedType != "" {
+				if mediaType, _, err := mime.ParseMediaType(expectedType); err != nil || !strings.Contains(mediaType, "/") {
+					return fmt.Errorf("invalid expected type '%s', must be a media type like application/json", expectedType)
+				}
+			}
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := p.NewKameletClient()
+			if err != nil {
+				return err
+			}
+
+			kamelet, err := p.getKamelet(client, namespace, kameletName)
+			if err != nil {
+				return knerrors.GetError(err)
+			}
+
+			propertyValues, err := collectProperties(cmd.InOrStdin(), propertiesFile, properties)
+			if err != nil {
+				return err
+			}
+
+			checks := verifyKameletBinding(namespace, kamelet, propertyValues, sink)
+			if warning := dataTypeWarning(kamelet, expectedType); warning != "" {
+				fmt.Fprintln(cmd.ErrOrStderr(), warning)
+			}
+			if !writeVerifyChecks(cmd.OutOrStdout(), checks) {
+				return fmt.Errorf("verification of Kamelet '%s' with sink '%s' failed", kameletName, sink)
+			}
+			fmt.Fprintf(p.messageWriter(cmd.OutOrStdout()), "Kamelet '%s' can be bound to sink '%s'.\n", kameletName, sink)
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	commands.AddNamespaceFlags(flags, false)
+	flags.StringVarP(&sink, "sink", "s", "", sinkUsage)
+	flags.StringArrayVarP(&properties, "property", "p", nil, "Kamelet property given as key=value pair. Can be given multiple times.")
+	flags.StringVar(&propertiesFile, "properties-file", "", "YAML or JSON file holding Kamelet properties as top level keys. "+
+		"Use '-' to read from stdin. Properties given with --property take precedence.")
+	flags.StringVar(&expectedType, "expected-type", "", "Media type the sink expects, e.g. application/json. "+
+		"A warning is printed if the Kamelet declares a different output type.")
+	return cmd
+}
+
+// verifyKameletBinding runs the pre-flight checks of the bind command for given Kamelet, properties and sink
+// without creating anything on the cluster
+func verifyKameletBinding(namespace string, kamelet *v1alpha1.Kamelet, propertyValues map[string]string, sink string) []verifyCheck {
+	checks := []verifyCheck{
+		{
+			description: fmt.Sprintf("Kamelet %s is a source", kamelet.Name),
+			err:         verifyKameletType(kamelet, kameletTypeSource),
+		},
+	}
+
+	_, err := validateProperties(kamelet, propertyValues, nil, false)
+	checks = append(checks, verifyCheck{
+		description: "properties are valid and required properties are given or have defaults",
+		err:         err,
+	})
+
+	endpoint, err := parseSink(sink, namespace)
+	sinkCheck := verifyCheck{err: err}
+	switch {
+	case err != nil:
+		sinkCheck.description = fmt.Sprintf("sink %s resolves", sink)
+	case endpoint.URI != nil:
+		sinkCheck.description = fmt.Sprintf("sink resolves to URI %s", *endpoint.URI)
+	default:
+		sinkCheck.description = fmt.Sprintf("sink resolves to %s %s in namespace %s", endpoint.Ref.Kind, endpoint.Ref.Name, endpoint.Ref.Namespace)
+	}
+	return append(checks, sinkCheck)
+}
+
+// dataTypeWarning returns a warning if the output type declared by given Kamelet differs from the expected media type,
+// media type parameters like the charset are ignored. No warning is returned for Kamelets declaring no output type.
+func dataTypeWarning(kamelet *v1alpha1.Kamelet, expectedType string) string {
+	if expectedType == "" {
+		return ""
+	}
+	declared := kamelet.Spec.Types[v1alpha1.EventSlotOut].MediaType
+	if declared == "" {
+		return ""
+	}
+	declaredMediaType, _, err := mime.ParseMediaType(declared)
+	if err != nil {
+		declaredMediaType = declared
+	}
+	expectedMediaType, _, _ := mime.ParseMediaType(expectedType)
+	if declaredMediaType == expectedMediaType {
+		return ""
+	}
+	return fmt.Sprintf("Warning: Kamelet %s produces %s but %s is expected, the events may require a type conversion.",
+		kamelet.Name, declared, expectedType)
+}
+
+// writeVerifyChecks prints an OK/NOT-OK line per check and returns true if all checks passed
+func writeVerifyChecks(out io.Writer, checks []verifyCheck) bool {
+	ok := true
+	for _, check := range checks {
+		if check.err == nil {
+			fmt.Fprintf(out, "%-7s %s\n", "OK", check.description)
+			continue
+		}
+		ok = false
+		fmt.Fprintf(out, "%-7s %s: %s\n", "NOT-OK", check.description, check.err.Error())
+	}
+	return ok
+}
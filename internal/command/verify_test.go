@@ -0,0 +1,180 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	camelkapis "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/util"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestVerifySetup(t *testing.T) {
+	p := KameletPluginParams{
+		Context: context.TODO(),
+	}
+
+	verifyCmd := NewVerifyCommand(&p)
+	assert.Equal(t, verifyCmd.Use, "verify")
+	assert.Equal(t, verifyCmd.Short, "Verify that a Kamelet source can be bound to given sink")
+	assert.Assert(t, verifyCmd.RunE != nil)
+}
+
+func TestVerifyErrorCaseMissingArgument(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+
+	_, err := runVerifyCmd(mockClient, "--sink", "broker:default")
+	assert.Error(t, err, "'kn-source-kamelet verify' requires the Kamelet name given as single argument")
+
+	_, err = runVerifyCmd(mockClient, "k1")
+	assert.Error(t, err, "'kn-source-kamelet verify' requires the sink to be specified with --sink")
+	mockClient.Recorder().Validate()
+}
+
+func TestVerifyOK(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	addKameletProperty(kamelet, "message", "string", "The message to send", true)
+	addKameletProperty(kamelet, "period", "integer", "Delay between messages", true)
+	setKameletPropertyDefault(kamelet, "period", "1000")
+	recorder.Get(kamelet, nil)
+
+	output, err := runVerifyCmd(mockClient, "k1", "--sink", "broker:default", "-p", "message=Hi")
+	assert.NilError(t, err)
+	outputLines := strings.Split(output, "\n")
+	assert.Check(t, util.ContainsAll(outputLines[0], "OK", "Kamelet k1 is a source"))
+	assert.Check(t, util.ContainsAll(outputLines[1], "OK", "properties are valid"))
+	assert.Check(t, util.ContainsAll(outputLines[2], "OK", "sink resolves to Broker default in namespace current"))
+	assert.Check(t, util.ContainsAll(outputLines[3], "Kamelet 'k1' can be bound to sink 'broker:default'."))
+	assert.Check(t, util.ContainsNone(output, "NOT-OK"))
+
+	recorder.Validate()
+}
+
+func TestVerifyNotOK(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	kamelet.Labels[kameletTypeLabel] = kameletTypeSink
+	addKameletProperty(kamelet, "message", "string", "The message to send", true)
+	recorder.Get(kamelet, nil)
+
+	output, err := runVerifyCmd(mockClient, "k1", "--sink", "svc:receiver")
+	assert.Error(t, err, "verification of Kamelet 'k1' with sink 'svc:receiver' failed")
+	outputLines := strings.Split(output, "\n")
+	assert.Check(t, util.ContainsAll(outputLines[0], "NOT-OK", "Kamelet k1 is a sink, not a source"))
+	assert.Check(t, util.ContainsAll(outputLines[1], "NOT-OK", "missing required properties for Kamelet k1: message"))
+	assert.Check(t, util.ContainsAll(outputLines[2], "NOT-OK", "unsupported sink prefix 'svc'"))
+
+	recorder.Validate()
+}
+
+func TestVerifyExpectedType(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	kamelet.Spec.Types = map[camelkapis.EventSlot]camelkapis.EventTypeSpec{
+		camelkapis.EventSlotOut: {MediaType: "text/plain"},
+	}
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+
+	p := &KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewKameletClient: func() (KameletClient, error) {
+			return mockClient, nil
+		},
+		NewKameletBindingClient: mockBindingClient(mockClient),
+	}
+	verify := func(args ...string) (string, string, error) {
+		cmd := NewVerifyCommand(p)
+		stdout := &strings.Builder{}
+		stderr := &strings.Builder{}
+		cmd.SetOut(stdout)
+		cmd.SetErr(stderr)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return stdout.String(), stderr.String(), err
+	}
+
+	stdout, stderr, err := verify("k1", "--sink", "broker:default", "--expected-type", "application/json")
+	assert.NilError(t, err)
+	assert.Equal(t, stderr, "Warning: Kamelet k1 produces text/plain but application/json is expected, "+
+		"the events may require a type conversion.\n")
+	assert.Check(t, util.ContainsAll(stdout, "Kamelet 'k1' can be bound to sink 'broker:default'."))
+
+	// media type parameters are ignored
+	_, stderr, err = verify("k1", "--sink", "broker:default", "--expected-type", "Text/Plain; charset=utf-8")
+	assert.NilError(t, err)
+	assert.Equal(t, stderr, "")
+
+	// no warning for Kamelets declaring no types
+	kamelet.Spec.Types = nil
+	_, stderr, err = verify("k1", "--sink", "broker:default", "--expected-type", "application/json")
+	assert.NilError(t, err)
+	assert.Equal(t, stderr, "")
+
+	_, err = runVerifyCmd(mockClient, "k1", "--sink", "broker:default", "--expected-type", "json")
+	assert.Error(t, err, "invalid expected type 'json', must be a media type like application/json")
+
+	recorder.Validate()
+}
+
+func TestVerifyErrorCaseNotFound(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKamelet("k1"), errors.New("not found"))
+
+	_, err := runVerifyCmd(mockClient, "k1", "--sink", "broker:default")
+	assert.Error(t, err, "not found")
+
+	recorder.Validate()
+}
+
+func runVerifyCmd(c *client.MockKameletClient, options ...string) (string, error) {
+	p := KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewKameletClient: func() (KameletClient, error) {
+			return c, nil
+		},
+		NewKameletBindingClient: mockBindingClient(c),
+	}
+
+	verifyCmd, _, output := commands.CreateSourcesTestKnCommand(NewVerifyCommand(&p), p.KnParams)
+
+	args := []string{"verify"}
+	args = append(args, options...)
+	verifyCmd.SetArgs(args)
+	err := verifyCmd.Execute()
+
+	return output.String(), err
+}
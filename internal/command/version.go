@@ -15,26 +15,131 @@
 package command
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"runtime"
+	"strings"
 
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
 	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/yaml"
 )
 
 var Version string
 var BuildDate string
 var GitRevision string
 
+// camelKAPIVersions are the versions of the Camel K API group reported by the version command, newest first
+var camelKAPIVersions = []string{"v1", "v1alpha1"}
+
+// versionInfo is the version information printed by the version command
+type versionInfo struct {
+	Version     string `json:"version"`
+	BuildDate   string `json:"buildDate"`
+	GitRevision string `json:"gitRevision"`
+	GoVersion   string `json:"goVersion"`
+	// CamelKAPIs holds the Camel K API group versions served by the cluster, nil if they could not be discovered
+	CamelKAPIs []string `json:"camelKAPIs"`
+}
+
 // NewVersionCommand implements 'kn-source-kamelet version' command
-func NewVersionCommand() *cobra.Command {
-	return &cobra.Command{
+func NewVersionCommand(p *KameletPluginParams) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "Prints the plugin version",
+		Long: "Prints the plugin version, the Go version it has been built with and the Camel K API versions served by " +
+			"the cluster. Only the plugin information is printed if the cluster can not be reached.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			out := cmd.OutOrStdout()
-			fmt.Fprintf(out, "Version:      %s\n", Version)
-			fmt.Fprintf(out, "Build Date:   %s\n", BuildDate)
-			fmt.Fprintf(out, "Git Revision: %s\n", GitRevision)
-			return nil
+			switch output {
+			case "", "yaml", "json":
+			default:
+				return fmt.Errorf("invalid output format '%s', must be one of: yaml, json", output)
+			}
+
+			info := versionInfo{
+				Version:     Version,
+				BuildDate:   BuildDate,
+				GitRevision: GitRevision,
+				GoVersion:   runtime.Version(),
+			}
+			apis, err := discoverCamelKAPIs(p)
+			if err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: unable to discover the Camel K APIs of the cluster: %v\n", err)
+			} else {
+				info.CamelKAPIs = apis
+			}
+			return writeVersionInfo(cmd.OutOrStdout(), output, info)
 		},
 	}
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output format. One of: yaml|json.")
+	return cmd
+}
+
+// discoverCamelKAPIs returns the Camel K API group versions served by the cluster, newest first
+func discoverCamelKAPIs(p *KameletPluginParams) ([]string, error) {
+	if p.NewDiscoveryClient == nil {
+		return nil, fmt.Errorf("no cluster connection configured")
+	}
+	discoveryClient, err := p.NewDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+	return servedGroupVersions(discoveryClient, v1alpha1.SchemeGroupVersion.Group, camelKAPIVersions)
+}
+
+// servedGroupVersions returns those of given versions of given API group that are served by the cluster
+func servedGroupVersions(discoveryClient discovery.ServerResourcesInterface, group string, versions []string) ([]string, error) {
+	served := []string{}
+	for _, version := range versions {
+		groupVersion := schema.GroupVersion{Group: group, Version: version}.String()
+		_, err := discoveryClient.ServerResourcesForGroupVersion(groupVersion)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		served = append(served, groupVersion)
+	}
+	return served, nil
+}
+
+// writeVersionInfo prints given version information in given output format, the Camel K APIs are left out
+// of the human readable output if they have not been discovered
+func writeVersionInfo(out io.Writer, output string, info versionInfo) error {
+	switch output {
+	case "json":
+		data, err := json.MarshalIndent(info, "", "    ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(data))
+		return err
+	case "yaml":
+		data, err := yaml.Marshal(info)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		return err
+	}
+
+	fmt.Fprintf(out, "Version:      %s\n", info.Version)
+	fmt.Fprintf(out, "Build Date:   %s\n", info.BuildDate)
+	fmt.Fprintf(out, "Git Revision: %s\n", info.GitRevision)
+	fmt.Fprintf(out, "Go Version:   %s\n", info.GoVersion)
+	if info.CamelKAPIs != nil {
+		apis := "none"
+		if len(info.CamelKAPIs) > 0 {
+			apis = strings.Join(info.CamelKAPIs, ", ")
+		}
+		fmt.Fprintf(out, "Camel K APIs: %s\n", apis)
+	}
+	return nil
 }
@@ -16,15 +16,21 @@ package command
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"runtime"
 	"testing"
 
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+
 	"gotest.tools/v3/assert"
 )
 
 var versionOutputTemplate = `Version:      %s
 Build Date:   %s
 Git Revision: %s
+Go Version:   %s
 `
 
 const (
@@ -34,7 +40,7 @@ const (
 )
 
 func TestVersionSetup(t *testing.T) {
-	versionCmd := NewVersionCommand()
+	versionCmd := NewVersionCommand(&KameletPluginParams{})
 	assert.Equal(t, versionCmd.Use, "version")
 	assert.Equal(t, versionCmd.Short, "Prints the plugin version")
 	assert.Assert(t, versionCmd.RunE != nil)
@@ -44,18 +50,65 @@ func TestVersionOutput(t *testing.T) {
 	Version = fakeVersion
 	BuildDate = fakeBuildDate
 	GitRevision = fakeGitRevision
-	expectedOutput := fmt.Sprintf(versionOutputTemplate, fakeVersion, fakeBuildDate, fakeGitRevision)
+	expectedOutput := fmt.Sprintf(versionOutputTemplate, fakeVersion, fakeBuildDate, fakeGitRevision, runtime.Version())
 
-	out, err := runVersionCmd()
+	// only the plugin information is printed when the cluster is not reachable
+	out, err := runVersionCmd(&fakeDiscovery{err: errors.New("connection refused")})
 	assert.NilError(t, err)
 	assert.Equal(t, out, expectedOutput)
+
+	out, err = runVersionCmd(&fakeDiscovery{})
+	assert.NilError(t, err)
+	assert.Equal(t, out, expectedOutput+"Camel K APIs: none\n")
+
+	discoveryClient := newPipeDiscovery()
+	discoveryClient.resources["camel.apache.org/v1alpha1"] = &v1.APIResourceList{GroupVersion: "camel.apache.org/v1alpha1"}
+	out, err = runVersionCmd(discoveryClient)
+	assert.NilError(t, err)
+	assert.Equal(t, out, expectedOutput+"Camel K APIs: camel.apache.org/v1, camel.apache.org/v1alpha1\n")
+}
+
+func TestVersionStructuredOutput(t *testing.T) {
+	Version = fakeVersion
+	BuildDate = fakeBuildDate
+	GitRevision = fakeGitRevision
+
+	out, err := runVersionCmd(newPipeDiscovery(), "-o", "yaml")
+	assert.NilError(t, err)
+	assert.Equal(t, out, fmt.Sprintf(`buildDate: fake-build-date
+camelKAPIs:
+- camel.apache.org/v1
+gitRevision: fake-git-revision
+goVersion: %s
+version: fake-version
+`, runtime.Version()))
+
+	out, err = runVersionCmd(&fakeDiscovery{err: errors.New("connection refused")}, "-o", "json")
+	assert.NilError(t, err)
+	assert.Equal(t, out, fmt.Sprintf(`{
+    "version": "fake-version",
+    "buildDate": "fake-build-date",
+    "gitRevision": "fake-git-revision",
+    "goVersion": "%s",
+    "camelKAPIs": null
+}
+`, runtime.Version()))
+
+	_, err = runVersionCmd(&fakeDiscovery{}, "-o", "wide")
+	assert.Error(t, err, "invalid output format 'wide', must be one of: yaml, json")
 }
 
-func runVersionCmd() (string, error) {
-	versionCmd := NewVersionCommand()
+func runVersionCmd(discoveryClient discovery.ServerResourcesInterface, args ...string) (string, error) {
+	versionCmd := NewVersionCommand(&KameletPluginParams{
+		NewDiscoveryClient: func() (discovery.ServerResourcesInterface, error) {
+			return discoveryClient, nil
+		},
+	})
 
 	output := new(bytes.Buffer)
 	versionCmd.SetOut(output)
+	versionCmd.SetErr(new(bytes.Buffer))
+	versionCmd.SetArgs(args)
 	err := versionCmd.Execute()
 	return output.String(), err
 }
@@ -0,0 +1,200 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// readinessFunc evaluates a watched object and reports whether it is ready,
+// together with the reason when it is not
+type readinessFunc func(obj runtime.Object) (ready bool, reason string)
+
+// waitUntilReady consumes events from given watcher until the readiness function reports the watched object as ready.
+// The onChange callback is invoked for every observed object. An error holding the last seen reason is returned when
+// the timeout elapses before the object got ready, ErrInterrupted is returned when given context gets cancelled.
+// The watcher is stopped in any case, which closes its result channel.
+func waitUntilReady(ctx context.Context, watcher watch.Interface, kind string, name string, timeout time.Duration,
+	isReady readinessFunc, onChange func(obj runtime.Object) error) error {
+	return waitUntilReadyWithProgress(ctx, watcher, kind, name, timeout, 0, isReady, onChange, nil, readinessWindow{})
+}
+
+// readinessWindow is the time the watched object must stay ready before the wait succeeds, so that readiness
+// flapping during startup is not taken for success. A zero window succeeds as soon as the object is ready.
+type readinessWindow struct {
+	// duration is the time the object must stay ready
+	duration time.Duration
+	// readyAtStart tells whether the object was already ready when the watch started, which starts the window
+	readyAtStart bool
+}
+
+// waitUntilReadyWithProgress works like waitUntilReady and additionally invokes onTick with the time elapsed since
+// the start of the wait every given interval, e.g. to show the progress relative to the timeout. No ticks happen
+// when the interval is not positive. The object must stay ready for given window before the wait succeeds.
+func waitUntilReadyWithProgress(ctx context.Context, watcher watch.Interface, kind string, name string, timeout time.Duration,
+	interval time.Duration, isReady readinessFunc, onChange func(obj runtime.Object) error, onTick func(elapsed time.Duration),
+	window readinessWindow) error {
+	defer watcher.Stop()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	var ticks <-chan time.Time
+	if interval > 0 && onTick != nil {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	// the window restarts whenever the object is seen not ready
+	var stableTimer *time.Timer
+	var stable <-chan time.Time
+	startWindow := func() {
+		if stableTimer == nil {
+			stableTimer = time.NewTimer(window.duration)
+			stable = stableTimer.C
+		}
+	}
+	stopWindow := func() {
+		if stableTimer != nil {
+			stableTimer.Stop()
+			stableTimer, stable = nil, nil
+		}
+	}
+	defer stopWindow()
+	if window.readyAtStart && window.duration > 0 {
+		startWindow()
+	}
+
+	lastReason := ""
+	for {
+		select {
+		case <-ticks:
+			onTick(time.Since(start))
+		case <-stable:
+			return nil
+		case <-ctx.Done():
+			if ctx.Err() == context.Canceled {
+				return fmt.Errorf("%w while waiting for %s %s to become ready", ErrInterrupted, kind, name)
+			}
+			if stableTimer != nil {
+				return fmt.Errorf("timeout after %s waiting for %s %s to stay ready for %s", timeout, kind, name, window.duration)
+			}
+			if lastReason == "" {
+				return fmt.Errorf("timeout after %s waiting for %s %s to become ready", timeout, kind, name)
+			}
+			return fmt.Errorf("timeout after %s waiting for %s %s to become ready: %s", timeout, kind, name, lastReason)
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return fmt.Errorf("watch for %s %s closed unexpectedly", kind, name)
+			}
+			switch event.Type {
+			case watch.Error:
+				return apierrors.FromObject(event.Object)
+			case watch.Deleted:
+				return fmt.Errorf("%s %s has been deleted", kind, name)
+			case watch.Added, watch.Modified:
+				if onChange != nil {
+					if err := onChange(event.Object); err != nil {
+						return err
+					}
+				}
+				ready, reason := isReady(event.Object)
+				if ready && window.duration <= 0 {
+					return nil
+				}
+				if ready {
+					startWindow()
+					continue
+				}
+				stopWindow()
+				lastReason = reason
+			}
+		}
+	}
+}
+
+// validateWaitWindow checks that given readiness window is not negative and shorter than the timeout of the wait
+func validateWaitWindow(window time.Duration, timeout time.Duration) error {
+	if window < 0 {
+		return fmt.Errorf("invalid --wait-window '%s', must not be negative", window)
+	}
+	if window > 0 && window >= timeout {
+		return fmt.Errorf("--wait-window %s must be shorter than --timeout %s", window, timeout)
+	}
+	return nil
+}
+
+// waitUntilDeleted consumes events from given watcher until the watched object has been deleted. Like waitUntilReady
+// ErrInterrupted is returned when given context gets cancelled.
+func waitUntilDeleted(ctx context.Context, watcher watch.Interface, kind string, name string, timeout time.Duration) error {
+	defer watcher.Stop()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.Canceled {
+				return fmt.Errorf("%w while waiting for %s %s to be deleted", ErrInterrupted, kind, name)
+			}
+			return fmt.Errorf("timeout after %s waiting for %s %s to be deleted", timeout, kind, name)
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return fmt.Errorf("watch for %s %s closed unexpectedly", kind, name)
+			}
+			switch event.Type {
+			case watch.Error:
+				return apierrors.FromObject(event.Object)
+			case watch.Deleted:
+				return nil
+			}
+		}
+	}
+}
+
+// isTerminal returns true if given writer is connected to a terminal
+func isTerminal(out io.Writer) bool {
+	return isCharDevice(out)
+}
+
+// isCharDevice returns true if given stream is a file referring to a character device like a terminal
+func isCharDevice(stream interface{}) bool {
+	f, ok := stream.(*os.File)
+	if !ok {
+		return false
+	}
+	stat, err := f.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// clearLines moves the cursor given number of lines up and clears the screen from there on
+func clearLines(out io.Writer, lines int) {
+	if lines > 0 {
+		fmt.Fprintf(out, "\033[%dA\033[J", lines)
+	}
+}
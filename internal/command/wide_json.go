@@ -0,0 +1,88 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+)
+
+// wideJSONFormat is the output format printing the Kamelets as JSON along with fields computed from them
+const wideJSONFormat = "wide-json"
+
+// kameletWithComputedFields is the wide-json envelope of a Kamelet
+type kameletWithComputedFields struct {
+	// Kamelet is the unmodified Kamelet object
+	Kamelet *v1alpha1.Kamelet `json:"kamelet"`
+	// Computed holds the convenience fields derived from the Kamelet
+	Computed computedKameletFields `json:"computed"`
+}
+
+// computedKameletFields are the fields derived from a Kamelet the way the plugin does it. The deprecation is only
+// reported here, -o json and -o yaml print the Kamelet unmodified with its support level and deprecation annotations.
+type computedKameletFields struct {
+	IsSource              bool   `json:"isSource"`
+	ProviderName          string `json:"providerName"`
+	RequiredPropertyCount int    `json:"requiredPropertyCount"`
+	Deprecated            bool   `json:"deprecated"`
+	ReplacedBy            string `json:"replacedBy,omitempty"`
+}
+
+// newKameletWithComputedFields wraps given Kamelet into the wide-json envelope, the type meta typed clients do not
+// populate is set on the wrapped copy
+func newKameletWithComputedFields(kamelet *v1alpha1.Kamelet) kameletWithComputedFields {
+	kamelet = kamelet.DeepCopy()
+	kamelet.SetGroupVersionKind(v1alpha1.SchemeGroupVersion.WithKind(v1alpha1.KameletKind))
+	deprecated, replacedBy := isKameletDeprecated(kamelet), ""
+	if deprecated {
+		replacedBy = kamelet.Annotations[kameletReplacedByAnnotation]
+	}
+	return kameletWithComputedFields{
+		Kamelet: kamelet,
+		Computed: computedKameletFields{
+			IsSource:              isKameletType(kamelet, kameletTypeSource),
+			ProviderName:          extractKameletProvider(kamelet),
+			RequiredPropertyCount: requiredPropertyCount(kamelet),
+			Deprecated:            deprecated,
+			ReplacedBy:            replacedBy,
+		},
+	}
+}
+
+// writeKameletsWideJSON prints given Kamelets in the wide-json envelope, as JSON array if asList is set and as
+// single object otherwise
+func writeKameletsWideJSON(out io.Writer, kamelets []*v1alpha1.Kamelet, asList bool) error {
+	var value interface{}
+	if asList {
+		wrapped := make([]kameletWithComputedFields, 0, len(kamelets))
+		for _, kamelet := range kamelets {
+			wrapped = append(wrapped, newKameletWithComputedFields(kamelet))
+		}
+		value = wrapped
+	} else {
+		value = newKameletWithComputedFields(kamelets[0])
+	}
+	data, err := json.MarshalIndent(value, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(out, string(data))
+	return err
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"encoding/json"
+	"testing"
+
+	camelkapis "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestDescribeTypeWideJSON(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	kamelet.Annotations = map[string]string{kameletProviderAnnotation: "Apache Software Foundation"}
+	addKameletProperty(kamelet, "message", "string", "The message to send", true)
+	addKameletProperty(kamelet, "period", "integer", "The interval", true)
+	addKameletProperty(kamelet, "format", "string", "The format", false)
+	recorder.Get(kamelet, nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1", "-o", "wide-json")
+	assert.NilError(t, err)
+	var wrapped kameletWithComputedFields
+	assert.NilError(t, json.Unmarshal([]byte(output), &wrapped))
+	assert.DeepEqual(t, wrapped.Computed, computedKameletFields{
+		IsSource:              true,
+		ProviderName:          "Apache Software Foundation",
+		RequiredPropertyCount: 2,
+	})
+	// the wrapped Kamelet is the unmodified object
+	assert.Equal(t, wrapped.Kamelet.Kind, camelkapis.KameletKind)
+	assert.Equal(t, wrapped.Kamelet.Name, "k1")
+	assert.Equal(t, len(wrapped.Kamelet.Spec.Definition.Properties), 3)
+	assert.Equal(t, wrapped.Kamelet.Status.Phase, camelkapis.KameletPhaseReady)
+
+	recorder.Validate()
+}
+
+func TestDescribeTypeWideJSONDeprecated(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	kamelet.Annotations = map[string]string{
+		kameletSupportLevelAnnotation: kameletSupportLevelDeprecated,
+		kameletReplacedByAnnotation:   "k2",
+	}
+	recorder.Get(kamelet, nil)
+
+	output, err := runDescribeTypeCmd(mockClient, "k1", "-o", "wide-json")
+	assert.NilError(t, err)
+	var wrapped kameletWithComputedFields
+	assert.NilError(t, json.Unmarshal([]byte(output), &wrapped))
+	assert.Assert(t, wrapped.Computed.Deprecated)
+	assert.Equal(t, wrapped.Computed.ReplacedBy, "k2")
+	// the wrapped Kamelet keeps its annotations unmodified
+	assert.DeepEqual(t, wrapped.Kamelet.Annotations, kamelet.Annotations)
+
+	recorder.Validate()
+}
+
+func TestListTypesWideJSON(t *testing.T) {
+	mockClient := client.NewMockKameletClient(t)
+	recorder := mockClient.Recorder()
+
+	sink := createKamelet("k2")
+	sink.Labels[kameletTypeLabel] = kameletTypeSink
+	kameletList := &camelkapis.KameletList{Items: []camelkapis.Kamelet{*createKamelet("k1"), *sink}}
+	recorder.List(kameletList, nil)
+	recorder.List(kameletList, nil)
+
+	output, err := runListTypesCmd(mockClient, "-o", "wide-json")
+	assert.NilError(t, err)
+	var wrapped []kameletWithComputedFields
+	assert.NilError(t, json.Unmarshal([]byte(output), &wrapped))
+	assert.Equal(t, len(wrapped), 1)
+	assert.Equal(t, wrapped[0].Kamelet.Name, "k1")
+	assert.Assert(t, wrapped[0].Computed.IsSource)
+
+	output, err = runListTypesCmd(mockClient, "--type", "sink", "-o", "wide-json")
+	assert.NilError(t, err)
+	wrapped = nil
+	assert.NilError(t, json.Unmarshal([]byte(output), &wrapped))
+	assert.Equal(t, len(wrapped), 1)
+	assert.Equal(t, wrapped[0].Kamelet.Name, "k2")
+	assert.Assert(t, !wrapped[0].Computed.IsSource)
+	assert.Equal(t, wrapped[0].Computed.ProviderName, "")
+	assert.Equal(t, wrapped[0].Computed.RequiredPropertyCount, 0)
+
+	recorder.Validate()
+}
@@ -27,7 +27,7 @@ func NewSourceKameletCommand() *cobra.Command {
 	var rootCmd = &cobra.Command{
 		Use:   "kn-source-kamelet",
 		Short: "Knative eventing Kamelet source plugin",
-		Long:  `Plugin manages Kamelets and KameletBindings as Knative eventing sources.`,
+		Long:  "Plugin manages Kamelets and KameletBindings as Knative eventing sources.\n\n" + command.ExitCodesUsage,
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -38,9 +38,43 @@ func NewSourceKameletCommand() *cobra.Command {
 	}
 	p.Initialize()
 
+	p.AddKubeConfigFlags(rootCmd.PersistentFlags())
+	rootCmd.PersistentFlags().DurationVar(&p.RequestTimeout, "request-timeout", command.DefaultRequestTimeout,
+		"Maximum time a single request to the cluster may take. Requests failing with transient errors are retried.")
+	rootCmd.PersistentFlags().StringVar(&p.CacheDir, "cache-dir", "", "Directory of the local caches. Defaults to the "+
+		"KUBECACHEDIR environment variable or ~/.kube/cache for the API discovery cache shared with kubectl and to the "+
+		"user cache directory for the Kamelet cache of list-types --cached.")
+	rootCmd.PersistentFlags().BoolVarP(&p.Quiet, "quiet", "q", false, "Suppress informational and progress messages like "+
+		"the confirmation of a created binding. Errors, warnings and the output requested with --output are still printed. "+
+		"Takes precedence over --verbose for these messages, the details --verbose adds to the output of describe-type are printed anyway.")
+
 	rootCmd.AddCommand(command.NewListTypesCommand(p))
 	rootCmd.AddCommand(command.NewDescribeTypeCommand(p))
-	rootCmd.AddCommand(command.NewVersionCommand())
+	rootCmd.AddCommand(command.NewBindCommand(p))
+	rootCmd.AddCommand(command.NewUpdateCommand(p))
+	rootCmd.AddCommand(command.NewDeleteCommand(p))
+	rootCmd.AddCommand(command.NewVerifyCommand(p))
+	rootCmd.AddCommand(command.NewCloneCommand(p))
+	rootCmd.AddCommand(command.NewVersionCommand(p))
+	rootCmd.AddCommand(command.NewCompletionCommand())
 
+	cancelOnInterrupt(rootCmd, p)
 	return rootCmd
 }
+
+// cancelOnInterrupt installs the signal handling cancelling the plugin context for the time the commands of given
+// tree run, so that kn executing the plugin in its own process keeps its signal handling before and after
+func cancelOnInterrupt(cmd *cobra.Command, p *command.KameletPluginParams) {
+	for _, subCmd := range cmd.Commands() {
+		cancelOnInterrupt(subCmd, p)
+	}
+	runE := cmd.RunE
+	if runE == nil {
+		return
+	}
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		stop := p.CancelOnInterrupt()
+		defer stop()
+		return runE(cmd, args)
+	}
+}
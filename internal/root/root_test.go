@@ -0,0 +1,63 @@
+// Copyright © 2021 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package root
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"knative.dev/kn-plugin-source-kamelet/internal/command"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCancelOnInterrupt(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sending interrupts is not supported on Windows")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p := &command.KameletPluginParams{Context: ctx, ContextCancel: cancel}
+
+	rootCmd := &cobra.Command{Use: "kn-source-kamelet"}
+	rootCmd.AddCommand(&cobra.Command{
+		Use: "wait",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			process, err := os.FindProcess(os.Getpid())
+			if err != nil {
+				return err
+			}
+			if err := process.Signal(os.Interrupt); err != nil {
+				return err
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(5 * time.Second):
+				return nil
+			}
+		},
+	})
+	cancelOnInterrupt(rootCmd, p)
+
+	// the signal is handled while the command runs
+	rootCmd.SetArgs([]string{"wait"})
+	err := rootCmd.Execute()
+	assert.Assert(t, command.IsInterrupted(err), "context has not been cancelled on interrupt: %v", err)
+}